@@ -0,0 +1,62 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"order-system/constants"
+	"order-system/utils"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// defaultJSONBodyLimit caps JSON and other non-upload request bodies
+	defaultJSONBodyLimit = 1 << 20 // 1MB
+	// defaultUploadBodyLimit caps multipart uploads and is the server-wide hard limit
+	defaultUploadBodyLimit = 10 << 20 // 10MB
+)
+
+// getEnvBytes reads a positive byte count from the environment, falling back to a default
+func getEnvBytes(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	bytes, err := strconv.Atoi(value)
+	if err != nil || bytes <= 0 {
+		log.Printf("Invalid %s value %q, using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return bytes
+}
+
+// bodyTooLarge sends the 413 response used by both the server-wide and per-request limits
+func bodyTooLarge(c *fiber.Ctx) error {
+	return utils.SendError(c, fiber.StatusRequestEntityTooLarge, constants.ErrCodePayloadTooLarge, "Request body too large")
+}
+
+// errorHandler renders oversized bodies rejected by the server as JSON, everything else as Fiber does
+func errorHandler(c *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && fiberErr.Code == fiber.StatusRequestEntityTooLarge {
+		return bodyTooLarge(c)
+	}
+	return fiber.DefaultErrorHandler(c, err)
+}
+
+// newBodyLimitMiddleware rejects non-multipart bodies above limit. Multipart uploads are
+// only bound by the server BodyLimit, which is set to the (higher) upload limit.
+func newBodyLimitMiddleware(limit int) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if strings.HasPrefix(c.Get(fiber.HeaderContentType), fiber.MIMEMultipartForm) {
+			return c.Next()
+		}
+		if c.Request().Header.ContentLength() > limit || len(c.Body()) > limit {
+			return bodyTooLarge(c)
+		}
+		return c.Next()
+	}
+}
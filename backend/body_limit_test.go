@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func newBodyLimitTestApp(jsonLimit, uploadLimit int) *fiber.App {
+	app := fiber.New(fiber.Config{BodyLimit: uploadLimit, ErrorHandler: errorHandler})
+	app.Use(newBodyLimitMiddleware(jsonLimit))
+	app.Post("/echo", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	return app
+}
+
+// multipartRequest builds an upload request carrying a single file of the given size
+func multipartRequest(t *testing.T, size int) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", "image.png")
+	if err != nil {
+		t.Fatalf("failed to create form file: %v", err)
+	}
+	part.Write(bytes.Repeat([]byte("x"), size))
+	writer.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/echo", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestBodyLimitRejectsLargeJSON(t *testing.T) {
+	app := newBodyLimitTestApp(16, 64)
+
+	small := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"a":1}`))
+	small.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(small, -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	large := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(`{"a":"`+strings.Repeat("x", 32)+`"}`))
+	large.Header.Set("Content-Type", "application/json")
+	resp, err = app.Test(large, -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status 413, got %d", resp.StatusCode)
+	}
+}
+
+func TestBodyLimitAllowsLargerUploads(t *testing.T) {
+	app := newBodyLimitTestApp(16, 1024)
+
+	resp, err := app.Test(multipartRequest(t, 64), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,41 @@
+package constants
+
+// Audit log actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Audit log entities
+const (
+	AuditEntityRestaurant   = "restaurant"
+	AuditEntitySettings     = "settings"
+	AuditEntityTable        = "table"
+	AuditEntityMenuItem     = "menu_item"
+	AuditEntityMenuCategory = "menu_category"
+	AuditEntityOrder        = "order"
+	AuditEntityPayment      = "payment"
+	AuditEntityOrderGroup   = "order_group"
+	AuditEntityUser         = "user"
+)
+
+// AuditActions lists every audit log action
+var AuditActions = []string{
+	AuditActionCreate,
+	AuditActionUpdate,
+	AuditActionDelete,
+}
+
+// AuditEntities lists every audit log entity
+var AuditEntities = []string{
+	AuditEntityRestaurant,
+	AuditEntitySettings,
+	AuditEntityTable,
+	AuditEntityMenuItem,
+	AuditEntityMenuCategory,
+	AuditEntityOrder,
+	AuditEntityPayment,
+	AuditEntityOrderGroup,
+	AuditEntityUser,
+}
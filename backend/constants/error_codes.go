@@ -0,0 +1,54 @@
+package constants
+
+// Machine-readable error codes sent in the "code" field of error responses. Clients should
+// branch on these rather than on the human-readable "error" message, which may change.
+const (
+	// Request validation
+	ErrCodeInvalidInput = "INVALID_INPUT" // the body or a path parameter failed validation
+	ErrCodeInvalidQuery = "INVALID_QUERY" // a query parameter (filter, sort, page, date range) is invalid
+
+	// Authentication and authorization
+	ErrCodeUnauthorized            = "UNAUTHORIZED"              // missing, invalid or expired token
+	ErrCodeInvalidCredentials      = "INVALID_CREDENTIALS"       // wrong username or password
+	ErrCodeAccountLocked           = "ACCOUNT_LOCKED"            // too many failed logins for the account
+	ErrCodeInvalidVerificationCode = "INVALID_VERIFICATION_CODE" // wrong, expired or used-up phone verification code
+	ErrCodeForbidden               = "FORBIDDEN"                 // authenticated but lacking the required role
+	ErrCodeRateLimited             = "RATE_LIMITED"              // too many requests from the client
+
+	// Missing resources; restaurants owned by someone else are reported as not found too
+	ErrCodeRestaurantNotFound = "RESTAURANT_NOT_FOUND"
+	ErrCodeTableNotFound      = "TABLE_NOT_FOUND"
+	ErrCodeMenuItemNotFound   = "MENU_ITEM_NOT_FOUND"
+	ErrCodeCategoryNotFound   = "CATEGORY_NOT_FOUND"
+	ErrCodeOrderNotFound      = "ORDER_NOT_FOUND"
+	ErrCodeOrderItemNotFound  = "ORDER_ITEM_NOT_FOUND"
+	ErrCodeOrderGroupNotFound = "ORDER_GROUP_NOT_FOUND"
+	ErrCodeUserNotFound       = "USER_NOT_FOUND"
+
+	// Conflicts with existing data
+	ErrCodeDuplicateValue  = "DUPLICATE_VALUE" // a unique value other than the ones below is taken
+	ErrCodeUsernameTaken   = "USERNAME_TAKEN"
+	ErrCodeEmailTaken      = "EMAIL_TAKEN"
+	ErrCodeSKUInUse        = "SKU_IN_USE"
+	ErrCodeSettingsExist   = "SETTINGS_EXIST"
+	ErrCodeCategoryExists  = "CATEGORY_EXISTS"
+	ErrCodeVersionConflict = "VERSION_CONFLICT" // the resource changed since the client loaded it
+	ErrCodeMenuItemInUse   = "MENU_ITEM_IN_USE" // referenced by active orders
+	ErrCodeLastAdmin       = "LAST_ADMIN"       // the change would leave no admin
+
+	// Business rules
+	ErrCodeInsufficientStock       = "INSUFFICIENT_STOCK"
+	ErrCodeFeaturedLimitReached    = "FEATURED_LIMIT_REACHED"
+	ErrCodeOrderNotOpen            = "ORDER_NOT_OPEN" // the order is completed or cancelled
+	ErrCodeOrderAlreadyMerged      = "ORDER_ALREADY_MERGED"
+	ErrCodePaymentExceedsBalance   = "PAYMENT_EXCEEDS_BALANCE"   // more than the unpaid part of the order
+	ErrCodeInvalidStatusTransition = "INVALID_STATUS_TRANSITION" // the order can't move to that status from its current one
+	ErrCodeNothingToReorder        = "NOTHING_TO_REORDER"        // none of a previous order's items can be ordered again
+	ErrCodeBelowMinimumOrder       = "BELOW_MINIMUM_ORDER"       // the items total is under the restaurant's minimum for the order type
+	ErrCodeKitchenBusy             = "KITCHEN_BUSY"              // the restaurant has as many active orders as its kitchen takes
+
+	// Server side
+	ErrCodeInternal        = "INTERNAL_ERROR"
+	ErrCodeRequestTimeout  = "REQUEST_TIMEOUT"
+	ErrCodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+)
@@ -0,0 +1,4 @@
+package constants
+
+// DefaultOrderRefPrefix starts order references of restaurants that haven't chosen a prefix
+const DefaultOrderRefPrefix = "A"
@@ -2,18 +2,64 @@ package constants
 
 // Order statuses for internal use
 const (
-	OrderStatusPending   = "pending"
-	OrderStatusConfirmed = "confirmed"
-	OrderStatusPreparing = "preparing"
-	OrderStatusReady     = "ready"
-	OrderStatusDelivered = "delivered"
-	OrderStatusCompleted = "completed"
-	OrderStatusCancelled = "cancelled"
+	OrderStatusScheduled      = "scheduled" // placed for later; moved to pending shortly before it is due
+	OrderStatusPending        = "pending"
+	OrderStatusConfirmed      = "confirmed"
+	OrderStatusPreparing      = "preparing"
+	OrderStatusReady          = "ready"
+	OrderStatusOutForDelivery = "out_for_delivery" // delivery orders only, on the way to the customer
+	OrderStatusDelivered      = "delivered"
+	OrderStatusCompleted      = "completed"
+	OrderStatusCancelled      = "cancelled"
 )
 
+// Order item statuses, set per item by the kitchen
+const (
+	OrderItemStatusPending = "pending"
+	OrderItemStatusReady   = "ready"
+)
+
+// KitchenOrderStatuses lists the internal statuses of orders the kitchen is still preparing, whose
+// items can be marked ready one by one
+var KitchenOrderStatuses = []string{
+	OrderStatusPending,
+	OrderStatusConfirmed,
+	OrderStatusPreparing,
+}
+
+// ActiveOrderStatuses lists the internal statuses of orders still being worked on
+var ActiveOrderStatuses = []string{
+	OrderStatusPending,
+	OrderStatusConfirmed,
+	OrderStatusPreparing,
+	OrderStatusReady,
+	OrderStatusOutForDelivery,
+}
+
+// OpenOrderStatuses lists the internal statuses of orders not yet finished: the active ones and
+// scheduled orders the kitchen hasn't been given yet
+var OpenOrderStatuses = append([]string{OrderStatusScheduled}, ActiveOrderStatuses...)
+
+// OrderStatusTransitions lists the statuses an order may move to from each status. Orders only move
+// forward, may skip steps, and can be cancelled until they are paid; completed and cancelled are final.
+// Scheduled orders can be started early or cancelled.
+// Only delivery orders may go out for delivery.
+var OrderStatusTransitions = map[string][]string{
+	OrderStatusScheduled:      {OrderStatusPending, OrderStatusCancelled},
+	OrderStatusPending:        {OrderStatusConfirmed, OrderStatusPreparing, OrderStatusReady, OrderStatusOutForDelivery, OrderStatusDelivered, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusConfirmed:      {OrderStatusPreparing, OrderStatusReady, OrderStatusOutForDelivery, OrderStatusDelivered, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusPreparing:      {OrderStatusReady, OrderStatusOutForDelivery, OrderStatusDelivered, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusReady:          {OrderStatusOutForDelivery, OrderStatusDelivered, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusOutForDelivery: {OrderStatusDelivered, OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusDelivered:      {OrderStatusCompleted, OrderStatusCancelled},
+	OrderStatusCompleted:      {},
+	OrderStatusCancelled:      {},
+}
+
 // Simplified frontend order statuses
 const (
 	FrontendOrderStatusActive   = "active"
+	FrontendOrderStatusOutForDelivery = "out_for_delivery"
 	FrontendOrderStatusDelivered = "delivered"
 	FrontendOrderStatusPaid     = "paid"
 )
\ No newline at end of file
@@ -0,0 +1,15 @@
+package constants
+
+// How an order reaches the customer
+const (
+	OrderTypeDineIn   = "dine_in"
+	OrderTypeTakeaway = "takeaway"
+	OrderTypeDelivery = "delivery" // requires a delivery address
+)
+
+// OrderTypes lists every accepted order type
+var OrderTypes = []string{
+	OrderTypeDineIn,
+	OrderTypeTakeaway,
+	OrderTypeDelivery,
+}
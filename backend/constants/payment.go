@@ -0,0 +1,24 @@
+package constants
+
+// Payment methods accepted when recording a payment
+const (
+	PaymentMethodCreditCard   = "credit_card"
+	PaymentMethodMobileWallet = "mobile_wallet"
+	PaymentMethodPaypal       = "paypal"
+	PaymentMethodCash         = "cash"
+)
+
+// PaymentMethods lists every accepted payment method
+var PaymentMethods = []string{
+	PaymentMethodCreditCard,
+	PaymentMethodMobileWallet,
+	PaymentMethodPaypal,
+	PaymentMethodCash,
+}
+
+// Payment statuses
+const (
+	PaymentStatusPending   = "pending"
+	PaymentStatusCompleted = "completed"
+	PaymentStatusFailed    = "failed"
+)
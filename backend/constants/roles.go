@@ -0,0 +1,15 @@
+package constants
+
+// User roles
+const (
+	RoleOwner = "owner"
+	RoleStaff = "staff"
+	RoleAdmin = "admin"
+)
+
+// Roles lists every role an admin can assign. Self-registration always creates owners.
+var Roles = []string{
+	RoleOwner,
+	RoleStaff,
+	RoleAdmin,
+}
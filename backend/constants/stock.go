@@ -0,0 +1,10 @@
+package constants
+
+// Stock movement types recorded in the menu item stock ledger
+const (
+	StockMovementInitial        = "initial"         // stock set when the item was created
+	StockMovementOrder          = "order"           // stock taken by an order
+	StockMovementOrderCancelled = "order_cancelled" // stock returned by a cancelled order
+	StockMovementAdjustment     = "adjustment"      // owner adjustment with a reason
+	StockMovementManual         = "manual"          // quantity overwritten through a menu item update
+)
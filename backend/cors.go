@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultCORSMaxAge is how long (in seconds) browsers may cache preflight responses
+const defaultCORSMaxAge = 86400 // 24 hours
+
+// corsSettings is the effective CORS configuration, loaded from CORS_CONFIG or the environment
+type corsSettings struct {
+	AllowOrigins []string `json:"allow_origins" yaml:"allow_origins"`
+	MaxAge       int      `json:"max_age" yaml:"max_age"`
+	// MethodOrigins replaces AllowOrigins for specific HTTP methods, e.g. wider GET access for previews
+	MethodOrigins map[string][]string `json:"method_origins" yaml:"method_origins"`
+}
+
+// loadCORSSettings reads the CORS config file named by CORS_CONFIG (JSON or YAML),
+// falling back to the comma-separated CORS_ORIGINS and CORS_MAX_AGE environment variables.
+func loadCORSSettings() (corsSettings, error) {
+	var settings corsSettings
+
+	if path := os.Getenv("CORS_CONFIG"); path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return settings, fmt.Errorf("reading CORS config %s: %w", path, err)
+		}
+
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(content, &settings)
+		default:
+			err = json.Unmarshal(content, &settings)
+		}
+		if err != nil {
+			return settings, fmt.Errorf("parsing CORS config %s: %w", path, err)
+		}
+	}
+
+	if len(settings.AllowOrigins) == 0 {
+		if corsOrigins := os.Getenv("CORS_ORIGINS"); corsOrigins != "" {
+			// Split comma-separated origins
+			for _, origin := range strings.Split(corsOrigins, ",") {
+				if origin = strings.TrimSpace(origin); origin != "" {
+					settings.AllowOrigins = append(settings.AllowOrigins, origin)
+				}
+			}
+		} else {
+			// In development, you can set specific origins
+			// For production, always define specific origins
+			settings.AllowOrigins = []string{
+				"http://localhost:5173", // Vite default port
+				"http://localhost:3000", // Common React port
+				"http://localhost:3001", // Alternative port
+			}
+		}
+	}
+
+	if settings.MaxAge == 0 {
+		settings.MaxAge = defaultCORSMaxAge
+		if raw := os.Getenv("CORS_MAX_AGE"); raw != "" {
+			maxAge, err := strconv.Atoi(raw)
+			if err != nil || maxAge < 0 {
+				return settings, fmt.Errorf("invalid CORS_MAX_AGE %q", raw)
+			}
+			settings.MaxAge = maxAge
+		}
+	}
+
+	// Normalize method keys so lookups are case-insensitive
+	methodOrigins := make(map[string][]string, len(settings.MethodOrigins))
+	for method, origins := range settings.MethodOrigins {
+		methodOrigins[strings.ToUpper(method)] = origins
+	}
+	settings.MethodOrigins = methodOrigins
+
+	return settings, settings.validate()
+}
+
+// validate checks every configured origin is a well-formed scheme://host[:port] URL.
+// Credentials are always allowed, so a wildcard origin is rejected: browsers refuse
+// credentialed responses with "Access-Control-Allow-Origin: *" and fail silently.
+func (s corsSettings) validate() error {
+	if s.MaxAge < 0 {
+		return fmt.Errorf("CORS max_age must not be negative")
+	}
+	for _, origin := range s.AllowOrigins {
+		if err := validateOrigin(origin); err != nil {
+			return err
+		}
+	}
+	for method, origins := range s.MethodOrigins {
+		if len(origins) == 0 {
+			return fmt.Errorf("CORS method override for %s has no origins", method)
+		}
+		for _, origin := range origins {
+			if err := validateOrigin(origin); err != nil {
+				return fmt.Errorf("%s override: %w", method, err)
+			}
+		}
+	}
+	return nil
+}
+
+// validateOrigin accepts an http(s) origin without path, query or fragment
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return fmt.Errorf("wildcard CORS origin %q cannot be used with credentials, list the allowed origins explicitly", origin)
+	}
+	parsed, err := url.Parse(origin)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+		(parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("invalid CORS origin %q, expected scheme://host[:port]", origin)
+	}
+	return nil
+}
+
+// corsConfig builds the Fiber CORS config for a list of origins
+func (s corsSettings) corsConfig(origins []string) cors.Config {
+	return cors.Config{
+		AllowOrigins:     strings.Join(origins, ","),
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS, PATCH",
+		AllowCredentials: true, // Enable credentials for WebSocket auth
+		ExposeHeaders:    "Content-Length",
+		MaxAge:           s.MaxAge,
+	}
+}
+
+// newCORSMiddleware returns a CORS handler that applies per-method origin overrides.
+// Preflight requests are matched on the method they announce in Access-Control-Request-Method.
+func newCORSMiddleware(settings corsSettings) fiber.Handler {
+	defaultHandler := cors.New(settings.corsConfig(settings.AllowOrigins))
+
+	overrides := make(map[string]fiber.Handler, len(settings.MethodOrigins))
+	for method, origins := range settings.MethodOrigins {
+		overrides[method] = cors.New(settings.corsConfig(origins))
+	}
+
+	return func(c *fiber.Ctx) error {
+		method := c.Method()
+		if method == fiber.MethodOptions {
+			if requested := c.Get(fiber.HeaderAccessControlRequestMethod); requested != "" {
+				method = strings.ToUpper(requested)
+			}
+		}
+		if handler, ok := overrides[method]; ok {
+			return handler(c)
+		}
+		return defaultHandler(c)
+	}
+}
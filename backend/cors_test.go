@@ -0,0 +1,118 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestValidateOrigin(t *testing.T) {
+	valid := []string{"http://localhost:5173", "https://app.example.com", "https://preview.example.com/"}
+	for _, origin := range valid {
+		if err := validateOrigin(origin); err != nil {
+			t.Fatalf("expected %q to be valid, got %v", origin, err)
+		}
+	}
+
+	invalid := []string{"localhost:5173", "ftp://example.com", "https://example.com/app", "https://", "https://example.com?x=1", "*"}
+	for _, origin := range invalid {
+		if err := validateOrigin(origin); err == nil {
+			t.Fatalf("expected %q to be rejected", origin)
+		}
+	}
+}
+
+func TestLoadCORSSettingsFromYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cors.yaml")
+	config := "allow_origins:\n  - https://app.example.com\nmax_age: 600\nmethod_origins:\n  get:\n    - https://preview.example.com\n"
+	if err := os.WriteFile(path, []byte(config), 0o600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("CORS_CONFIG", path)
+	t.Setenv("CORS_ORIGINS", "https://ignored.example.com")
+
+	settings, err := loadCORSSettings()
+	if err != nil {
+		t.Fatalf("loadCORSSettings returned error: %v", err)
+	}
+	if len(settings.AllowOrigins) != 1 || settings.AllowOrigins[0] != "https://app.example.com" {
+		t.Fatalf("unexpected origins: %v", settings.AllowOrigins)
+	}
+	if settings.MaxAge != 600 {
+		t.Fatalf("expected max age 600, got %d", settings.MaxAge)
+	}
+	if origins := settings.MethodOrigins["GET"]; len(origins) != 1 || origins[0] != "https://preview.example.com" {
+		t.Fatalf("expected GET override, got %v", settings.MethodOrigins)
+	}
+}
+
+func TestLoadCORSSettingsRejectsWildcard(t *testing.T) {
+	t.Setenv("CORS_CONFIG", "")
+	t.Setenv("CORS_MAX_AGE", "")
+	t.Setenv("CORS_ORIGINS", "https://a.example.com,*")
+
+	if _, err := loadCORSSettings(); err == nil {
+		t.Fatal("expected wildcard origin to be rejected while credentials are enabled")
+	}
+}
+
+func TestLoadCORSSettingsFromEnv(t *testing.T) {
+	t.Setenv("CORS_CONFIG", "")
+	t.Setenv("CORS_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("CORS_MAX_AGE", "120")
+
+	settings, err := loadCORSSettings()
+	if err != nil {
+		t.Fatalf("loadCORSSettings returned error: %v", err)
+	}
+	if len(settings.AllowOrigins) != 2 || settings.AllowOrigins[1] != "https://b.example.com" {
+		t.Fatalf("unexpected origins: %v", settings.AllowOrigins)
+	}
+	if settings.MaxAge != 120 {
+		t.Fatalf("expected max age 120, got %d", settings.MaxAge)
+	}
+
+	t.Setenv("CORS_ORIGINS", "not-a-url")
+	if _, err := loadCORSSettings(); err == nil {
+		t.Fatal("expected malformed origin to be rejected")
+	}
+}
+
+func TestCORSMiddlewareMethodOverride(t *testing.T) {
+	app := fiber.New()
+	app.Use(newCORSMiddleware(corsSettings{
+		AllowOrigins:  []string{"https://app.example.com"},
+		MaxAge:        60,
+		MethodOrigins: map[string][]string{"GET": {"https://preview.example.com"}},
+	}))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Post("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	tests := []struct {
+		method  string
+		origin  string
+		allowed bool
+	}{
+		{http.MethodGet, "https://preview.example.com", true},
+		{http.MethodGet, "https://app.example.com", false},
+		{http.MethodPost, "https://app.example.com", true},
+		{http.MethodPost, "https://preview.example.com", false},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, "/", nil)
+		req.Header.Set("Origin", tt.origin)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		got := resp.Header.Get("Access-Control-Allow-Origin") == tt.origin
+		if got != tt.allowed {
+			t.Fatalf("%s from %s: expected allowed=%v, got header %q", tt.method, tt.origin, tt.allowed, resp.Header.Get("Access-Control-Allow-Origin"))
+		}
+	}
+}
@@ -1,11 +1,13 @@
 package database
 
 import (
+	"fmt"
 	"log"
-	"order-system/models"
 	"os"
+	"strings"
 
 	"gorm.io/driver/postgres"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
 	"github.com/joho/godotenv"
@@ -13,26 +15,109 @@ import (
 
 var DB *gorm.DB
 
+// readDB is the read replica from DATABASE_READ_URL, nil when reads go to DB
+var readDB *gorm.DB
+
+// Supported values of DB_DRIVER
+const (
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+)
+
+// defaultSQLitePath is used when DB_DRIVER=sqlite and DATABASE_URL is empty
+const defaultSQLitePath = "order_system.db"
+
+// ConnectDB connects to the database configured in the environment and applies pending migrations
 func ConnectDB() {
+	Connect()
+	if err := Migrate(); err != nil {
+		log.Fatal("failed to migrate database:", err)
+	}
+}
+
+// Connect connects to the database configured in the environment without migrating it
+func Connect() {
 	var err error
 
-	// Load environment variables from .env file
-	err = godotenv.Load()
-	if err != nil {
-		log.Fatal("Error loading .env file")
+	// Load environment variables from .env file if there is one; containers and CI set them directly
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found, using system environment variables")
 	}
 
 	var dbURL = os.Getenv("DATABASE_URL")
-	DB, err = gorm.Open(postgres.Open(dbURL), &gorm.Config{})
+	var driver = os.Getenv("DB_DRIVER")
+	if dbURL == "" && !strings.EqualFold(driver, DriverSQLite) {
+		log.Fatal("DATABASE_URL is not set")
+	}
+	DB, err = Open(driver, dbURL)
 	if err != nil {
 		log.Fatal("failed to connect database:", err)
 	}
 
-	// Auto create tables
-	err = DB.AutoMigrate(&models.User{}, &models.Restaurant{}, &models.Table{}, &models.MenuItem{}, &models.Order{}, &models.OrderItem{}, &models.Payment{})
+	readDB = nil
+	if readURL := os.Getenv("DATABASE_READ_URL"); readURL != "" {
+		readDB, err = Open(driver, readURL)
+		if err != nil {
+			log.Fatal("failed to connect read database:", err)
+		}
+		log.Println("Serving read-only queries from DATABASE_READ_URL")
+	}
+}
 
-	if err != nil {
-		panic("Failed to migrate database!")
+// ReadDB returns the database for read-only queries that can tolerate replication lag:
+// the replica at DATABASE_READ_URL when one is configured, otherwise DB
+func ReadDB() *gorm.DB {
+	if readDB != nil {
+		return readDB
 	}
+	return DB
+}
+
+// Open connects to the database with the given driver, postgres (the default) or sqlite.
+// For sqlite the dsn is a file path, defaulting to defaultSQLitePath.
+func Open(driver, dsn string) (*gorm.DB, error) {
+	switch strings.ToLower(driver) {
+	case "", DriverPostgres:
+		return gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	case DriverSQLite:
+		if dsn == "" {
+			dsn = defaultSQLitePath
+		}
+		return gorm.Open(sqlite.Open(sqliteDSN(dsn)), &gorm.Config{})
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q, expected %s or %s", driver, DriverPostgres, DriverSQLite)
+	}
+}
 
+// sqliteDSN adds the connection options the app relies on unless dsn already sets them:
+// enforced foreign keys like Postgres, waiting for locks instead of failing, and write locks
+// taken when a transaction begins so read-then-write transactions can't fail halfway
+func sqliteDSN(dsn string) string {
+	options := []string{"_foreign_keys=on", "_busy_timeout=5000", "_txlock=immediate"}
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	for _, option := range options {
+		name := option[:strings.Index(option, "=")]
+		if strings.Contains(dsn, name+"=") {
+			continue
+		}
+		dsn += separator + option
+		separator = "&"
+	}
+	return dsn
+}
+
+// IsSQLite reports whether DB is a SQLite database
+func IsSQLite() bool {
+	return DB.Dialector.Name() == DriverSQLite
+}
+
+// HourOf returns a SQL expression for the hour (0-23) of a timestamp column, for either driver
+func HourOf(column string) string {
+	if IsSQLite() {
+		return fmt.Sprintf("CAST(strftime('%%H', %s) AS INTEGER)", column)
+	}
+	return fmt.Sprintf("CAST(EXTRACT(HOUR FROM %s) AS INTEGER)", column)
 }
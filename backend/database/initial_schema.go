@@ -0,0 +1,178 @@
+package database
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// The structs below are the models as they were when versioned migrations replaced AutoMigrate.
+// Migration 0001 creates its tables from them instead of from package models, so changing a model
+// doesn't change what 0001 builds; later schema changes belong in their own migrations.
+
+type initialUser struct {
+	gorm.Model
+	Username    string              `gorm:"size:255;not null;unique"`
+	Password    string              `gorm:"size:255;not null"`
+	Role        string              `gorm:"size:50;default:'owner'"`
+	Restaurants []initialRestaurant `gorm:"foreignKey:UserID"`
+	Email       string              `gorm:"size:255;not null;unique"`
+}
+
+func (initialUser) TableName() string { return "users" }
+
+type initialRestaurant struct {
+	gorm.Model
+	UserID      uint                       `gorm:"not null"`
+	Name        string                     `gorm:"size:255;not null"`
+	Address     string                     `gorm:"size:255"`
+	PhoneNumber string                     `gorm:"size:50"`
+	LogoURL     string                     `gorm:"size:255"`
+	Tables      []initialTable             `gorm:"foreignKey:RestaurantID"`
+	MenuItems   []initialMenuItem          `gorm:"foreignKey:RestaurantID"`
+	Settings    *initialRestaurantSettings `gorm:"foreignKey:RestaurantID"`
+}
+
+func (initialRestaurant) TableName() string { return "restaurants" }
+
+type initialRestaurantSettings struct {
+	gorm.Model
+	RestaurantID          uint    `gorm:"not null;uniqueIndex"`
+	TaxRate               float64 `gorm:"default:0"`
+	Currency              string  `gorm:"size:3;not null;default:'USD'"`
+	PrepBufferMinutes     int     `gorm:"default:0"`
+	LowStockThreshold     int     `gorm:"not null"`
+	OperatingHoursEnabled bool    `gorm:"default:false"`
+}
+
+func (initialRestaurantSettings) TableName() string { return "restaurant_settings" }
+
+type initialAuditLog struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"not null;index"`
+	RestaurantID uint      `gorm:"not null;index:idx_audit_logs_restaurant_created"`
+	Action       string    `gorm:"size:20;not null"`
+	Entity       string    `gorm:"size:50;not null"`
+	EntityID     uint      `gorm:"not null"`
+	Details      string    `gorm:"type:text"`
+	CreatedAt    time.Time `gorm:"index:idx_audit_logs_restaurant_created"`
+}
+
+func (initialAuditLog) TableName() string { return "audit_logs" }
+
+type initialTable struct {
+	gorm.Model
+	RestaurantID uint           `gorm:"not null"`
+	TableNumber  int            `gorm:"not null"`
+	QRCodeURL    string         `gorm:"size:255"`
+	Orders       []initialOrder `gorm:"foreignKey:TableID"`
+}
+
+func (initialTable) TableName() string { return "tables" }
+
+type initialMenuItem struct {
+	gorm.Model
+	RestaurantID  uint               `gorm:"not null;uniqueIndex:idx_menu_items_restaurant_sku"`
+	SKU           *string            `gorm:"size:100;uniqueIndex:idx_menu_items_restaurant_sku"`
+	Name          string             `gorm:"size:255;not null"`
+	Description   string             `gorm:"type:text"`
+	Price         int64              `gorm:"not null"`
+	Category      string             `gorm:"size:50"`
+	CategoryID    *uint              `gorm:"index"`
+	ImageURL      string             `gorm:"size:255"`
+	Quantity      int                `gorm:"default:0"`
+	DietaryTags   string             `gorm:"type:text;default:'[]'"`
+	Allergens     string             `gorm:"type:text;default:'[]'"`
+	IsFeatured    bool               `gorm:"default:false;index"`
+	FeaturedOrder int                `gorm:"default:0"`
+	Version       int                `gorm:"not null;default:0"`
+	OrderItems    []initialOrderItem `gorm:"foreignKey:MenuItemID"`
+}
+
+func (initialMenuItem) TableName() string { return "menu_items" }
+
+type initialInventoryAdjustment struct {
+	gorm.Model
+	RestaurantID  uint   `gorm:"not null;index"`
+	MenuItemID    uint   `gorm:"not null;index"`
+	UserID        uint   `gorm:"not null"`
+	Delta         int    `gorm:"not null"`
+	AppliedDelta  int    `gorm:"not null"`
+	QuantityAfter int    `gorm:"not null"`
+	Reason        string `gorm:"size:255;not null"`
+}
+
+func (initialInventoryAdjustment) TableName() string { return "inventory_adjustments" }
+
+type initialStockMovement struct {
+	ID            uint   `gorm:"primaryKey"`
+	RestaurantID  uint   `gorm:"not null;index"`
+	MenuItemID    uint   `gorm:"not null;index:idx_stock_movements_item_created"`
+	Type          string `gorm:"size:20;not null"`
+	Delta         int    `gorm:"not null"`
+	QuantityAfter int    `gorm:"not null"`
+	OrderID       *uint  `gorm:"index"`
+	AdjustmentID  *uint
+	Note          string    `gorm:"size:255"`
+	CreatedAt     time.Time `gorm:"index:idx_stock_movements_item_created"`
+}
+
+func (initialStockMovement) TableName() string { return "stock_movements" }
+
+type initialMenuCategory struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"not null;index"`
+	Name         string `gorm:"size:50;not null"`
+	DisplayOrder int    `gorm:"default:0"`
+}
+
+func (initialMenuCategory) TableName() string { return "menu_categories" }
+
+type initialOrder struct {
+	gorm.Model
+	TableID      uint               `gorm:"not null"`
+	CustomerName string             `gorm:"size:255"`
+	Status       string             `gorm:"size:50;default:'pending'"`
+	TotalAmount  int64              `gorm:"not null"`
+	Discount     int64              `gorm:"not null;default:0"`
+	Tip          int64              `gorm:"not null;default:0"`
+	GroupID      *uint              `gorm:"index"`
+	CreatedAt    time.Time          `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time          `gorm:"autoUpdateTime"`
+	Table        *initialTable      `gorm:"foreignKey:TableID"`
+	OrderItems   []initialOrderItem `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	Payments     []initialPayment   `gorm:"foreignKey:OrderID"`
+}
+
+func (initialOrder) TableName() string { return "orders" }
+
+type initialOrderGroup struct {
+	gorm.Model
+	RestaurantID uint           `gorm:"not null"`
+	TotalAmount  int64          `gorm:"not null"`
+	Orders       []initialOrder `gorm:"foreignKey:GroupID"`
+}
+
+func (initialOrderGroup) TableName() string { return "order_groups" }
+
+type initialOrderItem struct {
+	gorm.Model
+	OrderID             uint            `gorm:"not null"`
+	MenuItemID          uint            `gorm:"not null"`
+	Quantity            int             `gorm:"default:1"`
+	SpecialInstructions string          `gorm:"type:text"`
+	MenuItem            initialMenuItem `gorm:"foreignKey:MenuItemID;references:ID"`
+}
+
+func (initialOrderItem) TableName() string { return "order_items" }
+
+type initialPayment struct {
+	gorm.Model
+	OrderID       uint      `gorm:"not null"`
+	PaymentMethod string    `gorm:"size:50"`
+	PaymentStatus string    `gorm:"size:50;default:'pending'"`
+	Amount        int64     `gorm:"not null"`
+	PaymentDate   time.Time `gorm:"autoCreateTime"`
+}
+
+func (initialPayment) TableName() string { return "payments" }
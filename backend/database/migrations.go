@@ -0,0 +1,824 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"order-system/constants"
+	"order-system/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// migration is one versioned schema change. Applied versions are recorded in schema_migrations,
+// so each migration runs once per database, inside a transaction together with its record.
+// Migration 0001 is frozen at the schema AutoMigrate built before versioned migrations, see
+// initial_schema.go; later migrations still check for what they add or remove, so they also apply
+// to databases AutoMigrate built back then.
+type migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// schemaMigration is a row of schema_migrations
+type schemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:255;not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// migrationLockID is the Postgres advisory lock key that keeps instances starting at the same
+// time from applying a migration twice
+const migrationLockID = 7_420_001
+
+// migrations lists every schema change in version order. Append new ones at the end.
+var migrations = []migration{
+	{Version: 1, Name: "initial_schema", Up: migrateInitialSchema, Down: dropInitialSchema},
+	{Version: 2, Name: "users_unique_among_undeleted", Up: migrateUserUniqueIndexes, Down: revertUserUniqueIndexes},
+	{Version: 3, Name: "order_item_snapshots", Up: migrateOrderItemSnapshots, Down: dropOrderItemSnapshots},
+	{Version: 4, Name: "audit_log_entity_index", Up: createAuditLogEntityIndex, Down: dropAuditLogEntityIndex},
+	{Version: 5, Name: "order_refs", Up: migrateOrderRefs, Down: dropOrderRefs},
+	{Version: 6, Name: "order_contact", Up: migrateOrderContact, Down: dropOrderContact},
+	{Version: 7, Name: "orders_without_table", Up: migrateOrdersWithoutTable, Down: revertOrdersWithoutTable},
+	{Version: 8, Name: "orders_restaurant_foreign_key", Up: createOrderRestaurantForeignKey, Down: dropOrderRestaurantForeignKey},
+	{Version: 9, Name: "min_order_amount", Up: migrateMinOrderAmount, Down: dropMinOrderAmount},
+	{Version: 10, Name: "scheduled_orders", Up: migrateScheduledOrders, Down: dropScheduledOrders},
+	{Version: 11, Name: "max_active_orders", Up: migrateMaxActiveOrders, Down: dropMaxActiveOrders},
+	{Version: 12, Name: "menu_item_station", Up: migrateMenuItemStation, Down: dropMenuItemStation},
+	{Version: 13, Name: "order_item_status", Up: migrateOrderItemStatus, Down: dropOrderItemStatus},
+	{Version: 14, Name: "menu_category_unique_name", Up: migrateMenuCategoryUniqueName, Down: dropMenuCategoryUniqueName},
+	{Version: 15, Name: "order_group_computed_total", Up: dropOrderGroupTotal, Down: restoreOrderGroupTotal},
+}
+
+// initialModels are the tables of migration 0001, in dependency order
+func initialModels() []interface{} {
+	return []interface{}{
+		&initialUser{}, &initialRestaurant{}, &initialRestaurantSettings{}, &initialTable{},
+		&initialMenuCategory{}, &initialMenuItem{}, &initialOrder{}, &initialOrderGroup{},
+		&initialOrderItem{}, &initialPayment{}, &initialAuditLog{}, &initialInventoryAdjustment{},
+		&initialStockMovement{},
+	}
+}
+
+// migrateInitialSchema is the schema as it was built by AutoMigrate before versioned migrations.
+// Databases created back then already have it, so every step only changes what is missing.
+func migrateInitialSchema(tx *gorm.DB) error {
+	// Money columns used to be floats; convert them to cents before AutoMigrate changes their type.
+	// SQLite support came after the change, so only Postgres databases can hold float columns.
+	if !IsSQLite() {
+		if err := migrateMoneyToCents(tx); err != nil {
+			return fmt.Errorf("migrating money columns: %w", err)
+		}
+	}
+
+	if err := tx.AutoMigrate(initialModels()...); err != nil {
+		return fmt.Errorf("auto-migrating tables: %w", err)
+	}
+
+	if err := backfillMenuCategories(tx); err != nil {
+		return fmt.Errorf("creating menu categories: %w", err)
+	}
+	return nil
+}
+
+// dropInitialSchema drops every table of migration 0001, dependents first
+func dropInitialSchema(tx *gorm.DB) error {
+	tables := initialModels()
+	for i := len(tables) - 1; i >= 0; i-- {
+		if err := tx.Migrator().DropTable(tables[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userUniqueColumns are the users columns whose unique constraints ignore soft-deleted rows since 0002
+var userUniqueColumns = []struct {
+	column string
+	index  string
+}{
+	{"username", "idx_users_username"},
+	{"email", "idx_users_email"},
+}
+
+// migrateUserUniqueIndexes replaces the unique constraints on users.username and users.email
+// with unique indexes over undeleted users, so deleted accounts free their username and email
+func migrateUserUniqueIndexes(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	for _, unique := range userUniqueColumns {
+		// GORM names the constraint uni_users_<column>; tables created by older GORM versions have
+		// Postgres' default <table>_<column>_key instead
+		for _, name := range []string{"uni_users_" + unique.column, "users_" + unique.column + "_key"} {
+			if migrator.HasConstraint(&models.User{}, name) {
+				if err := migrator.DropConstraint(&models.User{}, name); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	// Dropping a constraint rebuilds the table on SQLite, which loses its indexes, so create
+	// indexes only after all constraints are gone and include the soft-delete index
+	for _, index := range []string{"idx_users_deleted_at", userUniqueColumns[0].index, userUniqueColumns[1].index} {
+		if !migrator.HasIndex(&models.User{}, index) {
+			if err := migrator.CreateIndex(&models.User{}, index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// revertUserUniqueIndexes restores plain unique constraints. It fails if a deleted user shares
+// a username or email with another user.
+func revertUserUniqueIndexes(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	for _, unique := range userUniqueColumns {
+		if migrator.HasIndex(&models.User{}, unique.index) {
+			if err := migrator.DropIndex(&models.User{}, unique.index); err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec(fmt.Sprintf("CREATE UNIQUE INDEX uni_users_%s ON users (%s)", unique.column, unique.column)).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// orderItemSnapshotFields are the order_items fields that record the menu item as it was ordered
+var orderItemSnapshotFields = []string{"ItemName", "UnitPrice"}
+
+// migrateOrderItemSnapshots adds the item name and unit price snapshots to order items, so orders
+// keep showing what was ordered after a menu item is renamed, repriced or deleted. Existing rows
+// are backfilled from their menu item, deleted ones included; that is its current price, as the
+// price at order time was never stored.
+func migrateOrderItemSnapshots(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	for _, field := range orderItemSnapshotFields {
+		if !migrator.HasColumn(&models.OrderItem{}, field) {
+			if err := migrator.AddColumn(&models.OrderItem{}, field); err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Exec(`UPDATE order_items SET
+		item_name = COALESCE((SELECT name FROM menu_items WHERE menu_items.id = order_items.menu_item_id), ''),
+		unit_price = COALESCE((SELECT price FROM menu_items WHERE menu_items.id = order_items.menu_item_id), 0)
+		WHERE item_name = ''`).Error
+}
+
+// dropOrderItemSnapshots removes the order item snapshot columns
+func dropOrderItemSnapshots(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	for _, field := range orderItemSnapshotFields {
+		if migrator.HasColumn(&models.OrderItem{}, field) {
+			if err := migrator.DropColumn(&models.OrderItem{}, field); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Dropping a column may rebuild the table on SQLite, which loses its indexes
+	if !migrator.HasIndex(&models.OrderItem{}, "idx_order_items_deleted_at") {
+		return migrator.CreateIndex(&models.OrderItem{}, "idx_order_items_deleted_at")
+	}
+	return nil
+}
+
+// auditLogEntityIndex serves audit log queries for one entity, e.g. everything that happened to an order
+const auditLogEntityIndex = "idx_audit_logs_restaurant_entity"
+
+// createAuditLogEntityIndex adds auditLogEntityIndex to databases whose audit_logs predate it
+func createAuditLogEntityIndex(tx *gorm.DB) error {
+	if tx.Migrator().HasIndex(&models.AuditLog{}, auditLogEntityIndex) {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(&models.AuditLog{}, auditLogEntityIndex)
+}
+
+// dropAuditLogEntityIndex removes auditLogEntityIndex
+func dropAuditLogEntityIndex(tx *gorm.DB) error {
+	if !tx.Migrator().HasIndex(&models.AuditLog{}, auditLogEntityIndex) {
+		return nil
+	}
+	return tx.Migrator().DropIndex(&models.AuditLog{}, auditLogEntityIndex)
+}
+
+// migrateOrderRefs adds daily order references: the orders.order_ref column, the per-restaurant
+// prefix setting and the order_sequences counters. Existing orders keep an empty reference.
+func migrateOrderRefs(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&models.Order{}, "OrderRef") {
+		if err := migrator.AddColumn(&models.Order{}, "OrderRef"); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn(&models.RestaurantSettings{}, "OrderRefPrefix") {
+		if err := migrator.AddColumn(&models.RestaurantSettings{}, "OrderRefPrefix"); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasTable(&models.OrderSequence{}) {
+		return migrator.CreateTable(&models.OrderSequence{})
+	}
+	return nil
+}
+
+// dropOrderRefs removes what migrateOrderRefs added
+func dropOrderRefs(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if err := migrator.DropTable(&models.OrderSequence{}); err != nil {
+		return err
+	}
+	if migrator.HasColumn(&models.RestaurantSettings{}, "OrderRefPrefix") {
+		if err := migrator.DropColumn(&models.RestaurantSettings{}, "OrderRefPrefix"); err != nil {
+			return err
+		}
+	}
+	if migrator.HasColumn(&models.Order{}, "OrderRef") {
+		if err := migrator.DropColumn(&models.Order{}, "OrderRef"); err != nil {
+			return err
+		}
+	}
+
+	if err := restoreIndexes(tx, &models.RestaurantSettings{}, "idx_restaurant_settings_restaurant_id", "idx_restaurant_settings_deleted_at"); err != nil {
+		return err
+	}
+	return restoreIndexes(tx, &models.Order{}, orderIndexes...)
+}
+
+// orderIndexes are the indexes of the orders table
+var orderIndexes = []string{"idx_orders_deleted_at", "idx_orders_group_id"}
+
+// restoreIndexes recreates the named indexes of model that are missing. Dropping a column may
+// rebuild the table on SQLite, which loses its indexes.
+func restoreIndexes(tx *gorm.DB, model interface{}, names ...string) error {
+	for _, name := range names {
+		if !tx.Migrator().HasIndex(model, name) {
+			if err := tx.Migrator().CreateIndex(model, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// orderContactFields are the orders fields for takeaway and delivery contact details
+var orderContactFields = []string{"CustomerPhone", "OrderType", "DeliveryAddress"}
+
+// migrateOrderContact adds the customer phone, order type and delivery address to orders.
+// Existing orders become dine-in orders without contact details.
+func migrateOrderContact(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	for _, field := range orderContactFields {
+		if !migrator.HasColumn(&models.Order{}, field) {
+			if err := migrator.AddColumn(&models.Order{}, field); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// dropOrderContact removes the order contact columns
+func dropOrderContact(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	for _, field := range orderContactFields {
+		if migrator.HasColumn(&models.Order{}, field) {
+			if err := migrator.DropColumn(&models.Order{}, field); err != nil {
+				return err
+			}
+		}
+	}
+	return restoreIndexes(tx, &models.Order{}, orderIndexes...)
+}
+
+// orderRestaurantIndex serves the restaurant's order queries now that orders may have no table to join through
+const orderRestaurantIndex = "idx_orders_restaurant_id"
+
+// migrateOrdersWithoutTable lets takeaway and delivery orders skip the table: table_id becomes
+// nullable and orders get a restaurant_id for when they have no table. Orders at a table keep 0.
+func migrateOrdersWithoutTable(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&models.Order{}, "RestaurantID") {
+		if err := migrator.AddColumn(&models.Order{}, "RestaurantID"); err != nil {
+			return err
+		}
+	}
+
+	nullable, err := orderTableIDNullable(tx)
+	if err != nil {
+		return err
+	}
+	if !nullable {
+		if IsSQLite() {
+			// SQLite can only change a column by rebuilding the table, which loses its indexes
+			err = migrator.AlterColumn(&models.Order{}, "TableID")
+		} else {
+			err = tx.Exec("ALTER TABLE orders ALTER COLUMN table_id DROP NOT NULL").Error
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return restoreIndexes(tx, &models.Order{}, append(orderIndexes, orderRestaurantIndex)...)
+}
+
+// revertOrdersWithoutTable drops orders.restaurant_id and, on Postgres, makes table_id required
+// again; SQLite keeps it nullable, as rebuilding the table would recreate it from the current
+// model. It fails while orders without a table exist.
+func revertOrdersWithoutTable(tx *gorm.DB) error {
+	var tableless int64
+	if err := tx.Model(&models.Order{}).Unscoped().Where("table_id IS NULL").Count(&tableless).Error; err != nil {
+		return err
+	}
+	if tableless > 0 {
+		return fmt.Errorf("%d orders have no table; assign them a table or delete them first", tableless)
+	}
+
+	migrator := tx.Migrator()
+	if migrator.HasIndex(&models.Order{}, orderRestaurantIndex) {
+		if err := migrator.DropIndex(&models.Order{}, orderRestaurantIndex); err != nil {
+			return err
+		}
+	}
+	if migrator.HasColumn(&models.Order{}, "RestaurantID") {
+		if err := migrator.DropColumn(&models.Order{}, "RestaurantID"); err != nil {
+			return err
+		}
+	}
+	if !IsSQLite() {
+		if err := tx.Exec("ALTER TABLE orders ALTER COLUMN table_id SET NOT NULL").Error; err != nil {
+			return err
+		}
+	}
+	return restoreIndexes(tx, &models.Order{}, orderIndexes...)
+}
+
+// createOrderRestaurantForeignKey fills in orders.restaurant_id from the order's table and makes
+// it reference restaurants. Databases created since the column was added already have the constraint.
+func createOrderRestaurantForeignKey(tx *gorm.DB) error {
+	if err := tx.Exec(`UPDATE orders SET
+		restaurant_id = COALESCE((SELECT restaurant_id FROM tables WHERE tables.id = orders.table_id), 0)
+		WHERE restaurant_id = 0`).Error; err != nil {
+		return err
+	}
+
+	migrator := tx.Migrator()
+	if migrator.HasConstraint(&models.Order{}, "Restaurant") {
+		return nil
+	}
+
+	var orphans int64
+	if err := tx.Model(&models.Order{}).Unscoped().Where("restaurant_id = 0").Count(&orphans).Error; err != nil {
+		return err
+	}
+	if orphans > 0 {
+		return fmt.Errorf("%d orders have no restaurant; set their restaurant_id or delete them first", orphans)
+	}
+
+	// SQLite adds the constraint by rebuilding the table, which loses its indexes
+	if err := migrator.CreateConstraint(&models.Order{}, "Restaurant"); err != nil {
+		return err
+	}
+	return restoreIndexes(tx, &models.Order{}, append(orderIndexes, orderRestaurantIndex)...)
+}
+
+// dropOrderRestaurantForeignKey removes the orders.restaurant_id foreign key
+func dropOrderRestaurantForeignKey(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasConstraint(&models.Order{}, "Restaurant") {
+		return nil
+	}
+	if err := migrator.DropConstraint(&models.Order{}, "Restaurant"); err != nil {
+		return err
+	}
+	return restoreIndexes(tx, &models.Order{}, append(orderIndexes, orderRestaurantIndex)...)
+}
+
+// migrateMinOrderAmount adds the minimum delivery order setting; existing restaurants have none
+func migrateMinOrderAmount(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&models.RestaurantSettings{}, "MinOrderAmount") {
+		return migrator.AddColumn(&models.RestaurantSettings{}, "MinOrderAmount")
+	}
+	return nil
+}
+
+// dropMinOrderAmount removes the minimum delivery order setting
+func dropMinOrderAmount(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if migrator.HasColumn(&models.RestaurantSettings{}, "MinOrderAmount") {
+		if err := migrator.DropColumn(&models.RestaurantSettings{}, "MinOrderAmount"); err != nil {
+			return err
+		}
+	}
+	return restoreIndexes(tx, &models.RestaurantSettings{}, "idx_restaurant_settings_restaurant_id", "idx_restaurant_settings_deleted_at")
+}
+
+// migrateScheduledOrders adds orders.scheduled_for for pre-orders; existing orders are wanted now
+func migrateScheduledOrders(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&models.Order{}, "ScheduledFor") {
+		return migrator.AddColumn(&models.Order{}, "ScheduledFor")
+	}
+	return nil
+}
+
+// dropScheduledOrders removes orders.scheduled_for. Scheduled orders would be stuck without it,
+// so this refuses while any are still waiting.
+func dropScheduledOrders(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&models.Order{}, "ScheduledFor") {
+		return nil
+	}
+	var waiting int64
+	if err := tx.Model(&models.Order{}).Where("status = ?", constants.OrderStatusScheduled).Count(&waiting).Error; err != nil {
+		return err
+	}
+	if waiting > 0 {
+		return fmt.Errorf("%d orders are still scheduled; start or cancel them first", waiting)
+	}
+	if err := migrator.DropColumn(&models.Order{}, "ScheduledFor"); err != nil {
+		return err
+	}
+	return restoreIndexes(tx, &models.Order{}, append(orderIndexes, orderRestaurantIndex)...)
+}
+
+// migrateMaxActiveOrders adds the kitchen capacity setting; existing restaurants have no limit
+func migrateMaxActiveOrders(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&models.RestaurantSettings{}, "MaxActiveOrders") {
+		return migrator.AddColumn(&models.RestaurantSettings{}, "MaxActiveOrders")
+	}
+	return nil
+}
+
+// dropMaxActiveOrders removes the kitchen capacity setting
+func dropMaxActiveOrders(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if migrator.HasColumn(&models.RestaurantSettings{}, "MaxActiveOrders") {
+		if err := migrator.DropColumn(&models.RestaurantSettings{}, "MaxActiveOrders"); err != nil {
+			return err
+		}
+	}
+	return restoreIndexes(tx, &models.RestaurantSettings{}, "idx_restaurant_settings_restaurant_id", "idx_restaurant_settings_deleted_at")
+}
+
+// menuItemIndexes are the indexes of the menu_items table other than the station one
+var menuItemIndexes = []string{"idx_menu_items_deleted_at", "idx_menu_items_restaurant_sku", "idx_menu_items_category_id", "idx_menu_items_is_featured"}
+
+// migrateMenuItemStation adds the kitchen station of menu items; existing items have none
+func migrateMenuItemStation(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&models.MenuItem{}, "Station") {
+		if err := migrator.AddColumn(&models.MenuItem{}, "Station"); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasIndex(&models.MenuItem{}, "Station") {
+		return migrator.CreateIndex(&models.MenuItem{}, "Station")
+	}
+	return nil
+}
+
+// dropMenuItemStation removes the kitchen station of menu items
+func dropMenuItemStation(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if migrator.HasIndex(&models.MenuItem{}, "Station") {
+		if err := migrator.DropIndex(&models.MenuItem{}, "Station"); err != nil {
+			return err
+		}
+	}
+	if migrator.HasColumn(&models.MenuItem{}, "Station") {
+		if err := migrator.DropColumn(&models.MenuItem{}, "Station"); err != nil {
+			return err
+		}
+	}
+	return restoreIndexes(tx, &models.MenuItem{}, menuItemIndexes...)
+}
+
+// migrateOrderItemStatus adds the per-item kitchen status. Items of orders already past the
+// kitchen are ready; the rest are pending.
+func migrateOrderItemStatus(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if migrator.HasColumn(&models.OrderItem{}, "Status") {
+		return nil
+	}
+	if err := migrator.AddColumn(&models.OrderItem{}, "Status"); err != nil {
+		return err
+	}
+	return tx.Model(&models.OrderItem{}).
+		Where("order_id IN (?)", tx.Model(&models.Order{}).Select("id").Where("status NOT IN ?", append([]string{constants.OrderStatusScheduled}, constants.KitchenOrderStatuses...))).
+		Update("status", constants.OrderItemStatusReady).Error
+}
+
+// dropOrderItemStatus removes the per-item kitchen status
+func dropOrderItemStatus(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if migrator.HasColumn(&models.OrderItem{}, "Status") {
+		if err := migrator.DropColumn(&models.OrderItem{}, "Status"); err != nil {
+			return err
+		}
+	}
+	return restoreIndexes(tx, &models.OrderItem{}, "idx_order_items_deleted_at")
+}
+
+// menuCategoryNameIndex keeps category names unique per restaurant, ignoring case and deleted
+// categories. GORM tags can't express LOWER(name), so the index isn't declared on the model.
+const menuCategoryNameIndex = "idx_menu_categories_restaurant_name"
+
+// migrateMenuCategoryUniqueName adds menuCategoryNameIndex. Categories whose names only differ
+// in case are merged into the oldest one first, which takes over their menu items.
+func migrateMenuCategoryUniqueName(tx *gorm.DB) error {
+	if tx.Migrator().HasIndex(&models.MenuCategory{}, menuCategoryNameIndex) {
+		return nil
+	}
+
+	var duplicates []models.MenuCategory
+	if err := tx.Where("EXISTS (SELECT 1 FROM menu_categories kept WHERE kept.restaurant_id = menu_categories.restaurant_id AND LOWER(kept.name) = LOWER(menu_categories.name) AND kept.deleted_at IS NULL AND kept.id < menu_categories.id)").
+		Find(&duplicates).Error; err != nil {
+		return err
+	}
+	for _, duplicate := range duplicates {
+		var kept models.MenuCategory
+		if err := tx.Where("restaurant_id = ? AND LOWER(name) = LOWER(?)", duplicate.RestaurantID, duplicate.Name).
+			Order("id").
+			First(&kept).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.MenuItem{}).
+			Where("category_id = ?", duplicate.ID).
+			Updates(map[string]interface{}{"category_id": kept.ID, "category": kept.Name}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&duplicate).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Exec("CREATE UNIQUE INDEX " + menuCategoryNameIndex + " ON menu_categories (restaurant_id, LOWER(name)) WHERE deleted_at IS NULL").Error
+}
+
+// dropMenuCategoryUniqueName removes menuCategoryNameIndex; merged categories stay merged
+func dropMenuCategoryUniqueName(tx *gorm.DB) error {
+	if !tx.Migrator().HasIndex(&models.MenuCategory{}, menuCategoryNameIndex) {
+		return nil
+	}
+	return tx.Migrator().DropIndex(&models.MenuCategory{}, menuCategoryNameIndex)
+}
+
+// orderGroupTotal is the total order groups stored until migration 15 computed it from their orders
+type orderGroupTotal struct {
+	TotalAmount int64 `gorm:"not null;default:0"`
+}
+
+func (orderGroupTotal) TableName() string { return "order_groups" }
+
+// dropOrderGroupTotal drops the stored group total, which went stale whenever a grouped order changed
+func dropOrderGroupTotal(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if !migrator.HasColumn(&orderGroupTotal{}, "TotalAmount") {
+		return nil
+	}
+	if err := migrator.DropColumn(&orderGroupTotal{}, "TotalAmount"); err != nil {
+		return err
+	}
+	return restoreIndexes(tx, &models.OrderGroup{}, "idx_order_groups_deleted_at")
+}
+
+// restoreOrderGroupTotal stores the group total again, filled in from the groups' orders
+func restoreOrderGroupTotal(tx *gorm.DB) error {
+	migrator := tx.Migrator()
+	if migrator.HasColumn(&orderGroupTotal{}, "TotalAmount") {
+		return nil
+	}
+	if err := migrator.AddColumn(&orderGroupTotal{}, "TotalAmount"); err != nil {
+		return err
+	}
+	return tx.Exec("UPDATE order_groups SET total_amount = (SELECT COALESCE(SUM(orders.total_amount), 0) FROM orders WHERE orders.group_id = order_groups.id AND orders.deleted_at IS NULL)").Error
+}
+
+// orderTableIDNullable reports whether orders.table_id accepts NULL
+func orderTableIDNullable(tx *gorm.DB) (bool, error) {
+	columnTypes, err := tx.Migrator().ColumnTypes(&models.Order{})
+	if err != nil {
+		return false, err
+	}
+	for _, columnType := range columnTypes {
+		if columnType.Name() == "table_id" {
+			nullable, _ := columnType.Nullable()
+			return nullable, nil
+		}
+	}
+	return false, errors.New("orders.table_id not found")
+}
+
+// Migrate applies every migration DB hasn't had yet, in version order
+func Migrate() error {
+	if err := DB.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+
+	for _, m := range migrations {
+		if err := inMigrationTx(func(tx *gorm.DB) error {
+			applied, err := lockMigration(tx, m.Version)
+			if err != nil || applied {
+				return err
+			}
+
+			log.Printf("Applying migration %04d_%s", m.Version, m.Name)
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		}); err != nil {
+			return fmt.Errorf("migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// RollbackMigrations reverts the most recently applied migrations, newest first, up to steps of them
+func RollbackMigrations(steps int) error {
+	if err := DB.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+
+	var applied []schemaMigration
+	if err := DB.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+		return err
+	}
+
+	for _, record := range applied {
+		m, ok := findMigration(record.Version)
+		if !ok {
+			return fmt.Errorf("migration %04d_%s is applied but unknown to this build", record.Version, record.Name)
+		}
+		if err := inMigrationTx(func(tx *gorm.DB) error {
+			stillApplied, err := lockMigration(tx, m.Version)
+			if err != nil || !stillApplied {
+				return err
+			}
+
+			log.Printf("Reverting migration %04d_%s", m.Version, m.Name)
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, m.Version).Error
+		}); err != nil {
+			return fmt.Errorf("reverting migration %04d_%s: %w", m.Version, m.Name, err)
+		}
+	}
+	return nil
+}
+
+// inMigrationTx runs fn in a transaction. On SQLite, which can only drop constraints by rebuilding
+// the table, foreign keys are switched off for the transaction as SQLite requires for rebuilds,
+// and checked for violations before it commits.
+func inMigrationTx(fn func(tx *gorm.DB) error) error {
+	if !IsSQLite() {
+		return DB.Transaction(fn)
+	}
+	// The pragma is per connection and ignored inside transactions, so pin one connection
+	return DB.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("PRAGMA foreign_keys = OFF").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("PRAGMA foreign_keys = ON")
+
+		return conn.Transaction(func(tx *gorm.DB) error {
+			if err := fn(tx); err != nil {
+				return err
+			}
+			rows, err := tx.Raw("PRAGMA foreign_key_check").Rows()
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			if rows.Next() {
+				return errors.New("foreign key check failed")
+			}
+			return rows.Err()
+		})
+	})
+}
+
+// lockMigration serializes migration runs across instances and reports whether version is applied
+func lockMigration(tx *gorm.DB, version int) (bool, error) {
+	// SQLite transactions already hold the database-wide write lock (_txlock=immediate)
+	if !IsSQLite() {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", migrationLockID).Error; err != nil {
+			return false, err
+		}
+	}
+	var count int64
+	if err := tx.Model(&schemaMigration{}).Where("version = ?", version).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func findMigration(version int) (migration, bool) {
+	for _, m := range migrations {
+		if m.Version == version {
+			return m, true
+		}
+	}
+	return migration{}, false
+}
+
+// backfillMenuCategories creates MenuCategory rows from the free-text categories of menu items
+// that aren't linked to one yet. Names differing only in case share a category.
+func backfillMenuCategories(tx *gorm.DB) error {
+	var rows []struct {
+		RestaurantID uint
+		Category     string
+	}
+	if err := tx.Model(&initialMenuItem{}).
+		Distinct("restaurant_id", "category").
+		Where("category_id IS NULL AND category <> ''").
+		Order("restaurant_id, category").
+		Scan(&rows).Error; err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var category initialMenuCategory
+		err := tx.Where("restaurant_id = ? AND LOWER(name) = LOWER(?)", row.RestaurantID, row.Category).First(&category).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			var count int64
+			if err := tx.Model(&initialMenuCategory{}).Where("restaurant_id = ?", row.RestaurantID).Count(&count).Error; err != nil {
+				return err
+			}
+			category = initialMenuCategory{RestaurantID: row.RestaurantID, Name: row.Category, DisplayOrder: int(count)}
+			err = tx.Create(&category).Error
+		}
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Model(&initialMenuItem{}).
+			Where("restaurant_id = ? AND category = ? AND category_id IS NULL", row.RestaurantID, row.Category).
+			Updates(map[string]interface{}{"category_id": category.ID, "category": category.Name}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateMoneyToCents converts legacy float money columns to integer cents, multiplying existing values by 100
+func migrateMoneyToCents(tx *gorm.DB) error {
+	moneyColumns := []struct {
+		table  string
+		column string
+	}{
+		{"menu_items", "price"},
+		{"orders", "total_amount"},
+		{"payments", "amount"},
+	}
+
+	for _, mc := range moneyColumns {
+		if !tx.Migrator().HasTable(mc.table) {
+			continue
+		}
+
+		columnTypes, err := tx.Migrator().ColumnTypes(mc.table)
+		if err != nil {
+			return err
+		}
+
+		for _, columnType := range columnTypes {
+			if columnType.Name() != mc.column {
+				continue
+			}
+
+			typeName := strings.ToLower(columnType.DatabaseTypeName())
+			if !strings.Contains(typeName, "float") && !strings.Contains(typeName, "double") &&
+				!strings.Contains(typeName, "numeric") && !strings.Contains(typeName, "real") {
+				break
+			}
+
+			log.Printf("Converting %s.%s to integer cents", mc.table, mc.column)
+			if err := tx.Exec(fmt.Sprintf(
+				"ALTER TABLE %s ALTER COLUMN %s TYPE bigint USING ROUND(%s * 100)::bigint",
+				mc.table, mc.column, mc.column,
+			)).Error; err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// orderSequenceDayLayout is the format of OrderSequence.Day
+const orderSequenceDayLayout = "2006-01-02"
+
+// NextOrderRef hands out the restaurant's next order reference for today, such as A-017, from
+// the restaurant's prefix and a number that starts at 1 each day. Call it in the transaction
+// that creates the order: the counter row stays locked until that transaction ends, so
+// concurrent orders get distinct numbers and a rolled-back order doesn't use one up.
+func NextOrderRef(tx *gorm.DB, restaurantID uint) (string, error) {
+	sequence := models.OrderSequence{
+		RestaurantID: restaurantID,
+		Day:          time.Now().Format(orderSequenceDayLayout),
+		LastNumber:   1,
+	}
+	if err := tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "restaurant_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"last_number": gorm.Expr("order_sequences.last_number + 1")}),
+	}).Create(&sequence).Error; err != nil {
+		return "", err
+	}
+	if err := tx.Where("restaurant_id = ? AND day = ?", sequence.RestaurantID, sequence.Day).
+		First(&sequence).Error; err != nil {
+		return "", err
+	}
+
+	var prefixes []string
+	if err := tx.Model(&models.RestaurantSettings{}).
+		Where("restaurant_id = ?", restaurantID).
+		Pluck("order_ref_prefix", &prefixes).Error; err != nil {
+		return "", err
+	}
+	prefix := constants.DefaultOrderRefPrefix
+	if len(prefixes) > 0 && prefixes[0] != "" {
+		prefix = prefixes[0]
+	}
+
+	return fmt.Sprintf("%s-%03d", prefix, sequence.LastNumber), nil
+}
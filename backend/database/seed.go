@@ -0,0 +1,243 @@
+package database
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"os"
+
+	"gorm.io/gorm"
+)
+
+const (
+	demoUsername       = "demo_owner"
+	demoEmail          = "demo_owner@example.com"
+	demoRestaurantName = "Demo Bistro"
+	demoTableCount     = 6
+)
+
+type demoMenuItem struct {
+	sku         string
+	name        string
+	description string
+	price       utils.Money
+	category    string
+	quantity    int
+	dietaryTags utils.StringList
+	allergens   utils.StringList
+	featured    bool
+}
+
+var demoCategories = []string{"Starters", "Mains", "Desserts", "Drinks"}
+
+var demoMenu = []demoMenuItem{
+	{"DEMO-001", "Tomato Bruschetta", "Grilled bread with tomato, garlic and basil", 650, "Starters", 40, utils.StringList{"vegan"}, utils.StringList{"gluten"}, true},
+	{"DEMO-002", "Calamari", "Fried squid rings with lemon aioli", 900, "Starters", 25, nil, utils.StringList{"eggs", "gluten", "molluscs"}, false},
+	{"DEMO-003", "Margherita Pizza", "Tomato, mozzarella and basil", 1250, "Mains", 30, utils.StringList{"vegetarian"}, utils.StringList{"gluten", "milk"}, true},
+	{"DEMO-004", "Grilled Salmon", "With seasonal vegetables and herb butter", 1890, "Mains", 15, utils.StringList{"gluten-free"}, utils.StringList{"fish", "milk"}, false},
+	{"DEMO-005", "Chickpea Curry", "Coconut curry with basmati rice", 1400, "Mains", 20, utils.StringList{"vegan", "gluten-free"}, nil, false},
+	{"DEMO-006", "Tiramisu", "Espresso-soaked ladyfingers with mascarpone", 750, "Desserts", 18, utils.StringList{"vegetarian"}, utils.StringList{"eggs", "gluten", "milk"}, true},
+	{"DEMO-007", "Sorbet", "Three scoops of seasonal fruit sorbet", 600, "Desserts", 25, utils.StringList{"vegan", "gluten-free"}, nil, false},
+	{"DEMO-008", "Lemonade", "Freshly squeezed", 400, "Drinks", 60, utils.StringList{"vegan", "gluten-free"}, nil, false},
+	{"DEMO-009", "Espresso", "Double shot", 300, "Drinks", 100, utils.StringList{"vegan", "gluten-free"}, nil, false},
+}
+
+type demoOrder struct {
+	table    int
+	customer string
+	status   string
+	items    map[string]int // SKU -> quantity
+}
+
+var demoOrders = []demoOrder{
+	{1, "Alice", constants.OrderStatusPending, map[string]int{"DEMO-003": 2, "DEMO-008": 2}},
+	{2, "Bob", constants.OrderStatusPreparing, map[string]int{"DEMO-004": 1, "DEMO-009": 1}},
+	{4, "Carol", constants.OrderStatusCompleted, map[string]int{"DEMO-001": 1, "DEMO-005": 1, "DEMO-006": 1}},
+}
+
+// SeedDemoData creates a demo owner with a restaurant, categorized menu, tables and sample orders.
+// Sample orders take their items' stock like real ones. Existing demo records are found by their names, SKUs and table numbers and left as they are,
+// so it can be run repeatedly. Tables are created without QR codes; run handler.BackfillTableQRCodes after.
+func SeedDemoData() error {
+	return DB.Transaction(func(tx *gorm.DB) error {
+		user, err := seedDemoOwner(tx)
+		if err != nil {
+			return err
+		}
+
+		var restaurant models.Restaurant
+		if err := tx.Where(models.Restaurant{UserID: user.ID, Name: demoRestaurantName}).
+			Attrs(models.Restaurant{Address: "1 Demo Street", PhoneNumber: "555-0100"}).
+			FirstOrCreate(&restaurant).Error; err != nil {
+			return err
+		}
+
+		categoryIDs := make(map[string]uint, len(demoCategories))
+		for i, name := range demoCategories {
+			var category models.MenuCategory
+			if err := tx.Where(models.MenuCategory{RestaurantID: restaurant.ID, Name: name}).
+				Attrs(models.MenuCategory{DisplayOrder: i}).
+				FirstOrCreate(&category).Error; err != nil {
+				return err
+			}
+			categoryIDs[name] = category.ID
+		}
+
+		menuItems := make(map[string]models.MenuItem, len(demoMenu))
+		for i, item := range demoMenu {
+			sku := item.sku
+			categoryID := categoryIDs[item.category]
+			var menuItem models.MenuItem
+			result := tx.Where("restaurant_id = ? AND sku = ?", restaurant.ID, sku).
+				Attrs(models.MenuItem{
+					RestaurantID:  restaurant.ID,
+					SKU:           &sku,
+					Name:          item.name,
+					Description:   item.description,
+					Price:         item.price,
+					Category:      item.category,
+					CategoryID:    &categoryID,
+					Quantity:      item.quantity,
+					DietaryTags:   item.dietaryTags,
+					Allergens:     item.allergens,
+					IsFeatured:    item.featured,
+					FeaturedOrder: i,
+				}).
+				FirstOrCreate(&menuItem)
+			if result.Error != nil {
+				return result.Error
+			}
+			// New items start their stock ledger like ones created through the API
+			if result.RowsAffected > 0 {
+				if err := tx.Create(&models.StockMovement{
+					RestaurantID:  restaurant.ID,
+					MenuItemID:    menuItem.ID,
+					Type:          constants.StockMovementInitial,
+					Delta:         menuItem.Quantity,
+					QuantityAfter: menuItem.Quantity,
+					Note:          "Demo data",
+				}).Error; err != nil {
+					return err
+				}
+			}
+			menuItems[sku] = menuItem
+		}
+
+		tables := make(map[int]models.Table, demoTableCount)
+		for number := 1; number <= demoTableCount; number++ {
+			var table models.Table
+			if err := tx.Where(models.Table{RestaurantID: restaurant.ID, TableNumber: number}).
+				FirstOrCreate(&table).Error; err != nil {
+				return err
+			}
+			tables[number] = table
+		}
+
+		// Orders have nothing natural to match on, so only seed them into a restaurant without any
+		var orderCount int64
+		if err := tx.Model(&models.Order{}).
+			Where("restaurant_id = ?", restaurant.ID).
+			Count(&orderCount).Error; err != nil {
+			return err
+		}
+		if orderCount > 0 {
+			return nil
+		}
+
+		for _, sample := range demoOrders {
+			ref, err := NextOrderRef(tx, restaurant.ID)
+			if err != nil {
+				return err
+			}
+			table := tables[sample.table]
+			order := models.Order{
+				RestaurantID: restaurant.ID,
+				TableID:      &table.ID,
+				OrderRef:     ref,
+				CustomerName: sample.customer,
+				Status:       sample.status,
+			}
+			for sku, quantity := range sample.items {
+				menuItem := menuItems[sku]
+				order.TotalAmount += menuItem.Price * utils.Money(quantity)
+				order.OrderItems = append(order.OrderItems, models.OrderItem{
+					MenuItemID: menuItem.ID,
+					ItemName:   menuItem.Name,
+					UnitPrice:  menuItem.Price,
+					Quantity:   quantity,
+				})
+			}
+			if err := tx.Create(&order).Error; err != nil {
+				return err
+			}
+			if err := takeDemoOrderStock(tx, order); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// takeDemoOrderStock takes the stock of a sample order and records it in the stock ledger, so
+// cancelling the order or having it swept as stale restocks only what it took
+func takeDemoOrderStock(tx *gorm.DB, order models.Order) error {
+	for _, orderItem := range order.OrderItems {
+		var menuItem models.MenuItem
+		if err := tx.First(&menuItem, orderItem.MenuItemID).Error; err != nil {
+			return err
+		}
+		menuItem.Quantity -= orderItem.Quantity
+		if err := tx.Model(&menuItem).Updates(map[string]interface{}{
+			"quantity": menuItem.Quantity,
+			"version":  gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.StockMovement{
+			RestaurantID:  order.RestaurantID,
+			MenuItemID:    menuItem.ID,
+			Type:          constants.StockMovementOrder,
+			Delta:         -orderItem.Quantity,
+			QuantityAfter: menuItem.Quantity,
+			OrderID:       &order.ID,
+		}).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedDemoOwner finds or creates the demo owner. The password comes from SEED_DEMO_PASSWORD
+// and is only used when the account is first created; without it a random one is generated
+// and logged once.
+func seedDemoOwner(tx *gorm.DB) (models.User, error) {
+	var user models.User
+	err := tx.Where("username = ?", demoUsername).First(&user).Error
+	if err == nil {
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return user, err
+	}
+
+	password := os.Getenv("SEED_DEMO_PASSWORD")
+	if password == "" {
+		random := make([]byte, 12)
+		if _, err := rand.Read(random); err != nil {
+			return user, err
+		}
+		password = hex.EncodeToString(random)
+		log.Printf("SEED_DEMO_PASSWORD not set, demo owner %q gets the generated password %q", demoUsername, password)
+	}
+	hashed, err := utils.HashPassword(password)
+	if err != nil {
+		return user, err
+	}
+
+	user = models.User{Username: demoUsername, Email: demoEmail, Password: hashed, Role: constants.RoleOwner}
+	return user, tx.Create(&user).Error
+}
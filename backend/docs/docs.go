@@ -30,26 +30,31 @@ const docTemplate = `{
                     "Order"
                 ],
                 "summary": "Get all user orders",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Include each order's items (default true); item_count and subtotal are always set",
+                        "name": "include_items",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.OrderResponse"
-                            }
+                            "$ref": "#/definitions/handler.Envelope-array_handler_OrderResponse"
                         }
                     },
                     "404": {
                         "description": "User not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Error retrieving orders",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
@@ -62,7 +67,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all restaurants for the authenticated user",
+                "description": "Get a page of restaurants for the authenticated user, with the total count in pagination",
                 "produces": [
                     "application/json"
                 ],
@@ -70,26 +75,43 @@ const docTemplate = `{
                     "Restaurant"
                 ],
                 "summary": "Get all restaurants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of restaurants to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.Restaurant"
-                            }
+                            "$ref": "#/definitions/handler.PageEnvelope-handler_Restaurant"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "User not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Error retrieving restaurants",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
@@ -126,25 +148,31 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handler.Restaurant"
+                            "$ref": "#/definitions/handler.Envelope-handler_Restaurant"
                         }
                     },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "User not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Duplicate value",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Error creating restaurant",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
@@ -167,19 +195,31 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response",
+                        "name": "If-None-Match",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.Restaurant"
+                            "$ref": "#/definitions/handler.Envelope-handler_Restaurant"
+                        }
+                    },
+                    "304": {
+                        "description": "Restaurant unchanged since the given ETag",
+                        "schema": {
+                            "type": "string"
                         }
                     },
                     "404": {
                         "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
@@ -223,25 +263,31 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.Restaurant"
+                            "$ref": "#/definitions/handler.Envelope-handler_Restaurant"
                         }
                     },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "User or restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Duplicate value",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Error updating restaurant",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
@@ -273,79 +319,132 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.Envelope-string"
                         }
                     },
                     "404": {
                         "description": "User or restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
                         "description": "Error deleting restaurant",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/restaurant/{restaurant_id}/menu": {
+        "/api/restaurant/{id}/audit": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all menu items for a restaurant",
+                "description": "Get a page of create, update and delete actions recorded for a restaurant, newest first, optionally filtered",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Restaurant"
                 ],
-                "summary": "Get all menu items",
+                "summary": "Get the audit log",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Restaurant ID",
-                        "name": "restaurant_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries with this action (create, update, delete)",
+                        "name": "action",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries for this kind of entity, e.g. order",
+                        "name": "entity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only entries for the entity with this ID",
+                        "name": "entity_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Only entries by this user",
+                        "name": "user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), inclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries to skip",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.MenuItem"
-                            }
+                            "$ref": "#/definitions/handler.PageEnvelope-handler_AuditLogEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters or filter",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving menu items",
+                        "description": "Error retrieving audit log",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            },
+            }
+        },
+        "/api/restaurant/{id}/clone": {
             "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new menu item for a restaurant",
+                "description": "Create a new restaurant for the same owner with copies of the settings, menu categories, menu items (stock reset to zero) and tables (with new QR codes). Orders and payments are not copied.",
                 "consumes": [
                     "application/json"
                 ],
@@ -353,24 +452,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Restaurant"
                 ],
-                "summary": "Create a new menu item",
+                "summary": "Clone a restaurant",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Restaurant ID",
-                        "name": "restaurant_id",
+                        "description": "Restaurant ID to copy",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Menu item data",
-                        "name": "menu_item",
+                        "description": "Overrides for the new restaurant",
+                        "name": "restaurant",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.MenuItem"
+                            "$ref": "#/definitions/handler.RestaurantCloneRequest"
                         }
                     }
                 ],
@@ -378,70 +476,107 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handler.MenuItem"
+                            "$ref": "#/definitions/handler.Envelope-handler_RestaurantCloneResponse"
                         }
                     },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error creating menu item",
+                        "description": "Error cloning restaurant",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/restaurant/{restaurant_id}/menu/{id}": {
-            "put": {
+        "/api/restaurant/{id}/settings": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a menu item",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get a restaurant's settings, or the defaults if none have been saved",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Restaurant"
                 ],
-                "summary": "Update a menu item",
+                "summary": "Get restaurant settings",
                 "parameters": [
                     {
                         "type": "string",
                         "description": "Restaurant ID",
-                        "name": "restaurant_id",
+                        "name": "id",
                         "in": "path",
                         "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_RestaurantSettings"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
                     },
+                    "500": {
+                        "description": "Error retrieving settings",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update only the provided settings fields, creating the settings row on first use",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Restaurant"
+                ],
+                "summary": "Update restaurant settings",
+                "parameters": [
                     {
                         "type": "string",
-                        "description": "Item ID",
+                        "description": "Restaurant ID",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Menu item data",
-                        "name": "menu_item",
+                        "description": "Settings to change",
+                        "name": "settings",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.MenuItem"
+                            "$ref": "#/definitions/handler.RestaurantSettingsUpdate"
                         }
                     }
                 ],
@@ -449,43 +584,51 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.MenuItem"
+                            "$ref": "#/definitions/handler.Envelope-handler_RestaurantSettings"
                         }
                     },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
-                        "description": "Restaurant or menu item not found",
+                        "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Duplicate value",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error updating menu item",
+                        "description": "Error updating settings",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/restaurant/{restaurant_id}/kitchen": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a menu item",
+                "description": "Get active orders grouped by status and sorted oldest-first, with items inlined for a kitchen display\nand grouped by the station they are prepared at. With station, only that station's items are shown\nand orders without any are left out.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Menu"
+                    "Order"
                 ],
-                "summary": "Delete a menu item",
+                "summary": "Get kitchen display orders",
                 "parameters": [
                     {
                         "type": "string",
@@ -496,49 +639,48 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Item ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Only items prepared at this station, e.g. bar",
+                        "name": "station",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.Envelope-array_handler_KitchenGroup"
                         }
                     },
                     "404": {
-                        "description": "Restaurant or menu item not found",
+                        "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error deleting menu item",
+                        "description": "Error retrieving orders",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/restaurant/{restaurant_id}/order": {
+        "/api/restaurant/{restaurant_id}/menu": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all orders for a restaurant",
+                "description": "Get all menu items for a restaurant, optionally only those priced within min_price and max_price (inclusive)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Menu"
                 ],
-                "summary": "Get all orders",
+                "summary": "Get all menu items",
                 "parameters": [
                     {
                         "type": "string",
@@ -546,28 +688,49 @@ const docTemplate = `{
                         "name": "restaurant_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Lowest price to include, e.g. 5.00",
+                        "name": "min_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Highest price to include, e.g. 20.00",
+                        "name": "max_price",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order by price, name or created_at, optionally with :asc or :desc, e.g. price:desc",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.Order"
-                            }
+                            "$ref": "#/definitions/handler.Envelope-array_handler_MenuItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid price range or sort",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
                         "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving orders",
+                        "description": "Error retrieving menu items",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
@@ -578,7 +741,7 @@ const docTemplate = `{
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new order for a restaurant",
+                "description": "Create a new menu item for a restaurant",
                 "consumes": [
                     "application/json"
                 ],
@@ -586,9 +749,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Menu"
                 ],
-                "summary": "Create a new order",
+                "summary": "Create a new menu item",
                 "parameters": [
                     {
                         "type": "string",
@@ -598,12 +761,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Order data",
-                        "name": "order",
+                        "description": "Menu item data",
+                        "name": "menu_item",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.Order"
+                            "$ref": "#/definitions/handler.MenuItem"
                         }
                     }
                 ],
@@ -611,45 +774,52 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handler.Order"
+                            "$ref": "#/definitions/handler.Envelope-handler_MenuItem"
                         }
                     },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
-                        "description": "Restaurant, table, or menu item not found",
+                        "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU already in use",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error creating order",
+                        "description": "Error creating menu item",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            }
-        },
-        "/api/restaurant/{restaurant_id}/order/{id}": {
-            "get": {
+            },
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get a single order by ID",
+                "description": "Delete several menu items at once. Nothing is deleted unless every ID belongs to the restaurant; otherwise the response lists the IDs that weren't found in data.missing_ids.\nItems in active orders are listed in data.in_use_ids with 409 unless force=true is passed.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Menu"
                 ],
-                "summary": "Get order by ID",
+                "summary": "Delete menu items in batch",
                 "parameters": [
                     {
                         "type": "string",
@@ -659,48 +829,70 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "IDs of the menu items to delete",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BatchDeleteMenuItemsRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete even if active orders contain some of the items",
+                        "name": "force",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.Order"
+                            "$ref": "#/definitions/handler.Envelope-handler_BatchDeleteMenuItemsResponse"
                         }
                     },
-                    "404": {
-                        "description": "Restaurant or order not found",
+                    "400": {
+                        "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or menu items not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Items are used by active orders",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving order",
+                        "description": "Error deleting menu items",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/restaurant/{restaurant_id}/menu-categories": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete an order",
+                "description": "Get a restaurant's menu categories in display order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Menu"
                 ],
-                "summary": "Delete an order",
+                "summary": "Get menu categories",
                 "parameters": [
                     {
                         "type": "string",
@@ -708,43 +900,36 @@ const docTemplate = `{
                         "name": "restaurant_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.Envelope-array_handler_MenuCategory"
                         }
                     },
                     "404": {
-                        "description": "Restaurant or order not found",
+                        "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error deleting order",
+                        "description": "Error retrieving categories",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             },
-            "patch": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update the status of an order",
+                "description": "Create a menu category for a restaurant",
                 "consumes": [
                     "application/json"
                 ],
@@ -752,9 +937,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Menu"
                 ],
-                "summary": "Update order status",
+                "summary": "Create a menu category",
                 "parameters": [
                     {
                         "type": "string",
@@ -764,65 +949,67 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Order ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Order status",
-                        "name": "status",
+                        "description": "Category data",
+                        "name": "category",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.OrderStatusUpdate"
+                            "$ref": "#/definitions/handler.MenuCategory"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/handler.Order"
+                            "$ref": "#/definitions/handler.Envelope-handler_MenuCategory"
                         }
                     },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
-                        "description": "Restaurant or order not found",
+                        "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Category already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error updating order",
+                        "description": "Error creating category",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/restaurant/{restaurant_id}/table": {
-            "get": {
+        "/api/restaurant/{restaurant_id}/menu-categories/{id}": {
+            "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all tables for a restaurant",
+                "description": "Rename or reorder a menu category; renaming updates the category shown on its menu items",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu"
                 ],
-                "summary": "Get all tables",
+                "summary": "Update a menu category",
                 "parameters": [
                     {
                         "type": "string",
@@ -830,49 +1017,71 @@ const docTemplate = `{
                         "name": "restaurant_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Category data",
+                        "name": "category",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MenuCategory"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.Table"
-                            }
+                            "$ref": "#/definitions/handler.Envelope-handler_MenuCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
-                        "description": "Restaurant not found",
+                        "description": "Restaurant or category not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Category already exists",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving tables",
+                        "description": "Error updating category",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             },
-            "post": {
+            "delete": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Create a new table for a restaurant",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Delete a menu category; its menu items are kept and become uncategorized",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu"
                 ],
-                "summary": "Create a new table",
+                "summary": "Delete a menu category",
                 "parameters": [
                     {
                         "type": "string",
@@ -882,51 +1091,43 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Table data",
-                        "name": "table",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.Table"
-                        }
+                        "type": "string",
+                        "description": "Category ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
-                        "schema": {
-                            "$ref": "#/definitions/handler.Table"
-                        }
-                    },
-                    "400": {
-                        "description": "Invalid input",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.Envelope-string"
                         }
                     },
                     "404": {
-                        "description": "Restaurant not found",
+                        "description": "Restaurant or category not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error creating table",
+                        "description": "Error deleting category",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/restaurant/{restaurant_id}/table/{id}": {
+        "/api/restaurant/{restaurant_id}/menu/by-sku/{sku}": {
             "put": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Update a table",
+                "description": "Create the menu item with the given SKU, or update the restaurant's existing item with that SKU. Safe to repeat, so external inventory syncs don't need internal IDs. Omitted dietary_tags, allergens, is_featured and featured_order keep their current values.",
                 "consumes": [
                     "application/json"
                 ],
@@ -934,9 +1135,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu"
                 ],
-                "summary": "Update a table",
+                "summary": "Create or update a menu item by SKU",
                 "parameters": [
                     {
                         "type": "string",
@@ -947,62 +1148,76 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Table ID",
-                        "name": "id",
+                        "description": "External SKU",
+                        "name": "sku",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Table data",
-                        "name": "table",
+                        "description": "Menu item data",
+                        "name": "menu_item",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.Table"
+                            "$ref": "#/definitions/handler.MenuItemUpsert"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK",
+                        "description": "Existing item updated",
                         "schema": {
-                            "$ref": "#/definitions/handler.Table"
+                            "$ref": "#/definitions/handler.Envelope-handler_MenuItem"
+                        }
+                    },
+                    "201": {
+                        "description": "New item created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_MenuItem"
                         }
                     },
                     "400": {
                         "description": "Invalid input",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
-                        "description": "Restaurant or table not found",
+                        "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Duplicate value",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error updating table",
+                        "description": "Error saving menu item",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/restaurant/{restaurant_id}/menu/out-of-stock": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete a table",
+                "description": "Get a restaurant's menu items with no stock left, by name unless sorted otherwise, to see what needs restocking",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu"
                 ],
-                "summary": "Delete a table",
+                "summary": "Get out-of-stock menu items",
                 "parameters": [
                     {
                         "type": "string",
@@ -1013,44 +1228,57 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Table ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Order by category, name or updated_at, optionally with :asc or :desc, e.g. category",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.Envelope-array_handler_MenuItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sort",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
-                        "description": "Restaurant or table not found",
+                        "description": "Restaurant not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error deleting table",
+                        "description": "Error retrieving menu items",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/restaurants/{restaurant_id}/menu": {
-            "get": {
-                "description": "Get all menu items for a restaurant without authentication",
+        "/api/restaurant/{restaurant_id}/menu/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a menu item. quantity is only changed when sent. Send the item's version to reject the update with 409 if the item changed since it was loaded, including stock taken by orders.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Menu"
                 ],
-                "summary": "Get public menu items",
+                "summary": "Update a menu item",
                 "parameters": [
                     {
                         "type": "string",
@@ -1058,46 +1286,71 @@ const docTemplate = `{
                         "name": "restaurant_id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Menu item data",
+                        "name": "menu_item",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MenuItem"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.MenuItem"
-                            }
+                            "$ref": "#/definitions/handler.Envelope-handler_MenuItem"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
-                        "description": "Restaurant not found",
+                        "description": "Restaurant or menu item not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "SKU already in use, or version conflict with the current item in data.current",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving menu items",
+                        "description": "Error updating menu item",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            }
-        },
-        "/api/restaurants/{restaurant_id}/order": {
-            "post": {
-                "description": "Create a new order for a restaurant without authentication",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Delete a menu item. Items in active orders are only deleted with force=true; the orders keep referencing the deleted item.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Order"
+                    "Menu"
                 ],
-                "summary": "Create a public order",
+                "summary": "Delete a menu item",
                 "parameters": [
                     {
                         "type": "string",
@@ -1107,144 +1360,259 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Order data",
-                        "name": "order",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.Order"
-                        }
-                    }
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Delete even if active orders contain the item",
+                        "name": "force",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.Order"
+                            "$ref": "#/definitions/handler.Envelope-string"
                         }
                     },
-                    "400": {
-                        "description": "Invalid input",
+                    "404": {
+                        "description": "Restaurant or menu item not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
-                    "404": {
-                        "description": "Restaurant, table, or menu item not found",
+                    "409": {
+                        "description": "Item is used by active orders",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error creating order",
+                        "description": "Error deleting menu item",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/table": {
-            "get": {
+        "/api/restaurant/{restaurant_id}/menu/{id}/adjust-stock": {
+            "post": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get all tables for all restaurants belonging to the user",
+                "description": "Add or remove stock for reasons other than orders (restock, spoilage, manual count). The quantity never drops below zero, and every adjustment is recorded with its reason.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Table"
+                    "Menu"
+                ],
+                "summary": "Adjust a menu item's stock",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Signed quantity change and reason",
+                        "name": "adjustment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.StockAdjustmentRequest"
+                        }
+                    }
                 ],
-                "summary": "Get all user tables",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.Table"
-                            }
+                            "$ref": "#/definitions/handler.Envelope-handler_StockAdjustmentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "404": {
-                        "description": "User not found",
+                        "description": "Restaurant or menu item not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Error retrieving tables",
+                        "description": "Error adjusting stock",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/user/": {
+        "/api/restaurant/{restaurant_id}/menu/{id}/stock-history": {
             "get": {
-                "description": "Get all registered users",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a page of the menu item's stock ledger in chronological order: initial stock, orders, cancelled orders, adjustments and manual edits, each with the running balance",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Menu"
+                ],
+                "summary": "Get a menu item's stock history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of entries to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get all users",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/handler.User"
-                            }
+                            "$ref": "#/definitions/handler.PageEnvelope-handler_StockMovementEntry"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or menu item not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Could not retrieve users",
+                        "description": "Error retrieving stock history",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            },
-            "delete": {
+            }
+        },
+        "/api/restaurant/{restaurant_id}/order": {
+            "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Delete the authenticated user",
+                "description": "Get all orders for a restaurant",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Order"
+                ],
+                "summary": "Get all orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include each order's items (default true); item_count and subtotal are always set",
+                        "name": "include_items",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order by created_at or total_amount, optionally with :asc or :desc, e.g. created_at:desc",
+                        "name": "sort",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Delete user",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.Envelope-array_handler_Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid sort",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
                     "500": {
-                        "description": "Could not retrieve or delete user",
+                        "description": "Error retrieving orders",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            }
-        },
-        "/api/user/login": {
+            },
             "post": {
-                "description": "Login with username and password to get JWT tokens",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new order for a restaurant",
                 "consumes": [
                     "application/json"
                 ],
@@ -1252,110 +1620,326 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Order"
                 ],
-                "summary": "User login",
+                "summary": "Create a new order",
                 "parameters": [
                     {
-                        "description": "Login credentials",
-                        "name": "credentials",
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order data",
+                        "name": "order",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.LoginRequest"
+                            "$ref": "#/definitions/handler.Order"
                         }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "201": {
+                        "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/fiber.Map"
+                            "$ref": "#/definitions/handler.Envelope-handler_Order"
                         }
                     },
-                    "401": {
-                        "description": "Invalid username or password",
+                    "400": {
+                        "description": "Invalid input, unknown fields listed in data.unknown_fields, or insufficient stock with data.shortages listing requested vs available per item",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant, table, or menu item not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating order",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/user/profile": {
+        "/api/restaurant/{restaurant_id}/order/{id}": {
             "get": {
                 "security": [
                     {
                         "BearerAuth": []
                     }
                 ],
-                "description": "Get the profile of the authenticated user",
+                "description": "Get a single order by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Order"
+                ],
+                "summary": "Get order by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get user profile",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.User"
+                            "$ref": "#/definitions/handler.Envelope-handler_Order"
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Restaurant or order not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving order",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
-            }
-        },
-        "/api/user/refresh": {
-            "post": {
-                "description": "Get new access token using refresh token",
-                "consumes": [
-                    "application/json"
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
                 ],
+                "description": "Delete an order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Order"
                 ],
-                "summary": "Refresh access token",
+                "summary": "Delete an order",
                 "parameters": [
                     {
-                        "description": "Refresh token",
-                        "name": "refresh",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.RefreshRequest"
-                        }
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handler.TokenResponse"
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-string"
                         }
                     },
-                    "401": {
-                        "description": "Invalid or expired refresh token",
+                    "404": {
+                        "description": "Restaurant or order not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error deleting order",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update the status of an order",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Update order status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderStatusUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or unknown status",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "The order can't move to that status from its current one",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating order",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/api/user/register": {
+        "/api/restaurant/{restaurant_id}/order/{id}/items/{item_id}": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Mark one item of an order ready, or pending again, while the kitchen is preparing the order.\nOnce every item is ready the order moves to ready. Sends an order_item_updated event, and order_updated when the order moves.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Mark an order item ready",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order item ID",
+                        "name": "item_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Item status",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderItemStatusUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input or unknown status",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant, order or order item not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "The order is no longer being prepared",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating order item",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/order/{id}/payments": {
             "post": {
-                "description": "Register a new user account",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record one part of a split bill against an order. The order is marked completed once payments cover its total; overpayment and payments on cancelled or completed orders are rejected.",
                 "consumes": [
                     "application/json"
                 ],
@@ -1363,17 +1947,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "User"
+                    "Payment"
                 ],
-                "summary": "Register a new user",
+                "summary": "Record a split payment",
                 "parameters": [
                     {
-                        "description": "User registration data",
-                        "name": "user",
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment amount and method",
+                        "name": "payment",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.RegisterRequest"
+                            "$ref": "#/definitions/handler.SplitPaymentRequest"
                         }
                     }
                 ],
@@ -1381,288 +1979,4367 @@ const docTemplate = `{
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/fiber.Map"
+                            "$ref": "#/definitions/handler.Envelope-handler_SplitPaymentResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid input",
+                        "description": "Invalid input, unknown fields listed in data.unknown_fields, order not payable or payment exceeds the remaining balance",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     },
-                    "409": {
-                        "description": "Username or email already taken",
+                    "404": {
+                        "description": "Restaurant or order not found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error recording payment",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Check if the API is running",
+        "/api/restaurant/{restaurant_id}/order/{id}/table": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Move a dine-in order to a different table of the same restaurant, e.g. when a party changes tables mid-meal",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Health"
+                    "Order"
+                ],
+                "summary": "Move an order to another table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Target table",
+                        "name": "table",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.OrderTableTransfer"
+                        }
+                    }
                 ],
-                "summary": "Health check endpoint",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/fiber.Map"
+                            "$ref": "#/definitions/handler.Envelope-handler_Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, the order isn't a dine-in order, or it is completed or cancelled",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant, order or table not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating order",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "fiber.Map": {
+        },
+        "/api/restaurant/{restaurant_id}/orders/active-count": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the number of active orders for a restaurant without loading order data",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get active order count",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_ActiveOrderCount"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error counting orders",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/orders/group/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get merged orders with their combined total, what has been paid towards it and what is left to pay",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get an order group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_OrderGroupResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or order group not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving order group",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/orders/group/{id}/payments": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Record a payment towards merged orders. The amount is spread over the group's orders with a balance left, oldest order first, as one payment per order; each order is marked completed once it is fully paid. Overpayment is rejected.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Payment"
+                ],
+                "summary": "Record a payment for an order group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Payment amount and method",
+                        "name": "payment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.SplitPaymentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_GroupPaymentResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, unknown fields listed in data.unknown_fields, or payment exceeds the remaining balance",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or order group not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error recording payment",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/orders/merge": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Group several open orders (e.g. from pushed-together tables) so they can be paid together. Each order keeps its items and history; the group's total is the combined total of its orders that aren't cancelled.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Merge open orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Orders to merge",
+                        "name": "orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.MergeOrdersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_OrderGroupResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, or an order is closed or already merged",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or order not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error merging orders",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/reports/fulfillment-time": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the average, median and p90 time from order creation to completion over a date range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Get order fulfillment time report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to 30 days ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), inclusive, defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_FulfillmentTimeReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving orders",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/reports/hourly": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get completed-order revenue and count bucketed by hour of day over a date range, always 24 buckets",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Get revenue by hour of day",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD), defaults to 30 days ago",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD), inclusive, defaults to today",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-array_handler_HourlyBucket"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid date range",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving orders",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/search": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Search a restaurant's menu items by name or description and its tables by number in one call.\nMatching ignores case; tables match when their number starts with the query.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Restaurant"
+                ],
+                "summary": "Search a restaurant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Search text, at most 100 characters",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_RestaurantSearchResults"
+                        }
+                    },
+                    "400": {
+                        "description": "Missing or too long query",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error searching",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/table": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get all tables for a restaurant",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Get all tables",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-array_handler_Table"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving tables",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create a new table for a restaurant",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Create a new table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Table data",
+                        "name": "table",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.Table"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_Table"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Duplicate value",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating table",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/table/{id}": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Update a table",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Update a table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Table ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Table data",
+                        "name": "table",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.Table"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_Table"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or table not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Duplicate value",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating table",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete a table",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Delete a table",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Table ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-string"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant or table not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error deleting table",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurant/{restaurant_id}/tables/batch": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Create several tables at once from a start/count range or an explicit list of numbers, skipping numbers that already exist",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Create tables in batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Table numbers to create",
+                        "name": "tables",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.BatchTableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_BatchTableResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Duplicate value",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating tables",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurants/{restaurant_id}/menu": {
+            "get": {
+                "description": "Get all menu items for a restaurant without authentication, including remaining stock (quantity) and in_stock",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "Get public menu items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated dietary tags every returned item must have, e.g. vegan,gluten-free",
+                        "name": "dietary",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Order by price, name or created_at instead of category display order, optionally with :asc or :desc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-array_handler_PublicMenuItem"
+                        }
+                    },
+                    "304": {
+                        "description": "Menu unchanged since the given ETag",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown dietary tag or invalid sort",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving menu items",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurants/{restaurant_id}/menu/featured": {
+            "get": {
+                "description": "Get a restaurant's featured menu items in featured order without authentication",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Menu"
+                ],
+                "summary": "Get featured menu items",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-array_handler_PublicMenuItem"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving menu items",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurants/{restaurant_id}/my-orders": {
+            "get": {
+                "description": "Get the most recent orders, up to 50, placed at the restaurant with a phone number, newest first. The number must be confirmed with the code from POST /my-orders/code; separators in the number don't matter.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Get past orders by phone number",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Phone number the orders were placed with",
+                        "name": "phone",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code texted to the phone number",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-array_handler_Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone number or missing code",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Wrong or expired code",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving orders",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurants/{restaurant_id}/my-orders/code": {
+            "post": {
+                "description": "Text a six-digit code to a phone number, which GET /my-orders takes to list the orders placed with that number. The code is valid for 10 minutes; a new one can be requested once a minute.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Send a code to see past orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Phone number the orders were placed with",
+                        "name": "phone",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.GuestOrderCodeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_GuestOrderCodeSent"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid phone number",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "429": {
+                        "description": "A code was sent to the number less than a minute ago",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error sending the code",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurants/{restaurant_id}/order": {
+            "post": {
+                "description": "Create a new order for a restaurant without authentication",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Create a public order",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Order data",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.Order"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_Order"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, unknown fields listed in data.unknown_fields, or insufficient stock with data.shortages listing requested vs available per item",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant, table, or menu item not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "429": {
+                        "description": "Kitchen at capacity, with data.estimated_wait_minutes",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating order",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurants/{restaurant_id}/reorder/{order_id}": {
+            "post": {
+                "description": "Place a new pending order with the items of a previous order placed with the guest's phone number, confirmed with the code from POST /my-orders/code. Items are charged at their current price; items no longer on the menu or without enough stock are left out and listed in skipped. Dine-in orders go to the previous table unless table_id is given.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Order"
+                ],
+                "summary": "Order the same as last time",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ID of the previous order",
+                        "name": "order_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Phone number, code and optional table",
+                        "name": "reorder",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ReorderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_ReorderResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input, unknown fields listed in data.unknown_fields, insufficient stock, or none of the items can be ordered, with data.skipped listing them",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "401": {
+                        "description": "Wrong or expired code",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant, order or table not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "429": {
+                        "description": "Kitchen at capacity, with data.estimated_wait_minutes",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error creating order",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/restaurants/{restaurant_id}/storefront": {
+            "get": {
+                "description": "Get everything the customer ordering page needs in one call without authentication: the restaurant with its tables, its public settings, the featured items and the full menu",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Restaurant"
+                ],
+                "summary": "Get a restaurant's storefront",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Restaurant ID",
+                        "name": "restaurant_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from an earlier response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_Storefront"
+                        }
+                    },
+                    "304": {
+                        "description": "Storefront unchanged since the given ETag",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "404": {
+                        "description": "Restaurant not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving storefront",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/table": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a page of tables across all restaurants belonging to the user, with the total count in pagination",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Table"
+                ],
+                "summary": "Get all user tables",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 200)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of tables to skip",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PageEnvelope-handler_Table"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid pagination parameters",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error retrieving tables",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/": {
+            "get": {
+                "description": "Get all registered users",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get all users",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-array_handler_User"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve users",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Delete the authenticated user together with their restaurants, tables, menu items and orders. The username must be retyped to confirm.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Delete user",
+                "parameters": [
+                    {
+                        "description": "Username confirmation",
+                        "name": "confirmation",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.DeleteUserRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-string"
+                        }
+                    },
+                    "400": {
+                        "description": "Confirmation does not match username",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Could not retrieve or delete user",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/login": {
+            "post": {
+                "description": "Login with username and password to get JWT tokens",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "User login",
+                "parameters": [
+                    {
+                        "description": "Login credentials",
+                        "name": "credentials",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_LoginResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid username or password",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/logout": {
+            "post": {
+                "description": "Clear user's tokens",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "User logout",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_MessageResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/profile": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get the profile of the authenticated user",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get user profile",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_UserProfile"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/refresh": {
+            "post": {
+                "description": "Get new access token using refresh token",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Refresh access token",
+                "parameters": [
+                    {
+                        "description": "Refresh token",
+                        "name": "refresh",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RefreshRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_MessageResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Invalid or expired refresh token",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/register": {
+            "post": {
+                "description": "Register a new owner account. Any role in the request is ignored; admins assign other roles with PATCH /api/user/{id}/role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Register a new user",
+                "parameters": [
+                    {
+                        "description": "User registration data",
+                        "name": "user",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RegisterRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_RegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid input",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Username or email already taken",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/websocket-token": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Get a temporary token for WebSocket authentication",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Get temporary WebSocket token",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_WebSocketTokenResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/{id}/role": {
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ],
+                "description": "Assign the owner, staff or admin role to a user. Only admins may call it, and the last remaining admin can't be demoted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "User"
+                ],
+                "summary": "Change a user's role",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New role",
+                        "name": "role",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpdateUserRoleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_User"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid role",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "User not found",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "409": {
+                        "description": "Cannot demote the last admin",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Error updating role",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ErrorEnvelope"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the API is running",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-handler_HealthStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/version": {
+            "get": {
+                "description": "Get the version, git commit and build time of the running server",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Build version",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.Envelope-version_Info"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "gorm.DeletedAt": {
+            "type": "object",
+            "properties": {
+                "time": {
+                    "type": "string"
+                },
+                "valid": {
+                    "description": "Valid is true if Time is not NULL",
+                    "type": "boolean"
+                }
+            }
+        },
+        "handler.ActiveOrderCount": {
+            "type": "object",
+            "properties": {
+                "active_count": {
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "handler.AuditLogEntry": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "example": "update"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "string",
+                    "example": "Updated menu item \"Margherita\""
+                },
+                "entity": {
+                    "type": "string",
+                    "example": "menu_item"
+                },
+                "entity_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.BatchDeleteMenuItemsRequest": {
+            "type": "object",
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        3,
+                        7,
+                        12
+                    ]
+                }
+            }
+        },
+        "handler.BatchDeleteMenuItemsResponse": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.BatchTableRequest": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "numbers": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "start": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "handler.BatchTableResponse": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Table"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "handler.DeleteUserRequest": {
+            "type": "object",
+            "properties": {
+                "confirm_username": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "handler.Envelope-array_handler_HourlyBucket": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.HourlyBucket"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-array_handler_KitchenGroup": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.KitchenGroup"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-array_handler_MenuCategory": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.MenuCategory"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-array_handler_MenuItem": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.MenuItem"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-array_handler_Order": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Order"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-array_handler_OrderResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.OrderResponse"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-array_handler_PublicMenuItem": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PublicMenuItem"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-array_handler_Table": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Table"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-array_handler_User": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.User"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_ActiveOrderCount": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ActiveOrderCount"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_BatchDeleteMenuItemsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.BatchDeleteMenuItemsResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_BatchTableResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.BatchTableResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_FulfillmentTimeReport": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.FulfillmentTimeReport"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_GroupPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.GroupPaymentResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_GuestOrderCodeSent": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.GuestOrderCodeSent"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_HealthStatus": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.HealthStatus"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_LoginResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.LoginResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_MenuCategory": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.MenuCategory"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_MenuItem": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.MenuItem"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_MessageResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.MessageResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_Order": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.Order"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_OrderGroupResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.OrderGroupResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_OrderResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.OrderResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.RegisterResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_ReorderResult": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.ReorderResult"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_Restaurant": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.Restaurant"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_RestaurantCloneResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.RestaurantCloneResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_RestaurantSearchResults": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.RestaurantSearchResults"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_RestaurantSettings": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.RestaurantSettings"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_SplitPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.SplitPaymentResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_StockAdjustmentResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.StockAdjustmentResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_Storefront": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.Storefront"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_Table": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.Table"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_User": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.User"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_UserProfile": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.UserProfile"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-handler_WebSocketTokenResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.WebSocketTokenResponse"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-string": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Envelope-version_Info": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/version.Info"
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.ErrorEnvelope": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "machine-readable, see constants.ErrCode*",
+                    "type": "string",
+                    "example": "RESTAURANT_NOT_FOUND"
+                },
+                "data": {
+                    "description": "null, or details named in the endpoint's description such as missing_ids"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "Restaurant not found"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": false
+                }
+            }
+        },
+        "handler.FulfillmentTimeReport": {
+            "type": "object",
+            "properties": {
+                "average_seconds": {
+                    "type": "number"
+                },
+                "completion_source": {
+                    "description": "timestamp used as the completion time",
+                    "type": "string",
+                    "example": "updated_at"
+                },
+                "from": {
+                    "type": "string",
+                    "example": "2024-05-01"
+                },
+                "median_seconds": {
+                    "type": "number"
+                },
+                "p90_seconds": {
+                    "type": "number"
+                },
+                "sample_size": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2024-05-31"
+                }
+            }
+        },
+        "handler.GroupPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "amount_paid": {
+                    "type": "string",
+                    "example": "50.00"
+                },
+                "group_complete": {
+                    "type": "boolean"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "payment_ids": {
+                    "description": "one payment per order the amount went to",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "remaining": {
+                    "type": "string",
+                    "example": "24.00"
+                },
+                "total_amount": {
+                    "type": "string",
+                    "example": "74.00"
+                }
+            }
+        },
+        "handler.GuestOrderCodeRequest": {
+            "type": "object",
+            "properties": {
+                "phone": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "+1 555 010 2030"
+                }
+            }
+        },
+        "handler.GuestOrderCodeSent": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "description": "seconds the code stays valid",
+                    "type": "integer",
+                    "example": 600
+                }
+            }
+        },
+        "handler.HealthStatus": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Welcome to the Order-System API"
+                },
+                "order_events_dropped": {
+                    "description": "events the WebSocket hub dropped because its queue was full",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "handler.HourlyBucket": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "hour": {
+                    "type": "integer"
+                },
+                "revenue": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.KitchenGroup": {
+            "type": "object",
+            "properties": {
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.KitchenOrder"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.KitchenOrder": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "elapsed_seconds": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.KitchenOrderItem"
+                    }
+                },
+                "order_ref": {
+                    "type": "string",
+                    "example": "A-017"
+                },
+                "order_type": {
+                    "type": "string",
+                    "example": "takeaway"
+                },
+                "stations": {
+                    "description": "the items by station, stations by name and items without one last",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.KitchenStation"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "table_id": {
+                    "description": "null when the order has no table",
+                    "type": "integer"
+                },
+                "table_number": {
+                    "description": "0 when the order has no table",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.KitchenOrderItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "description": "order item ID, for marking it ready",
+                    "type": "integer"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "special_instructions": {
+                    "type": "string"
+                },
+                "station": {
+                    "description": "the menu item's current station; empty for none",
+                    "type": "string",
+                    "example": "grill"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "ready"
+                    ]
+                }
+            }
+        },
+        "handler.KitchenStation": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.KitchenOrderItem"
+                    }
+                },
+                "station": {
+                    "description": "empty for items without a station",
+                    "type": "string",
+                    "example": "grill"
+                }
+            }
+        },
+        "handler.LoginRequest": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "handler.LoginResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "restaurant": {
+                    "description": "the user's first restaurant, null if they have none",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/handler.LoginRestaurant"
+                        }
+                    ]
+                },
+                "role": {
+                    "type": "string",
+                    "example": "owner"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "handler.LoginRestaurant": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.MenuCategory": {
+            "type": "object",
+            "properties": {
+                "display_order": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Desserts"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.MenuItem": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sesame"
+                    ]
+                },
+                "category": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dietary_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "vegan",
+                        "gluten-free"
+                    ]
+                },
+                "featured_order": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_featured": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "12.50"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "type": "string",
+                    "example": "PIZ-001"
+                },
+                "station": {
+                    "description": "kitchen station, lowercase; empty for none",
+                    "type": "string",
+                    "example": "grill"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.MenuItemUpsert": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dietary_tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "featured_order": {
+                    "type": "integer"
+                },
+                "image_url": {
+                    "type": "string"
+                },
+                "is_featured": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Margherita"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "12.50"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "station": {
+                    "type": "string",
+                    "example": "grill"
+                }
+            }
+        },
+        "handler.MergeOrdersRequest": {
+            "type": "object",
+            "properties": {
+                "order_ids": {
+                    "description": "required: true",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        12,
+                        15
+                    ]
+                }
+            }
+        },
+        "handler.MessageResponse": {
+            "type": "object",
+            "properties": {
+                "message": {
+                    "type": "string",
+                    "example": "Successfully logged out"
+                }
+            }
+        },
+        "handler.Order": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "customer_phone": {
+                    "type": "string",
+                    "example": "+1 555 010 2030"
+                },
+                "delivery_address": {
+                    "description": "required for delivery orders",
+                    "type": "string",
+                    "example": "12 Harbour Road"
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "2.50"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_count": {
+                    "description": "total quantity across the order's items",
+                    "type": "integer"
+                },
+                "order_items": {
+                    "description": "null when the list was requested with include_items=false",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.OrderItem"
+                    }
+                },
+                "order_ref": {
+                    "description": "daily per-restaurant reference staff call out",
+                    "type": "string",
+                    "example": "A-017"
+                },
+                "order_type": {
+                    "description": "defaults to dine_in",
+                    "type": "string",
+                    "enum": [
+                        "dine_in",
+                        "takeaway",
+                        "delivery"
+                    ],
+                    "example": "delivery"
+                },
+                "scheduled_for": {
+                    "description": "when a pre-order is wanted; null for orders wanted now",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "scheduled",
+                        "active",
+                        "out_for_delivery",
+                        "delivered",
+                        "paid",
+                        "cancelled"
+                    ]
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "35.00"
+                },
+                "table_id": {
+                    "description": "null for takeaway and delivery orders",
+                    "type": "integer"
+                },
+                "table_number": {
+                    "description": "0 when the order has no table",
+                    "type": "integer"
+                },
+                "tip": {
+                    "type": "string",
+                    "example": "5.00"
+                },
+                "total_amount": {
+                    "type": "string",
+                    "example": "37.50"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.OrderGroupResponse": {
+            "type": "object",
+            "properties": {
+                "amount_paid": {
+                    "type": "string",
+                    "example": "30.00"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Order"
+                    }
+                },
+                "remaining": {
+                    "type": "string",
+                    "example": "44.00"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                },
+                "total_amount": {
+                    "description": "combined total of the orders that aren't cancelled",
+                    "type": "string",
+                    "example": "74.00"
+                }
+            }
+        },
+        "handler.OrderItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "line_total": {
+                    "description": "price * quantity",
+                    "type": "string",
+                    "example": "25.00"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "string",
+                    "example": "12.50"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "special_instructions": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "ready"
+                    ]
+                }
+            }
+        },
+        "handler.OrderItemStatusUpdate": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "required: true",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "ready"
+                    ],
+                    "example": "ready"
+                }
+            }
+        },
+        "handler.OrderResponse": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "customer_name": {
+                    "type": "string"
+                },
+                "customer_phone": {
+                    "type": "string",
+                    "example": "+1 555 010 2030"
+                },
+                "delivery_address": {
+                    "description": "required for delivery orders",
+                    "type": "string",
+                    "example": "12 Harbour Road"
+                },
+                "discount": {
+                    "type": "string",
+                    "example": "2.50"
+                },
+                "group_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "item_count": {
+                    "description": "total quantity across the order's items",
+                    "type": "integer"
+                },
+                "order_items": {
+                    "description": "null when the list was requested with include_items=false",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.OrderItem"
+                    }
+                },
+                "order_ref": {
+                    "description": "daily per-restaurant reference staff call out",
+                    "type": "string",
+                    "example": "A-017"
+                },
+                "order_type": {
+                    "description": "defaults to dine_in",
+                    "type": "string",
+                    "enum": [
+                        "dine_in",
+                        "takeaway",
+                        "delivery"
+                    ],
+                    "example": "delivery"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                },
+                "restaurant_name": {
+                    "type": "string"
+                },
+                "scheduled_for": {
+                    "description": "when a pre-order is wanted; null for orders wanted now",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "scheduled",
+                        "active",
+                        "out_for_delivery",
+                        "delivered",
+                        "paid",
+                        "cancelled"
+                    ]
+                },
+                "subtotal": {
+                    "type": "string",
+                    "example": "35.00"
+                },
+                "table_id": {
+                    "description": "null for takeaway and delivery orders",
+                    "type": "integer"
+                },
+                "table_number": {
+                    "description": "0 when the order has no table",
+                    "type": "integer"
+                },
+                "tip": {
+                    "type": "string",
+                    "example": "5.00"
+                },
+                "total_amount": {
+                    "type": "string",
+                    "example": "37.50"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.OrderStatusUpdate": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "completed"
+                }
+            }
+        },
+        "handler.OrderTableTransfer": {
+            "type": "object",
+            "properties": {
+                "table_id": {
+                    "description": "required: true",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "handler.PageEnvelope-handler_AuditLogEntry": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.AuditLogEntry"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handler.Pagination"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.PageEnvelope-handler_Restaurant": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Restaurant"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handler.Pagination"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.PageEnvelope-handler_StockMovementEntry": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.StockMovementEntry"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handler.Pagination"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.PageEnvelope-handler_Table": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.Table"
+                    }
+                },
+                "error": {
+                    "description": "always null",
+                    "type": "string"
+                },
+                "pagination": {
+                    "$ref": "#/definitions/handler.Pagination"
+                },
+                "success": {
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
+        "handler.Pagination": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.PublicMenuItem": {
+            "type": "object",
+            "properties": {
+                "allergens": {
+                    "description": "JSON array from utils.Allergens",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "category": {
+                    "description": "name of the linked MenuCategory, kept for display",
+                    "type": "string"
+                },
+                "categoryID": {
+                    "type": "integer"
+                },
+                "category_display_order": {
+                    "description": "nil for uncategorized items",
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "dietaryTags": {
+                    "description": "JSON array from utils.DietaryTags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "featuredOrder": {
+                    "description": "position among featured items, ascending",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "imageURL": {
+                    "type": "string"
+                },
+                "in_stock": {
+                    "type": "boolean"
+                },
+                "isFeatured": {
+                    "type": "boolean"
+                },
+                "is_new": {
+                    "description": "added within the last MENU_NEW_ITEM_DAYS days",
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "orderItems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                },
+                "price": {
+                    "description": "in cents",
+                    "type": "integer"
+                },
+                "quantity": {
+                    "description": "available quantity of the menu item",
+                    "type": "integer"
+                },
+                "remaining_quantity": {
+                    "description": "stock left to order",
+                    "type": "integer"
+                },
+                "restaurantID": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "description": "owner's external inventory code",
+                    "type": "string"
+                },
+                "station": {
+                    "description": "lowercase kitchen station it is prepared at, e.g. grill or bar; empty for none",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "bumped on every write, for optimistic locking",
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.RefreshRequest": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.refresh..."
+                }
+            }
+        },
+        "handler.RegisterRequest": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "password": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "password123"
+                },
+                "username": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "handler.RegisterResponse": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "example": "john@example.com"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "owner"
+                },
+                "user_id": {
+                    "type": "integer"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "john_doe"
+                }
+            }
+        },
+        "handler.ReorderRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "482913"
+                },
+                "phone": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "+1 555 010 2030"
+                },
+                "table_id": {
+                    "description": "defaults to the previous order's table, for dine_in orders",
+                    "type": "integer",
+                    "example": 4
+                }
+            }
+        },
+        "handler.ReorderResult": {
+            "type": "object",
+            "properties": {
+                "order": {
+                    "$ref": "#/definitions/handler.Order"
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.ReorderSkippedItem"
+                    }
+                }
+            }
+        },
+        "handler.ReorderSkippedItem": {
+            "type": "object",
+            "properties": {
+                "available": {
+                    "description": "current stock, for out_of_stock items",
+                    "type": "integer"
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "reason": {
+                    "description": "removed or out_of_stock",
+                    "type": "string",
+                    "example": "out_of_stock"
+                }
+            }
+        },
+        "handler.Restaurant": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "logo_url": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "phone_number": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.RestaurantCloneRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Downtown Bistro"
+                },
+                "phone_number": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RestaurantCloneResponse": {
+            "type": "object",
+            "properties": {
+                "menu_categories": {
+                    "type": "integer"
+                },
+                "menu_items": {
+                    "type": "integer"
+                },
+                "restaurant": {
+                    "$ref": "#/definitions/models.Restaurant"
+                },
+                "tables": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.RestaurantSearchResults": {
+            "type": "object",
+            "properties": {
+                "menu_items": {
+                    "description": "name or description matches, by name",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MenuItem"
+                    }
+                },
+                "query": {
+                    "description": "the trimmed search text",
+                    "type": "string"
+                },
+                "tables": {
+                    "description": "tables whose number starts with the query, by number",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Table"
+                    }
+                }
+            }
+        },
+        "handler.RestaurantSettings": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "low_stock_threshold": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "max_active_orders": {
+                    "description": "active orders at which customer orders are refused; 0 for no limit",
+                    "type": "integer",
+                    "example": 20
+                },
+                "min_order_amount": {
+                    "description": "smallest items subtotal for delivery orders; 0.00 for none",
+                    "type": "string",
+                    "example": "15.00"
+                },
+                "operating_hours_enabled": {
+                    "type": "boolean"
+                },
+                "order_ref_prefix": {
+                    "type": "string",
+                    "example": "A"
+                },
+                "prep_buffer_minutes": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                },
+                "tax_rate": {
+                    "description": "percent for display; not added to order totals",
+                    "type": "number",
+                    "example": 8.5
+                }
+            }
+        },
+        "handler.RestaurantSettingsUpdate": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "EUR"
+                },
+                "low_stock_threshold": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "max_active_orders": {
+                    "type": "integer",
+                    "example": 20
+                },
+                "min_order_amount": {
+                    "type": "string",
+                    "example": "15.00"
+                },
+                "operating_hours_enabled": {
+                    "type": "boolean"
+                },
+                "order_ref_prefix": {
+                    "description": "1-3 letters, used from the next order on",
+                    "type": "string",
+                    "example": "T"
+                },
+                "prep_buffer_minutes": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "tax_rate": {
+                    "type": "number",
+                    "example": 8.5
+                }
+            }
+        },
+        "handler.SplitPaymentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "12.50"
+                },
+                "payment_method": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "cash"
+                }
+            }
+        },
+        "handler.SplitPaymentResponse": {
+            "type": "object",
+            "properties": {
+                "amount_paid": {
+                    "type": "string",
+                    "example": "25.00"
+                },
+                "order_complete": {
+                    "type": "boolean"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "payment_id": {
+                    "type": "integer"
+                },
+                "remaining": {
+                    "type": "string",
+                    "example": "12.50"
+                },
+                "total_amount": {
+                    "type": "string",
+                    "example": "37.50"
+                }
+            }
+        },
+        "handler.StockAdjustmentRequest": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "description": "required: true",
+                    "type": "integer",
+                    "example": -3
+                },
+                "reason": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "spoilage"
+                }
+            }
+        },
+        "handler.StockAdjustmentResponse": {
+            "type": "object",
+            "properties": {
+                "adjustment_id": {
+                    "type": "integer"
+                },
+                "applied_delta": {
+                    "type": "integer",
+                    "example": -2
+                },
+                "delta": {
+                    "type": "integer",
+                    "example": -3
+                },
+                "menu_item_id": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "spoilage"
+                }
+            }
+        },
+        "handler.StockMovementEntry": {
+            "type": "object",
+            "properties": {
+                "adjustment_id": {
+                    "type": "integer"
+                },
+                "balance": {
+                    "type": "integer",
+                    "example": 18
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delta": {
+                    "type": "integer",
+                    "example": -2
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "order"
+                }
+            }
+        },
+        "handler.Storefront": {
+            "type": "object",
+            "properties": {
+                "featured": {
+                    "description": "in featured order",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PublicMenuItem"
+                    }
+                },
+                "menu": {
+                    "description": "as from GET /api/restaurants/{restaurant_id}/menu",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PublicMenuItem"
+                    }
+                },
+                "restaurant": {
+                    "description": "with its tables, as from GET /api/restaurant/{id}",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Restaurant"
+                        }
+                    ]
+                },
+                "settings": {
+                    "$ref": "#/definitions/handler.StorefrontSettings"
+                }
+            }
+        },
+        "handler.StorefrontSettings": {
+            "type": "object",
+            "properties": {
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "min_order_amount": {
+                    "description": "smallest items subtotal for delivery orders",
+                    "type": "string",
+                    "example": "15.00"
+                },
+                "operating_hours_enabled": {
+                    "type": "boolean"
+                },
+                "tax_rate": {
+                    "type": "number",
+                    "example": 8.5
+                }
+            }
+        },
+        "handler.Table": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "qr_code_url": {
+                    "type": "string"
+                },
+                "regenerate_qr": {
+                    "type": "boolean"
+                },
+                "restaurant_id": {
+                    "type": "integer"
+                },
+                "table_number": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.UpdateUserRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "description": "required: true",
+                    "type": "string",
+                    "example": "staff"
+                }
+            }
+        },
+        "handler.User": {
             "type": "object",
-            "additionalProperties": true
+            "properties": {
+                "email": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
         },
-        "handler.LoginRequest": {
+        "handler.UserProfile": {
             "type": "object",
             "properties": {
-                "password": {
-                    "description": "required: true",
+                "email": {
                     "type": "string",
-                    "example": "password123"
+                    "example": "john@example.com"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "owner"
                 },
                 "username": {
-                    "description": "required: true",
                     "type": "string",
                     "example": "john_doe"
                 }
             }
         },
-        "handler.MenuItem": {
+        "handler.WebSocketTokenResponse": {
+            "type": "object",
+            "properties": {
+                "websocket_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.MenuItem": {
             "type": "object",
             "properties": {
+                "allergens": {
+                    "description": "JSON array from utils.Allergens",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
                 "category": {
+                    "description": "name of the linked MenuCategory, kept for display",
                     "type": "string"
                 },
+                "categoryID": {
+                    "type": "integer"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
                 "description": {
                     "type": "string"
                 },
+                "dietaryTags": {
+                    "description": "JSON array from utils.DietaryTags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "featuredOrder": {
+                    "description": "position among featured items, ascending",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "image_url": {
+                "imageURL": {
                     "type": "string"
                 },
+                "isFeatured": {
+                    "type": "boolean"
+                },
                 "name": {
                     "type": "string"
                 },
+                "orderItems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OrderItem"
+                    }
+                },
                 "price": {
-                    "type": "number"
+                    "description": "in cents",
+                    "type": "integer"
                 },
                 "quantity": {
+                    "description": "available quantity of the menu item",
                     "type": "integer"
                 },
-                "restaurant_id": {
+                "restaurantID": {
+                    "type": "integer"
+                },
+                "sku": {
+                    "description": "owner's external inventory code",
+                    "type": "string"
+                },
+                "station": {
+                    "description": "lowercase kitchen station it is prepared at, e.g. grill or bar; empty for none",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "version": {
+                    "description": "bumped on every write, for optimistic locking",
                     "type": "integer"
                 }
             }
         },
-        "handler.Order": {
+        "models.Order": {
             "type": "object",
             "properties": {
-                "customer_name": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "customerName": {
+                    "description": "Name of the customer who placed the order",
                     "type": "string"
                 },
+                "customerPhone": {
+                    "description": "optional, checked with utils.ValidatePhone",
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
+                "deliveryAddress": {
+                    "description": "set on delivery orders only",
+                    "type": "string"
+                },
+                "discount": {
+                    "description": "in cents, order-level discount set by staff",
+                    "type": "integer"
+                },
+                "groupID": {
+                    "description": "set when merged with other tables' orders to be paid together",
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "order_items": {
+                "orderItems": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.OrderItem"
+                        "$ref": "#/definitions/models.OrderItem"
                     }
                 },
-                "status": {
+                "orderRef": {
+                    "description": "daily reference staff call out, e.g. A-017; empty on orders from before references",
                     "type": "string"
                 },
-                "table_id": {
-                    "type": "integer"
+                "orderType": {
+                    "description": "see constants.OrderType*",
+                    "type": "string"
                 },
-                "total_amount": {
-                    "type": "number"
-                }
-            }
-        },
-        "handler.OrderItem": {
-            "type": "object",
-            "properties": {
-                "id": {
+                "payments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Payment"
+                    }
+                },
+                "restaurantID": {
+                    "description": "owning restaurant, also for orders without a table",
                     "type": "integer"
                 },
-                "menu_item_id": {
+                "scheduledFor": {
+                    "description": "when a pre-order is wanted; nil for orders wanted now",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "see constants.OrderStatus*",
+                    "type": "string"
+                },
+                "tableID": {
+                    "description": "nil for takeaway and delivery orders, which aren't served at a table",
                     "type": "integer"
                 },
-                "order_id": {
+                "tip": {
+                    "description": "in cents",
                     "type": "integer"
                 },
-                "quantity": {
+                "totalAmount": {
+                    "description": "in cents: items subtotal - discount + tip",
                     "type": "integer"
                 },
-                "special_instructions": {
+                "updatedAt": {
                     "type": "string"
                 }
             }
         },
-        "handler.OrderResponse": {
+        "models.OrderItem": {
             "type": "object",
             "properties": {
-                "customer_name": {
+                "createdAt": {
                     "type": "string"
                 },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "order_items": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.OrderItem"
-                    }
+                "itemName": {
+                    "description": "menu item name when the order was placed",
+                    "type": "string"
                 },
-                "restaurant_id": {
+                "menuItem": {
+                    "$ref": "#/definitions/models.MenuItem"
+                },
+                "menuItemID": {
                     "type": "integer"
                 },
-                "restaurant_name": {
+                "orderID": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "specialInstructions": {
                     "type": "string"
                 },
                 "status": {
+                    "description": "pending until the kitchen marks the item ready",
                     "type": "string"
                 },
-                "table_id": {
+                "unitPrice": {
+                    "description": "in cents, menu item price when the order was placed",
                     "type": "integer"
                 },
-                "total_amount": {
-                    "type": "number"
-                }
-            }
-        },
-        "handler.OrderStatusUpdate": {
-            "type": "object",
-            "properties": {
-                "status": {
-                    "type": "string",
-                    "example": "completed"
-                }
-            }
-        },
-        "handler.RefreshRequest": {
-            "type": "object",
-            "properties": {
-                "refresh_token": {
-                    "description": "required: true",
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.refresh..."
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "handler.RegisterRequest": {
+        "models.Payment": {
             "type": "object",
             "properties": {
-                "email": {
-                    "description": "required: true",
-                    "type": "string",
-                    "example": "john@example.com"
+                "amount": {
+                    "description": "in cents",
+                    "type": "integer"
                 },
-                "password": {
-                    "description": "required: true",
-                    "type": "string",
-                    "example": "password123"
+                "createdAt": {
+                    "type": "string"
                 },
-                "role": {
-                    "type": "string",
-                    "example": "owner"
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
                 },
-                "username": {
-                    "description": "required: true",
-                    "type": "string",
-                    "example": "john_doe"
+                "id": {
+                    "type": "integer"
+                },
+                "orderID": {
+                    "type": "integer"
+                },
+                "paymentDate": {
+                    "type": "string"
+                },
+                "paymentMethod": {
+                    "description": "credit_card, mobile_wallet, paypal, cash",
+                    "type": "string"
+                },
+                "paymentStatus": {
+                    "description": "pending, completed, failed",
+                    "type": "string"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "handler.Restaurant": {
+        "models.Restaurant": {
             "type": "object",
             "properties": {
                 "address": {
                     "type": "string"
                 },
+                "createdAt": {
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "logo_url": {
+                "logoURL": {
                     "type": "string"
                 },
+                "menuItems": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.MenuItem"
+                    }
+                },
                 "name": {
                     "type": "string"
                 },
-                "phone_number": {
+                "phoneNumber": {
                     "type": "string"
                 },
-                "user_id": {
+                "settings": {
+                    "$ref": "#/definitions/models.RestaurantSettings"
+                },
+                "tables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Table"
+                    }
+                },
+                "updatedAt": {
+                    "type": "string"
+                },
+                "userID": {
+                    "description": "Link restaurant to a user (owner)",
                     "type": "integer"
                 }
             }
         },
-        "handler.Table": {
+        "models.RestaurantSettings": {
             "type": "object",
             "properties": {
+                "createdAt": {
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "ISO 4217 code",
+                    "type": "string"
+                },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "qr_code_url": {
+                "lowStockThreshold": {
+                    "description": "quantity at which items count as low on stock; the handler default of 5 keeps 0 storable",
+                    "type": "integer"
+                },
+                "maxActiveOrders": {
+                    "description": "kitchen capacity: active orders at which customers' orders are turned away; 0 for no limit",
+                    "type": "integer"
+                },
+                "minOrderAmount": {
+                    "description": "in cents, smallest items subtotal accepted for delivery orders; 0 for none",
+                    "type": "integer"
+                },
+                "operatingHoursEnabled": {
+                    "type": "boolean"
+                },
+                "orderRefPrefix": {
+                    "description": "letters before the daily number in order references, e.g. A-017",
                     "type": "string"
                 },
-                "restaurant_id": {
+                "prepBufferMinutes": {
+                    "description": "extra minutes added to preparation estimates",
                     "type": "integer"
                 },
-                "table_number": {
+                "restaurantID": {
                     "type": "integer"
-                }
-            }
-        },
-        "handler.TokenResponse": {
-            "type": "object",
-            "properties": {
-                "access_token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
                 },
-                "refresh_token": {
-                    "type": "string",
-                    "example": "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.refresh..."
+                "taxRate": {
+                    "description": "percent shown to customers; order totals don't include it",
+                    "type": "number"
+                },
+                "updatedAt": {
+                    "type": "string"
                 }
             }
         },
-        "handler.User": {
+        "models.Table": {
             "type": "object",
             "properties": {
-                "email": {
+                "createdAt": {
                     "type": "string"
                 },
+                "deletedAt": {
+                    "$ref": "#/definitions/gorm.DeletedAt"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "role": {
+                "orders": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Order"
+                    }
+                },
+                "qrcodeURL": {
                     "type": "string"
                 },
-                "username": {
+                "restaurantID": {
+                    "type": "integer"
+                },
+                "tableNumber": {
+                    "type": "integer"
+                },
+                "updatedAt": {
                     "type": "string"
                 }
             }
+        },
+        "version.Info": {
+            "type": "object",
+            "properties": {
+                "build_time": {
+                    "type": "string",
+                    "example": "2024-03-01T12:00:00Z"
+                },
+                "commit": {
+                    "type": "string",
+                    "example": "3f2c1ab"
+                },
+                "version": {
+                    "type": "string",
+                    "example": "1.4.0"
+                }
+            }
         }
     },
     "securityDefinitions": {
         "BearerAuth": {
+            "description": "Fallback for clients without cookies: \"Bearer \" followed by the access_token cookie's value",
             "type": "apiKey",
             "name": "Authorization",
             "in": "header"
@@ -1673,11 +6350,11 @@ const docTemplate = `{
 // SwaggerInfo holds exported Swagger Info so clients can modify it
 var SwaggerInfo = &swag.Spec{
 	Version:          "1.0",
-	Host:             "localhost:3000",
+	Host:             "",
 	BasePath:         "/",
 	Schemes:          []string{},
 	Title:            "Order System API",
-	Description:      "API for Order System with user authentication and restaurant management",
+	Description:      "API for Order System with user authentication and restaurant management. Protected endpoints read the access_token cookie set by POST /api/user/login, which the browser sends when these docs are served by the API they describe; Swagger 2.0 can't describe cookie auth, so only the header fallback appears below.",
 	InfoInstanceName: "swagger",
 	SwaggerTemplate:  docTemplate,
 	LeftDelim:        "{{",
@@ -3,10 +3,11 @@ package handler
 import (
 	"errors"
 	"fmt"
-	"order-system/database"
+	"order-system/constants"
 	"order-system/models"
 	"order-system/utils"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,7 +16,6 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
 )
 
 // HealthCheck godoc
@@ -23,14 +23,15 @@ import (
 // @Description Check if the API is running
 // @Tags Health
 // @Produce json
-// @Success 200 {object} utils.APIResponse
+// @Success 200 {object} Envelope[HealthStatus]
 // @Router /health [get]
 func HealthCheck(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"status":  "ok",
-			"message": "Welcome to the Order-System API",
+		"data": HealthStatus{
+			Status:             "ok",
+			Message:            "Welcome to the Order-System API",
+			OrderEventsDropped: DroppedOrderEvents(),
 		},
 		"error": nil,
 	})
@@ -46,6 +47,15 @@ func getEnvOrDefault(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvIntOrDefault reads a positive integer from the environment, falling back to a default
+func getEnvIntOrDefault(key string, defaultValue int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
+
 // Generate Access Token
 func generateAccessToken(username string) (string, error) {
 	token := jwt.New(jwt.SigningMethodHS256)
@@ -78,104 +88,76 @@ func generateRefreshToken(username string) (string, error) {
 
 // Register godoc
 // @Summary Register a new user
-// @Description Register a new user account
+// @Description Register a new owner account. Any role in the request is ignored; admins assign other roles with PATCH /api/user/{id}/role.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Param user body RegisterRequest true "User registration data"
-// @Success 201 {object} fiber.Map
-// @Failure 400 {string} string "Invalid input"
-// @Failure 409 {string} string "Username or email already taken"
+// @Success 201 {object} Envelope[RegisterResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 409 {object} ErrorEnvelope "Username or email already taken"
 // @Router /api/user/register [post]
 func Register(c *fiber.Ctx) error {
 	var registerRequest struct {
 		Username string `json:"username"`
 		Password string `json:"password"`
 		Email    string `json:"email"`
-		Role     string `json:"role"`
 	}
 
 	// Parse the registration data
 	if err := c.BodyParser(&registerRequest); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
 	}
 
 	// Check if user already exists
 	var existingUser models.User
-	err := database.DB.Where("username = ?", registerRequest.Username).First(&existingUser).Error
+	err := db(c).Where("username = ?", registerRequest.Username).First(&existingUser).Error
 	if err == nil {
-		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Username already taken",
-		})
+		return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeUsernameTaken, "Username already taken")
 	} else if err != gorm.ErrRecordNotFound {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Database error",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Database error")
 	}
 
 	// Check if email already exists
 	var existingEmailUser models.User
-	err = database.DB.Where("email = ?", registerRequest.Email).First(&existingEmailUser).Error
+	err = db(c).Where("email = ?", registerRequest.Email).First(&existingEmailUser).Error
 	if err == nil {
-		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Email already registered",
-		})
+		return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeEmailTaken, "Email already registered")
 	} else if err != gorm.ErrRecordNotFound {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Database error",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Database error")
 	}
 
 	// Hash the password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(registerRequest.Password), bcrypt.DefaultCost)
+	hashedPassword, err := utils.HashPassword(registerRequest.Password)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error hashing password",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error hashing password")
 	}
 
-	// Create a new user and save to the database
-	role := registerRequest.Role
-	if role == "" {
-		role = "owner"
-	}
+	// Create a new user and save to the database. Self-registered accounts are always owners,
+	// so an unauthenticated request can't grant itself staff or admin rights.
 	user := models.User{
 		Username: registerRequest.Username,
-		Password: string(hashedPassword),
+		Password: hashedPassword,
 		Email:    registerRequest.Email,
-		Role:     role,
+		Role:     constants.RoleOwner,
 	}
 
-	if err := database.DB.Create(&user).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error creating user",
-		})
+	if err := db(c).Create(&user).Error; err != nil {
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error creating user"
+		}
+		return utils.SendError(c, status, code, message)
 	}
 
 	// Return success response
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"user_id":  user.ID,
-			"username": user.Username,
-			"email":    user.Email,
-			"role":     user.Role,
+		"data": RegisterResponse{
+			UserID:   user.ID,
+			Username: user.Username,
+			Email:    user.Email,
+			Role:     user.Role,
 		},
 		"error": nil,
 	})
@@ -188,9 +170,9 @@ func Register(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param credentials body LoginRequest true "Login credentials"
-// @Success 200 {object} fiber.Map
-// @Failure 401 {string} string "Invalid username or password"
-// @Failure 429 {string} string "Rate limit exceeded"
+// @Success 200 {object} Envelope[LoginResponse]
+// @Failure 401 {object} ErrorEnvelope "Invalid username or password"
+// @Failure 429 {object} ErrorEnvelope "Rate limit exceeded"
 // @Router /api/user/login [post]
 func Login(c *fiber.Ctx) error {
 	var loginRequest struct {
@@ -199,40 +181,24 @@ func Login(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&loginRequest); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
 	}
 
 	// Check brute force protection
 	if !utils.CheckBruteForce(loginRequest.Username) {
-		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Too many failed login attempts. Account locked temporarily.",
-		})
+		return utils.SendError(c, fiber.StatusTooManyRequests, constants.ErrCodeAccountLocked, "Too many failed login attempts. Account locked temporarily.")
 	}
 
 	var dbUser models.User
-	err := database.DB.Where("username = ?", loginRequest.Username).Preload("Restaurants").First(&dbUser).Error
+	err := db(c).Where("username = ?", loginRequest.Username).Preload("Restaurants").First(&dbUser).Error
 	if err != nil {
 		// Return generic error to prevent username enumeration
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid username or password",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeInvalidCredentials, "Invalid username or password")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(dbUser.Password), []byte(loginRequest.Password)); err != nil {
+	if !utils.CheckPassword(dbUser.Password, loginRequest.Password) {
 		// Login failed, don't record successful login
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid username or password",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeInvalidCredentials, "Invalid username or password")
 	}
 
 	// Login successful, record this to reset brute force attempts
@@ -241,20 +207,12 @@ func Login(c *fiber.Ctx) error {
 	// Generate Secure Access and Refresh Tokens
 	accessToken, err := utils.GenerateSecureAccessToken(dbUser.ID, loginRequest.Username)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error generating access token",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error generating access token")
 	}
 
 	refreshToken, err := utils.GenerateSecureRefreshToken(dbUser.ID, loginRequest.Username)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error generating refresh token",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error generating refresh token")
 	}
 
 	// Set tokens as HttpOnly, Secure cookies
@@ -262,26 +220,26 @@ func Login(c *fiber.Ctx) error {
 	utils.SetSecureCookie(c, "refresh_token", refreshToken, 30*24*60*60) // 30 days
 
 	// Build restaurant response (nil if no restaurant)
-	var restaurantData interface{}
+	var restaurantData *LoginRestaurant
 	if len(dbUser.Restaurants) > 0 {
 		firstRestaurant := dbUser.Restaurants[0]
-		restaurantData = fiber.Map{
-			"restaurant_id": firstRestaurant.ID,
-			"name":          firstRestaurant.Name,
-			"address":       firstRestaurant.Address,
-			"phone_number":  firstRestaurant.PhoneNumber,
+		restaurantData = &LoginRestaurant{
+			RestaurantID: firstRestaurant.ID,
+			Name:         firstRestaurant.Name,
+			Address:      firstRestaurant.Address,
+			PhoneNumber:  firstRestaurant.PhoneNumber,
 		}
 	}
 
 	// Return response without tokens in the body (they're in cookies)
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"user_id":    dbUser.ID,
-			"username":   dbUser.Username,
-			"email":      dbUser.Email,
-			"role":       dbUser.Role,
-			"restaurant": restaurantData,
+		"data": LoginResponse{
+			UserID:     dbUser.ID,
+			Username:   dbUser.Username,
+			Email:      dbUser.Email,
+			Role:       dbUser.Role,
+			Restaurant: restaurantData,
 		},
 		"error": nil,
 	})
@@ -300,40 +258,24 @@ func ProtectRoute(c *fiber.Ctx) error {
 	}
 
 	if tokenString == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "No access token provided",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "No access token provided")
 	}
 
 	claims, err := utils.ValidateAccessToken(tokenString)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid or expired access token",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "Invalid or expired access token")
 	}
 
 	// Store user info in context
 	username, ok := claims["username"].(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid token claims",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "Invalid token claims")
 	}
 
 	// Get user_id from claims
 	userID, ok := claims["user_id"].(float64)  // JWT numbers are float64
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid token user ID",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "Invalid token user ID")
 	}
 
 	c.Locals("username", username)
@@ -348,28 +290,24 @@ func ProtectRoute(c *fiber.Ctx) error {
 // @Tags User
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} User
-// @Failure 401 {string} string "Unauthorized"
+// @Success 200 {object} Envelope[UserProfile]
+// @Failure 401 {object} ErrorEnvelope "Unauthorized"
 // @Router /api/user/profile [get]
 func Profile(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 
 	var user models.User
-	err := database.DB.Where("username = ?", username).First(&user).Error
+	err := db(c).Where("username = ?", username).First(&user).Error
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Could not retrieve user profile",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Could not retrieve user profile")
 	}
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"username": user.Username,
-			"email":    user.Email,
-			"role":     user.Role,
+		"data": UserProfile{
+			Username: user.Username,
+			Email:    user.Email,
+			Role:     user.Role,
 		},
 		"error": nil,
 	})
@@ -382,59 +320,39 @@ func Profile(c *fiber.Ctx) error {
 // @Accept json
 // @Produce json
 // @Param refresh body RefreshRequest true "Refresh token"
-// @Success 200 {object} TokenResponse
-// @Failure 401 {string} string "Invalid or expired refresh token"
+// @Success 200 {object} Envelope[MessageResponse]
+// @Failure 401 {object} ErrorEnvelope "Invalid or expired refresh token"
 // @Router /api/user/refresh [post]
 func RefreshToken(c *fiber.Ctx) error {
 	// Get refresh token from cookie
 	refreshTokenString := c.Cookies("refresh_token")
 	if refreshTokenString == "" {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "No refresh token provided",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "No refresh token provided")
 	}
 
 	// Validate the refresh token
 	claims, err := utils.ValidateRefreshToken(refreshTokenString)
 	if err != nil {
 		utils.ClearSecureCookie(c, "refresh_token") // Clear invalid token
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid or expired refresh token",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "Invalid or expired refresh token")
 	}
 
 	// Extract user info from the refresh token's claims
 	username, ok := claims["username"].(string)
 	userID, ok2 := claims["user_id"].(float64)
 	if !ok || !ok2 {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid token claims",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "Invalid token claims")
 	}
 
 	// Generate new access and refresh tokens
 	newAccessToken, err := utils.GenerateSecureAccessToken(uint(userID), username)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error generating new access token",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error generating new access token")
 	}
 
 	newRefreshToken, err := utils.GenerateSecureRefreshToken(uint(userID), username)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error generating new refresh token",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error generating new refresh token")
 	}
 
 	// Set the new tokens as HttpOnly, Secure cookies
@@ -444,8 +362,8 @@ func RefreshToken(c *fiber.Ctx) error {
 	// Return success response
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"message": "Tokens refreshed successfully",
+		"data": MessageResponse{
+			Message: "Tokens refreshed successfully",
 		},
 		"error": nil,
 	})
@@ -487,18 +405,14 @@ func ValidateAccessToken(tokenString string) (string, error) {
 // @Description Get all registered users
 // @Tags User
 // @Produce json
-// @Success 200 {array} User
-// @Failure 500 {string} string "Could not retrieve users"
+// @Success 200 {object} Envelope[[]User]
+// @Failure 500 {object} ErrorEnvelope "Could not retrieve users"
 // @Router /api/user/ [get]
 func GetAllUsers(c *fiber.Ctx) error {
 	var users []models.User
-	err := database.DB.Find(&users).Error
+	err := db(c).Find(&users).Error
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Could not retrieve users",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Could not retrieve users")
 	}
 
 	return c.JSON(fiber.Map{
@@ -510,31 +424,63 @@ func GetAllUsers(c *fiber.Ctx) error {
 
 // DeleteUser godoc
 // @Summary Delete user
-// @Description Delete the authenticated user
+// @Description Delete the authenticated user together with their restaurants, tables, menu items and orders. The username must be retyped to confirm.
 // @Tags User
+// @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} string
-// @Failure 500 {string} string "Could not retrieve or delete user"
+// @Param confirmation body DeleteUserRequest true "Username confirmation"
+// @Success 200 {object} Envelope[string]
+// @Failure 400 {object} ErrorEnvelope "Confirmation does not match username"
+// @Failure 500 {object} ErrorEnvelope "Could not retrieve or delete user"
 // @Router /api/user/ [delete]
 func DeleteUser(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 
+	var request DeleteUserRequest
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if request.ConfirmUsername != username {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Confirmation does not match username")
+	}
+
 	var user models.User
-	err := database.DB.Where("username = ?", username).First(&user).Error
+	err := db(c).Where("username = ?", username).First(&user).Error
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Could not retrieve user",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Could not retrieve user")
+	}
+
+	// Collect active and scheduled orders up front so open dashboards can be told they are gone
+	var restaurants []models.Restaurant
+	if err := db(c).Where("user_id = ?", user.ID).Find(&restaurants).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Could not retrieve user")
+	}
+	var deletedOrders []OrderResponse
+	for i := range restaurants {
+		var orders []models.Order
+		if err := db(c).Where("restaurant_id = ? AND status IN ?", restaurants[i].ID, constants.OpenOrderStatuses).
+			Preload("Table").
+			Preload("OrderItems").
+			Find(&orders).Error; err != nil {
+			return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Could not retrieve user")
+		}
+		for _, order := range orders {
+			deletedOrders = append(deletedOrders, buildOrderResponse(order, &restaurants[i]))
+		}
+	}
+
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		return deleteUserCascade(tx, user)
+	}); err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Could not delete user")
+	}
+
+	for _, order := range deletedOrders {
+		globalOrderHub.publish("order_deleted", order)
 	}
-	if err := database.DB.Delete(&user).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Could not delete user",
-		})
+	for _, restaurant := range restaurants {
+		globalMenuCache.invalidate(restaurant.ID)
 	}
 
 	return c.JSON(fiber.Map{
@@ -544,13 +490,50 @@ func DeleteUser(c *fiber.Ctx) error {
 	})
 }
 
+// deleteUserCascade soft-deletes a user and everything they own: restaurants, their tables
+// and menu items, and the orders, order groups, order items and payments of those restaurants.
+// Audit entries are kept as the record of what happened, detached from the user with user ID 0.
+func deleteUserCascade(tx *gorm.DB, user models.User) error {
+	restaurantIDs := tx.Model(&models.Restaurant{}).Select("id").Where("user_id = ?", user.ID)
+	orderIDs := tx.Model(&models.Order{}).Select("id").Where("restaurant_id IN (?)", restaurantIDs)
+
+	// Children go first so the subqueries, which skip soft-deleted rows, still match their parents
+	steps := []func() error{
+		func() error { return tx.Where("order_id IN (?)", orderIDs).Delete(&models.OrderItem{}).Error },
+		func() error { return tx.Where("order_id IN (?)", orderIDs).Delete(&models.Payment{}).Error },
+		func() error { return tx.Where("restaurant_id IN (?)", restaurantIDs).Delete(&models.Order{}).Error },
+		func() error { return tx.Where("restaurant_id IN (?)", restaurantIDs).Delete(&models.OrderGroup{}).Error },
+		func() error { return tx.Where("restaurant_id IN (?)", restaurantIDs).Delete(&models.Table{}).Error },
+		func() error {
+			return tx.Where("restaurant_id IN (?)", restaurantIDs).Delete(&models.InventoryAdjustment{}).Error
+		},
+		func() error { return tx.Where("restaurant_id IN (?)", restaurantIDs).Delete(&models.StockMovement{}).Error },
+		func() error { return tx.Where("restaurant_id IN (?)", restaurantIDs).Delete(&models.MenuItem{}).Error },
+		func() error { return tx.Where("restaurant_id IN (?)", restaurantIDs).Delete(&models.MenuCategory{}).Error },
+		func() error {
+			return tx.Where("restaurant_id IN (?)", restaurantIDs).Delete(&models.RestaurantSettings{}).Error
+		},
+		func() error {
+			return tx.Model(&models.AuditLog{}).Where("user_id = ?", user.ID).Update("user_id", 0).Error
+		},
+		func() error { return tx.Where("user_id = ?", user.ID).Delete(&models.Restaurant{}).Error },
+		func() error { return tx.Delete(&user).Error },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Logout godoc
 // @Summary User logout
 // @Description Clear user's tokens
 // @Tags User
 // @Produce json
-// @Success 200 {object} fiber.Map
-// @Failure 500 {object} fiber.Map
+// @Success 200 {object} Envelope[MessageResponse]
+// @Failure 500 {object} ErrorEnvelope
 // @Router /api/user/logout [post]
 func Logout(c *fiber.Ctx) error {
 	// Clear the secure tokens from cookies
@@ -559,8 +542,8 @@ func Logout(c *fiber.Ctx) error {
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"message": "Successfully logged out",
+		"data": MessageResponse{
+			Message: "Successfully logged out",
 		},
 		"error": nil,
 	})
@@ -571,18 +554,15 @@ func Logout(c *fiber.Ctx) error {
 // @Description Get a temporary token for WebSocket authentication
 // @Tags User
 // @Produce json
-// @Success 200 {object} fiber.Map
-// @Failure 401 {object} fiber.Map
+// @Security BearerAuth
+// @Success 200 {object} Envelope[WebSocketTokenResponse]
+// @Failure 401 {object} ErrorEnvelope
 // @Router /api/user/websocket-token [get]
 func GetWebSocketToken(c *fiber.Ctx) error {
 	// Get username from context (set by ProtectRoute middleware)
 	username, ok := c.Locals("username").(string)
 	if !ok {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid user context",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "Invalid user context")
 	}
 
 	// The user_id from JWT claims comes as float64, need to convert properly
@@ -592,11 +572,7 @@ func GetWebSocketToken(c *fiber.Ctx) error {
 		if userIDVal, ok := c.Locals("user_id").(uint); ok {
 			return generateWebSocketTokenResponse(c, userIDVal, username)
 		}
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid user ID context",
-		})
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeUnauthorized, "Invalid user ID context")
 	}
 
 	return generateWebSocketTokenResponse(c, uint(userIDFloat), username)
@@ -608,17 +584,13 @@ func generateWebSocketTokenResponse(c *fiber.Ctx, userID uint, username string)
 	// This token will have a short expiration and specific purpose
 	websocketToken, err := utils.GenerateSecureWebSocketToken(userID, username)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error generating WebSocket token",
-		})
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error generating WebSocket token")
 	}
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data": fiber.Map{
-			"websocket_token": websocketToken,
+		"data": WebSocketTokenResponse{
+			WebSocketToken: websocketToken,
 		},
 		"error": nil,
 	})
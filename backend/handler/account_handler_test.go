@@ -1,9 +1,15 @@
 package handler
 
 import (
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
 	"order-system/utils"
+	"strings"
 	"testing"
 	"time"
 
@@ -118,3 +124,164 @@ func TestProtectRouteMissingToken(t *testing.T) {
 		t.Fatalf("expected status 401, got %d", resp.StatusCode)
 	}
 }
+
+func TestDeleteUserRequiresConfirmation(t *testing.T) {
+	app := fiber.New()
+	app.Delete("/user", func(c *fiber.Ctx) error {
+		c.Locals("username", "tester")
+		return DeleteUser(c)
+	})
+
+	for _, body := range []string{"", `{}`, `{"confirm_username":"someone_else"}`} {
+		req := httptest.NewRequest(http.MethodDelete, "/user", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Fatalf("body %q: expected status 400, got %d", body, resp.StatusCode)
+		}
+	}
+}
+
+func TestRegisterReusesDeletedUsernameAndEmail(t *testing.T) {
+	testutil.SetupDB(t)
+	app := setupTestApp()
+
+	register := func() int {
+		body := `{"username":"testuser_reuse","password":"testpassword123","email":"reuse@example.com"}`
+		req := httptest.NewRequest(http.MethodPost, "/api/user/register", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := register(); status != fiber.StatusCreated {
+		t.Fatalf("first registration: expected status 201, got %d", status)
+	}
+	if status := register(); status != fiber.StatusConflict {
+		t.Fatalf("duplicate registration: expected status 409, got %d", status)
+	}
+
+	// Deleting the account only soft-deletes the row
+	var user models.User
+	if err := database.DB.Where("username = ?", "testuser_reuse").First(&user).Error; err != nil {
+		t.Fatalf("loading registered user: %v", err)
+	}
+	entry := models.AuditLog{UserID: user.ID, RestaurantID: 1, Action: constants.AuditActionUpdate, Entity: constants.AuditEntityOrder, EntityID: 1}
+	database.DB.Create(&entry)
+	token, err := utils.GenerateSecureAccessToken(user.ID, user.Username)
+	if err != nil {
+		t.Fatalf("failed to generate token: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodDelete, "/api/user/", strings.NewReader(`{"confirm_username":"testuser_reuse"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("delete user: expected status 200, got %d", resp.StatusCode)
+	}
+	if err := database.DB.First(&entry, entry.ID).Error; err != nil || entry.UserID != 0 {
+		t.Fatalf("expected the audit entry to be kept without its user, got %+v (%v)", entry, err)
+	}
+
+	if status := register(); status != fiber.StatusCreated {
+		t.Fatalf("registration after delete: expected status 201, got %d", status)
+	}
+}
+
+func TestRegisterIgnoresRequestedRole(t *testing.T) {
+	testutil.SetupDB(t)
+	app := setupTestApp()
+
+	body := `{"username":"testuser_escalate","password":"testpassword123","email":"escalate@example.com","role":"admin"}`
+	req := httptest.NewRequest(http.MethodPost, "/api/user/register", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("expected status 201, got %d", resp.StatusCode)
+	}
+
+	var user models.User
+	if err := database.DB.Where("username = ?", "testuser_escalate").First(&user).Error; err != nil {
+		t.Fatalf("loading registered user: %v", err)
+	}
+	if user.Role != constants.RoleOwner {
+		t.Fatalf("expected self-registered user to be an owner, got %q", user.Role)
+	}
+}
+
+func TestUpdateUserRoleRequiresAdmin(t *testing.T) {
+	testutil.SetupDB(t)
+	app := setupTestApp()
+
+	admin := models.User{Username: "testuser_admin", Password: "x", Email: "admin@example.com", Role: constants.RoleAdmin}
+	owner := models.User{Username: "testuser_owner", Password: "x", Email: "owner@example.com", Role: constants.RoleOwner}
+	for _, user := range []*models.User{&admin, &owner} {
+		if err := database.DB.Create(user).Error; err != nil {
+			t.Fatalf("creating user: %v", err)
+		}
+	}
+
+	updateRole := func(caller models.User, target uint, role string) int {
+		token, err := utils.GenerateSecureAccessToken(caller.ID, caller.Username)
+		if err != nil {
+			t.Fatalf("failed to generate token: %v", err)
+		}
+		req := httptest.NewRequest(http.MethodPatch, fmt.Sprintf("/api/user/%d/role", target), strings.NewReader(fmt.Sprintf(`{"role":%q}`, role)))
+		req.Header.Set("Content-Type", "application/json")
+		req.AddCookie(&http.Cookie{Name: "access_token", Value: token})
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := updateRole(owner, owner.ID, constants.RoleAdmin); status != fiber.StatusForbidden {
+		t.Fatalf("owner promoting themselves: expected status 403, got %d", status)
+	}
+	if status := updateRole(admin, owner.ID, "superuser"); status != fiber.StatusBadRequest {
+		t.Fatalf("unknown role: expected status 400, got %d", status)
+	}
+	if status := updateRole(admin, 999999, constants.RoleStaff); status != fiber.StatusNotFound {
+		t.Fatalf("unknown user: expected status 404, got %d", status)
+	}
+	if status := updateRole(admin, admin.ID, constants.RoleOwner); status != fiber.StatusConflict {
+		t.Fatalf("demoting the last admin: expected status 409, got %d", status)
+	}
+	if status := updateRole(admin, owner.ID, constants.RoleStaff); status != fiber.StatusOK {
+		t.Fatalf("admin assigning staff: expected status 200, got %d", status)
+	}
+
+	database.DB.First(&owner, owner.ID)
+	if owner.Role != constants.RoleStaff {
+		t.Fatalf("expected role staff, got %q", owner.Role)
+	}
+	var entry models.AuditLog
+	if err := database.DB.Where("entity = ? AND entity_id = ?", constants.AuditEntityUser, owner.ID).First(&entry).Error; err != nil {
+		t.Fatalf("expected the role change to be audited: %v", err)
+	}
+	if entry.UserID != admin.ID {
+		t.Fatalf("expected the audit entry to name admin %d, got %d", admin.ID, entry.UserID)
+	}
+
+	// With a second admin, the first one may step down
+	if status := updateRole(admin, owner.ID, constants.RoleAdmin); status != fiber.StatusOK {
+		t.Fatalf("admin promoting a second admin: expected status 200, got %d", status)
+	}
+	if status := updateRole(admin, admin.ID, constants.RoleOwner); status != fiber.StatusOK {
+		t.Fatalf("admin stepping down: expected status 200, got %d", status)
+	}
+}
@@ -0,0 +1,22 @@
+package handler
+
+// apiError is a client error decided inside a transaction or helper, carrying the status and
+// error code it should be answered with. Any other error out of a transaction is a database failure.
+type apiError struct {
+	status  int
+	code    string
+	message string
+	data    interface{} // details for the response's data, as with utils.SendErrorWithData
+}
+
+func newAPIError(status int, code string, message string) *apiError {
+	return &apiError{status: status, code: code, message: message}
+}
+
+func newAPIErrorWithData(status int, code string, message string, data interface{}) *apiError {
+	return &apiError{status: status, code: code, message: message, data: data}
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
@@ -1,5 +1,41 @@
 package handler
 
+import (
+	"order-system/models"
+	"order-system/utils"
+	"time"
+)
+
+// Envelope is the body of every successful JSON response, with the payload in data
+type Envelope[T any] struct {
+	Success bool    `json:"success" example:"true"`
+	Data    T       `json:"data"`
+	Error   *string `json:"error"` // always null
+}
+
+// PageEnvelope is the body of a paginated list response
+type PageEnvelope[T any] struct {
+	Success    bool       `json:"success" example:"true"`
+	Data       []T        `json:"data"`
+	Pagination Pagination `json:"pagination"`
+	Error      *string    `json:"error"` // always null
+}
+
+// ErrorEnvelope is the body of every JSON error response
+type ErrorEnvelope struct {
+	Success bool        `json:"success" example:"false"`
+	Data    interface{} `json:"data"` // null, or details named in the endpoint's description such as missing_ids
+	Error   string      `json:"error" example:"Restaurant not found"`
+	Code    string      `json:"code" example:"RESTAURANT_NOT_FOUND"` // machine-readable, see constants.ErrCode*
+}
+
+// swagger:model HealthStatus
+type HealthStatus struct {
+	Status             string `json:"status" example:"ok"`
+	Message            string `json:"message" example:"Welcome to the Order-System API"`
+	OrderEventsDropped uint64 `json:"order_events_dropped"` // events the WebSocket hub dropped because its queue was full
+}
+
 // swagger:model RegisterRequest
 type RegisterRequest struct {
 	// required: true
@@ -7,8 +43,13 @@ type RegisterRequest struct {
 	// required: true
 	Password string `json:"password" example:"password123"`
 	// required: true
-	Email    string `json:"email" example:"john@example.com"`
-	Role     string `json:"role" example:"owner"`
+	Email string `json:"email" example:"john@example.com"`
+}
+
+// swagger:model UpdateUserRoleRequest
+type UpdateUserRoleRequest struct {
+	// required: true
+	Role string `json:"role" example:"staff"`
 }
 
 // swagger:model LoginRequest
@@ -19,10 +60,46 @@ type LoginRequest struct {
 	Password string `json:"password" example:"password123"`
 }
 
-// swagger:model TokenResponse
-type TokenResponse struct {
-	AccessToken  string `json:"access_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."`
-	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.refresh..."`
+// swagger:model RegisterResponse
+type RegisterResponse struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username" example:"john_doe"`
+	Email    string `json:"email" example:"john@example.com"`
+	Role     string `json:"role" example:"owner"`
+}
+
+// swagger:model LoginResponse
+type LoginResponse struct {
+	UserID     uint             `json:"user_id"`
+	Username   string           `json:"username" example:"john_doe"`
+	Email      string           `json:"email" example:"john@example.com"`
+	Role       string           `json:"role" example:"owner"`
+	Restaurant *LoginRestaurant `json:"restaurant"` // the user's first restaurant, null if they have none
+}
+
+// swagger:model LoginRestaurant
+type LoginRestaurant struct {
+	RestaurantID uint   `json:"restaurant_id"`
+	Name         string `json:"name"`
+	Address      string `json:"address"`
+	PhoneNumber  string `json:"phone_number"`
+}
+
+// swagger:model UserProfile
+type UserProfile struct {
+	Username string `json:"username" example:"john_doe"`
+	Email    string `json:"email" example:"john@example.com"`
+	Role     string `json:"role" example:"owner"`
+}
+
+// swagger:model MessageResponse
+type MessageResponse struct {
+	Message string `json:"message" example:"Successfully logged out"`
+}
+
+// swagger:model WebSocketTokenResponse
+type WebSocketTokenResponse struct {
+	WebSocketToken string `json:"websocket_token"`
 }
 
 // swagger:model RefreshRequest
@@ -31,6 +108,12 @@ type RefreshRequest struct {
 	RefreshToken string `json:"refresh_token" example:"eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.refresh..."`
 }
 
+// swagger:model DeleteUserRequest
+type DeleteUserRequest struct {
+	// required: true
+	ConfirmUsername string `json:"confirm_username" example:"john_doe"`
+}
+
 // swagger:model User
 type User struct {
 	ID       uint   `json:"id"`
@@ -49,43 +132,249 @@ type Restaurant struct {
 	LogoURL     string `json:"logo_url"`
 }
 
+// swagger:model RestaurantCloneRequest
+type RestaurantCloneRequest struct {
+	Name        string `json:"name" example:"Downtown Bistro"`
+	Address     string `json:"address"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+// swagger:model RestaurantCloneResponse
+type RestaurantCloneResponse struct {
+	Restaurant     models.Restaurant `json:"restaurant"`
+	MenuCategories int               `json:"menu_categories"`
+	MenuItems      int               `json:"menu_items"`
+	Tables         int               `json:"tables"`
+}
+
+// swagger:model RestaurantSettings
+type RestaurantSettings struct {
+	RestaurantID          uint        `json:"restaurant_id"`
+	TaxRate               float64     `json:"tax_rate" example:"8.5"` // percent for display; not added to order totals
+	Currency              string      `json:"currency" example:"USD"`
+	PrepBufferMinutes     int         `json:"prep_buffer_minutes" example:"5"`
+	LowStockThreshold     int         `json:"low_stock_threshold" example:"5"`
+	OperatingHoursEnabled bool        `json:"operating_hours_enabled"`
+	OrderRefPrefix        string      `json:"order_ref_prefix" example:"A"`
+	MinOrderAmount        utils.Money `json:"min_order_amount" swaggertype:"string" example:"15.00"` // smallest items subtotal for delivery orders; 0.00 for none
+	MaxActiveOrders       int         `json:"max_active_orders" example:"20"`                        // active orders at which customer orders are refused; 0 for no limit
+}
+
+// swagger:model RestaurantSettingsUpdate
+type RestaurantSettingsUpdate struct {
+	TaxRate               *float64     `json:"tax_rate" example:"8.5"`
+	Currency              *string      `json:"currency" example:"EUR"`
+	PrepBufferMinutes     *int         `json:"prep_buffer_minutes" example:"5"`
+	LowStockThreshold     *int         `json:"low_stock_threshold" example:"3"`
+	OperatingHoursEnabled *bool        `json:"operating_hours_enabled"`
+	OrderRefPrefix        *string      `json:"order_ref_prefix" example:"T"` // 1-3 letters, used from the next order on
+	MinOrderAmount        *utils.Money `json:"min_order_amount" swaggertype:"string" example:"15.00"`
+	MaxActiveOrders       *int         `json:"max_active_orders" example:"20"`
+}
+
+// swagger:model AuditLogEntry
+type AuditLogEntry struct {
+	ID           uint      `json:"id"`
+	UserID       uint      `json:"user_id"`
+	RestaurantID uint      `json:"restaurant_id"`
+	Action       string    `json:"action" example:"update"`
+	Entity       string    `json:"entity" example:"menu_item"`
+	EntityID     uint      `json:"entity_id"`
+	Details      string    `json:"details" example:"Updated menu item \"Margherita\""`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
 // swagger:model Table
 type Table struct {
 	ID           uint   `json:"id"`
 	RestaurantID uint   `json:"restaurant_id"`
 	TableNumber  int    `json:"table_number"`
 	QRCodeURL    string `json:"qr_code_url"`
+	RegenerateQR bool   `json:"regenerate_qr,omitempty"`
+}
+
+// swagger:model BatchTableRequest
+type BatchTableRequest struct {
+	Start   int   `json:"start" example:"1"`
+	Count   int   `json:"count" example:"20"`
+	Numbers []int `json:"numbers"`
+}
+
+// swagger:model BatchTableResponse
+type BatchTableResponse struct {
+	Created []models.Table `json:"created"`
+	Skipped []int          `json:"skipped"`
+}
+
+// swagger:model BatchDeleteMenuItemsRequest
+type BatchDeleteMenuItemsRequest struct {
+	IDs []uint `json:"ids" example:"3,7,12"`
+}
+
+// swagger:model BatchDeleteMenuItemsResponse
+type BatchDeleteMenuItemsResponse struct {
+	Deleted []uint `json:"deleted"`
 }
 
 // swagger:model MenuItem
 type MenuItem struct {
-	ID           uint    `json:"id"`
-	RestaurantID uint    `json:"restaurant_id"`
-	Name         string  `json:"name"`
-	Description  string  `json:"description"`
-	Price        float64 `json:"price"`
-	Category     string  `json:"category"`
-	ImageURL     string  `json:"image_url"`
-	Quantity     int     `json:"quantity"`
+	ID            uint        `json:"id"`
+	RestaurantID  uint        `json:"restaurant_id"`
+	SKU           *string     `json:"sku" example:"PIZ-001"`
+	Name          string      `json:"name"`
+	Description   string      `json:"description"`
+	Price         utils.Money `json:"price" swaggertype:"string" example:"12.50"`
+	Category      string      `json:"category"`
+	CategoryID    *uint       `json:"category_id"`
+	ImageURL      string      `json:"image_url"`
+	Quantity      int         `json:"quantity"`
+	DietaryTags   []string    `json:"dietary_tags" example:"vegan,gluten-free"`
+	Allergens     []string    `json:"allergens" example:"sesame"`
+	IsFeatured    bool        `json:"is_featured"`
+	FeaturedOrder int         `json:"featured_order" example:"1"`
+	Station       string      `json:"station" example:"grill"` // kitchen station, lowercase; empty for none
+	Version       int         `json:"version"`
+}
+
+// swagger:model MenuItemUpsert
+type MenuItemUpsert struct {
+	Name          string      `json:"name" example:"Margherita"`
+	Description   string      `json:"description"`
+	Price         utils.Money `json:"price" swaggertype:"string" example:"12.50"`
+	Category      string      `json:"category"`
+	CategoryID    *uint       `json:"category_id"`
+	ImageURL      string      `json:"image_url"`
+	Quantity      int         `json:"quantity"`
+	DietaryTags   []string    `json:"dietary_tags"`
+	Allergens     []string    `json:"allergens"`
+	IsFeatured    *bool       `json:"is_featured"`
+	FeaturedOrder *int        `json:"featured_order"`
+	Station       *string     `json:"station" example:"grill"`
+}
+
+// swagger:model StockAdjustmentRequest
+type StockAdjustmentRequest struct {
+	// required: true
+	Delta int `json:"delta" example:"-3"`
+	// required: true
+	Reason string `json:"reason" example:"spoilage"`
+}
+
+// swagger:model StockAdjustmentResponse
+type StockAdjustmentResponse struct {
+	AdjustmentID uint   `json:"adjustment_id"`
+	MenuItemID   uint   `json:"menu_item_id"`
+	Delta        int    `json:"delta" example:"-3"`
+	AppliedDelta int    `json:"applied_delta" example:"-2"`
+	Quantity     int    `json:"quantity" example:"0"`
+	Reason       string `json:"reason" example:"spoilage"`
+}
+
+// swagger:model StockMovementEntry
+type StockMovementEntry struct {
+	ID           uint      `json:"id"`
+	Type         string    `json:"type" example:"order"`
+	Delta        int       `json:"delta" example:"-2"`
+	Balance      int       `json:"balance" example:"18"`
+	OrderID      *uint     `json:"order_id"`
+	AdjustmentID *uint     `json:"adjustment_id"`
+	Note         string    `json:"note"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// swagger:model MenuCategory
+type MenuCategory struct {
+	ID           uint   `json:"id"`
+	RestaurantID uint   `json:"restaurant_id"`
+	Name         string `json:"name" example:"Desserts"`
+	DisplayOrder int    `json:"display_order" example:"3"`
+}
+
+// swagger:model PublicMenuItem
+type PublicMenuItem struct {
+	models.MenuItem
+	RemainingQuantity    int  `json:"remaining_quantity"` // stock left to order
+	InStock              bool `json:"in_stock"`
+	CategoryDisplayOrder *int `json:"category_display_order"` // nil for uncategorized items
+	IsNew                bool `json:"is_new"`                 // added within the last MENU_NEW_ITEM_DAYS days
+}
+
+// swagger:model Storefront
+type Storefront struct {
+	Restaurant models.Restaurant  `json:"restaurant"` // with its tables, as from GET /api/restaurant/{id}
+	Settings   StorefrontSettings `json:"settings"`
+	Featured   []PublicMenuItem   `json:"featured"` // in featured order
+	Menu       []PublicMenuItem   `json:"menu"`     // as from GET /api/restaurants/{restaurant_id}/menu
+}
+
+// swagger:model RestaurantSearchResults
+type RestaurantSearchResults struct {
+	Query     string            `json:"query"`      // the trimmed search text
+	MenuItems []models.MenuItem `json:"menu_items"` // name or description matches, by name
+	Tables    []models.Table    `json:"tables"`     // tables whose number starts with the query, by number
+}
+
+// swagger:model StorefrontSettings
+type StorefrontSettings struct {
+	Currency              string      `json:"currency" example:"USD"`
+	TaxRate               float64     `json:"tax_rate" example:"8.5"`
+	OperatingHoursEnabled bool        `json:"operating_hours_enabled"`
+	MinOrderAmount        utils.Money `json:"min_order_amount" swaggertype:"string" example:"15.00"` // smallest items subtotal for delivery orders
+}
+
+// swagger:model ActiveOrderCount
+type ActiveOrderCount struct {
+	ActiveCount int64 `json:"active_count" example:"4"`
 }
 
 // swagger:model Order
 type Order struct {
-	ID           uint        `json:"id"`
-	TableID      uint        `json:"table_id"`
-	CustomerName string      `json:"customer_name"`
-	Status       string      `json:"status"`
-	TotalAmount  float64     `json:"total_amount"`
-	OrderItems   []OrderItem `json:"order_items"`
+	ID              uint        `json:"id"`
+	OrderRef        string      `json:"order_ref" example:"A-017"` // daily per-restaurant reference staff call out
+	TableID         *uint       `json:"table_id"`                  // null for takeaway and delivery orders
+	TableNumber     int         `json:"table_number"`              // 0 when the order has no table
+	CustomerName    string      `json:"customer_name"`
+	CustomerPhone   string      `json:"customer_phone" example:"+1 555 010 2030"`
+	OrderType       string      `json:"order_type" example:"delivery" enums:"dine_in,takeaway,delivery"` // defaults to dine_in
+	DeliveryAddress string      `json:"delivery_address" example:"12 Harbour Road"`                      // required for delivery orders
+	Status          string      `json:"status" enums:"scheduled,active,out_for_delivery,delivered,paid,cancelled"`
+	ScheduledFor    *time.Time  `json:"scheduled_for"` // when a pre-order is wanted; null for orders wanted now
+	ItemCount       int         `json:"item_count"`    // total quantity across the order's items
+	Subtotal        utils.Money `json:"subtotal" swaggertype:"string" example:"35.00"`
+	Discount        utils.Money `json:"discount" swaggertype:"string" example:"2.50"`
+	Tip             utils.Money `json:"tip" swaggertype:"string" example:"5.00"`
+	TotalAmount     utils.Money `json:"total_amount" swaggertype:"string" example:"37.50"`
+	GroupID         *uint       `json:"group_id"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+	OrderItems      []OrderItem `json:"order_items"` // null when the list was requested with include_items=false
 }
 
 // swagger:model OrderItem
 type OrderItem struct {
-	ID                  uint   `json:"id"`
-	OrderID             uint   `json:"order_id"`
-	MenuItemID          uint   `json:"menu_item_id"`
-	Quantity            int    `json:"quantity"`
-	SpecialInstructions string `json:"special_instructions"`
+	ID                  uint        `json:"id"`
+	OrderID             uint        `json:"order_id"`
+	MenuItemID          uint        `json:"menu_item_id"`
+	Name                string      `json:"name"`
+	Price               utils.Money `json:"price" swaggertype:"string" example:"12.50"`
+	Quantity            int         `json:"quantity"`
+	LineTotal           utils.Money `json:"line_total" swaggertype:"string" example:"25.00"` // price * quantity
+	SpecialInstructions string      `json:"special_instructions"`
+	Status              string      `json:"status" enums:"pending,ready"`
+}
+
+// swagger:model OrderItemStatusUpdate
+type OrderItemStatusUpdate struct {
+	// required: true
+	Status string `json:"status" enums:"pending,ready" example:"ready"`
+}
+
+// swagger:model StockShortage
+type StockShortage struct {
+	MenuItemID uint   `json:"menu_item_id"`
+	Name       string `json:"name"`
+	Requested  int    `json:"requested"`
+	Available  int    `json:"available"`
 }
 
 // swagger:model OrderStatusUpdate
@@ -93,9 +382,132 @@ type OrderStatusUpdate struct {
 	Status string `json:"status" example:"completed"`
 }
 
+// swagger:model SplitPaymentRequest
+type SplitPaymentRequest struct {
+	// required: true
+	Amount utils.Money `json:"amount" swaggertype:"string" example:"12.50"`
+	// required: true
+	PaymentMethod string `json:"payment_method" example:"cash"`
+}
+
+// swagger:model SplitPaymentResponse
+type SplitPaymentResponse struct {
+	PaymentID     uint        `json:"payment_id"`
+	OrderID       uint        `json:"order_id"`
+	TotalAmount   utils.Money `json:"total_amount" swaggertype:"string" example:"37.50"`
+	AmountPaid    utils.Money `json:"amount_paid" swaggertype:"string" example:"25.00"`
+	Remaining     utils.Money `json:"remaining" swaggertype:"string" example:"12.50"`
+	OrderComplete bool        `json:"order_complete"`
+}
+
+// swagger:model MergeOrdersRequest
+type MergeOrdersRequest struct {
+	// required: true
+	OrderIDs []uint `json:"order_ids" example:"12,15"`
+}
+
+// swagger:model OrderGroupResponse
+type OrderGroupResponse struct {
+	ID           uint        `json:"id"`
+	RestaurantID uint        `json:"restaurant_id"`
+	TotalAmount  utils.Money `json:"total_amount" swaggertype:"string" example:"74.00"` // combined total of the orders that aren't cancelled
+	AmountPaid   utils.Money `json:"amount_paid" swaggertype:"string" example:"30.00"`
+	Remaining    utils.Money `json:"remaining" swaggertype:"string" example:"44.00"`
+	Orders       []Order     `json:"orders"`
+}
+
+// swagger:model GroupPaymentResponse
+type GroupPaymentResponse struct {
+	GroupID       uint        `json:"group_id"`
+	PaymentIDs    []uint      `json:"payment_ids"` // one payment per order the amount went to
+	TotalAmount   utils.Money `json:"total_amount" swaggertype:"string" example:"74.00"`
+	AmountPaid    utils.Money `json:"amount_paid" swaggertype:"string" example:"50.00"`
+	Remaining     utils.Money `json:"remaining" swaggertype:"string" example:"24.00"`
+	GroupComplete bool        `json:"group_complete"`
+}
+
+// swagger:model GuestOrderCodeRequest
+type GuestOrderCodeRequest struct {
+	// required: true
+	Phone string `json:"phone" example:"+1 555 010 2030"`
+}
+
+// swagger:model GuestOrderCodeSent
+type GuestOrderCodeSent struct {
+	ExpiresIn int `json:"expires_in" example:"600"` // seconds the code stays valid
+}
+
+// swagger:model ReorderRequest
+type ReorderRequest struct {
+	// required: true
+	Phone string `json:"phone" example:"+1 555 010 2030"`
+	// required: true
+	Code    string `json:"code" example:"482913"`
+	TableID uint   `json:"table_id" example:"4"` // defaults to the previous order's table, for dine_in orders
+}
+
+// swagger:model ReorderSkippedItem
+type ReorderSkippedItem struct {
+	MenuItemID uint   `json:"menu_item_id"`
+	Name       string `json:"name"`
+	Quantity   int    `json:"quantity"`
+	Reason     string `json:"reason" example:"out_of_stock"` // removed or out_of_stock
+	Available  int    `json:"available"`                     // current stock, for out_of_stock items
+}
+
+// swagger:model ReorderResult
+type ReorderResult struct {
+	Order   Order                `json:"order"`
+	Skipped []ReorderSkippedItem `json:"skipped"`
+}
+
+// swagger:model OrderTableTransfer
+type OrderTableTransfer struct {
+	// required: true
+	TableID uint `json:"table_id" example:"4"`
+}
+
 // swagger:model OrderResponse
 type OrderResponse struct {
 	Order
 	RestaurantName string `json:"restaurant_name"`
 	RestaurantID   uint   `json:"restaurant_id"`
-}
\ No newline at end of file
+}
+
+// swagger:model KitchenOrderItem
+type KitchenOrderItem struct {
+	ID                  uint   `json:"id"` // order item ID, for marking it ready
+	MenuItemID          uint   `json:"menu_item_id"`
+	Name                string `json:"name"`
+	Quantity            int    `json:"quantity"`
+	SpecialInstructions string `json:"special_instructions"`
+	Station             string `json:"station" example:"grill"` // the menu item's current station; empty for none
+	Status              string `json:"status" enums:"pending,ready"`
+}
+
+// swagger:model KitchenStation
+type KitchenStation struct {
+	Station string             `json:"station" example:"grill"` // empty for items without a station
+	Items   []KitchenOrderItem `json:"items"`
+}
+
+// swagger:model KitchenOrder
+type KitchenOrder struct {
+	ID             uint               `json:"id"`
+	OrderRef       string             `json:"order_ref" example:"A-017"`
+	TableID        *uint              `json:"table_id"`     // null when the order has no table
+	TableNumber    int                `json:"table_number"` // 0 when the order has no table
+	CustomerName   string             `json:"customer_name"`
+	OrderType      string             `json:"order_type" example:"takeaway"`
+	Status         string             `json:"status"`
+	CreatedAt      time.Time          `json:"created_at"`
+	ElapsedSeconds int64              `json:"elapsed_seconds"`
+	Items          []KitchenOrderItem `json:"items"`
+	Stations       []KitchenStation   `json:"stations"` // the items by station, stations by name and items without one last
+}
+
+// swagger:model KitchenGroup
+type KitchenGroup struct {
+	Status string         `json:"status"`
+	Orders []KitchenOrder `json:"orders"`
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/utils"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// recordAudit stores an audit entry for an action by the requesting user on one of the
+// restaurant's entities. It runs after the action has succeeded, so a failure is logged rather
+// than undoing the action.
+func recordAudit(c *fiber.Ctx, restaurant *models.Restaurant, action, entity string, entityID uint, details string) {
+	actorID, err := auditActorID(c)
+	if err != nil {
+		log.Printf("failed to record audit log %s %s %d for restaurant %d: unknown acting user: %v",
+			action, entity, entityID, restaurant.ID, err)
+		return
+	}
+	entry := models.AuditLog{
+		UserID:       actorID,
+		RestaurantID: restaurant.ID,
+		Action:       action,
+		Entity:       entity,
+		EntityID:     entityID,
+		Details:      details,
+	}
+	if err := database.DB.Create(&entry).Error; err != nil {
+		log.Printf("failed to record audit log %s %s %d for restaurant %d: %v",
+			action, entity, entityID, restaurant.ID, err)
+	}
+}
+
+// auditActorID is the ID of the requesting user: the user_id claim ProtectRoute stores, or the
+// user with the stored username where only that is set
+func auditActorID(c *fiber.Ctx) (uint, error) {
+	switch id := c.Locals("user_id").(type) {
+	case float64:
+		return uint(id), nil
+	case uint:
+		return id, nil
+	}
+
+	username, _ := c.Locals("username").(string)
+	var user models.User
+	if err := db(c).Select("id").Where("username = ?", username).First(&user).Error; err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
+
+// recordUserAudit stores an audit entry for an admin action on a user account. Accounts don't
+// belong to a restaurant, so the entry has restaurant ID 0 and never shows up in a restaurant's log.
+func recordUserAudit(actor *models.User, action string, userID uint, details string) {
+	entry := models.AuditLog{
+		UserID:   actor.ID,
+		Action:   action,
+		Entity:   constants.AuditEntityUser,
+		EntityID: userID,
+		Details:  details,
+	}
+	if err := database.DB.Create(&entry).Error; err != nil {
+		log.Printf("failed to record audit log %s user %d by user %d: %v", action, userID, actor.ID, err)
+	}
+}
+
+// auditLogFilter narrows the audit log to the entries matching every set field
+type auditLogFilter struct {
+	Action   string
+	Entity   string
+	EntityID uint
+	UserID   uint
+	From     *time.Time
+	To       *time.Time // exclusive
+}
+
+// parseAuditLogFilter reads the action, entity, entity_id, user_id, from and to query parameters
+func parseAuditLogFilter(c *fiber.Ctx) (auditLogFilter, error) {
+	filter := auditLogFilter{
+		Action: c.Query("action"),
+		Entity: c.Query("entity"),
+	}
+	if filter.Action != "" && !slices.Contains(constants.AuditActions, filter.Action) {
+		return filter, fmt.Errorf("action must be one of %s", strings.Join(constants.AuditActions, ", "))
+	}
+	if filter.Entity != "" && !slices.Contains(constants.AuditEntities, filter.Entity) {
+		return filter, fmt.Errorf("entity must be one of %s", strings.Join(constants.AuditEntities, ", "))
+	}
+
+	var err error
+	if filter.EntityID, err = parseIDQuery(c, "entity_id"); err != nil {
+		return filter, err
+	}
+	if filter.UserID, err = parseIDQuery(c, "user_id"); err != nil {
+		return filter, err
+	}
+
+	// Dates are whole days like the reports use; to is inclusive
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.ParseInLocation(reportDateLayout, raw, time.Local)
+		if err != nil {
+			return filter, errors.New("from must be a date like 2024-01-31")
+		}
+		filter.From = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.ParseInLocation(reportDateLayout, raw, time.Local)
+		if err != nil {
+			return filter, errors.New("to must be a date like 2024-01-31")
+		}
+		to = to.AddDate(0, 0, 1)
+		filter.To = &to
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, errors.New("from must not be after to")
+	}
+	return filter, nil
+}
+
+// parseIDQuery reads an optional ID from the named query parameter, returning 0 when it's absent
+func parseIDQuery(c *fiber.Ctx, name string) (uint, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return 0, nil
+	}
+	id, err := strconv.ParseUint(raw, 10, 32)
+	if err != nil || id == 0 {
+		return 0, fmt.Errorf("%s must be a positive integer", name)
+	}
+	return uint(id), nil
+}
+
+// apply adds the filter's conditions to query
+func (f auditLogFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Action != "" {
+		query = query.Where("action = ?", f.Action)
+	}
+	if f.Entity != "" {
+		query = query.Where("entity = ?", f.Entity)
+	}
+	if f.EntityID != 0 {
+		query = query.Where("entity_id = ?", f.EntityID)
+	}
+	if f.UserID != 0 {
+		query = query.Where("user_id = ?", f.UserID)
+	}
+	if f.From != nil {
+		query = query.Where("created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("created_at < ?", *f.To)
+	}
+	return query
+}
+
+// GetAuditLog godoc
+// @Summary Get the audit log
+// @Description Get a page of create, update and delete actions recorded for a restaurant, newest first, optionally filtered
+// @Tags Restaurant
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Restaurant ID"
+// @Param action query string false "Only entries with this action (create, update, delete)"
+// @Param entity query string false "Only entries for this kind of entity, e.g. order"
+// @Param entity_id query int false "Only entries for the entity with this ID"
+// @Param user_id query int false "Only entries by this user"
+// @Param from query string false "Start date (YYYY-MM-DD)"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} PageEnvelope[AuditLogEntry]
+// @Failure 400 {object} ErrorEnvelope "Invalid pagination parameters or filter"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving audit log"
+// @Router /api/restaurant/{id}/audit [get]
+func GetAuditLog(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+
+	page, err := parsePagination(c)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid pagination parameters: "+err.Error())
+	}
+	filter, err := parseAuditLogFilter(c)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid filter: "+err.Error())
+	}
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(c.Params("id")))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	query := filter.apply(db(c).Model(&models.AuditLog{}).Where("restaurant_id = ?", restaurant.ID))
+	if err := query.Count(&page.Total).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving audit log")
+	}
+
+	var logs []models.AuditLog
+	if err := query.Order("created_at DESC, id DESC").Limit(page.Limit).Offset(page.Offset).Find(&logs).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving audit log")
+	}
+
+	entries := make([]AuditLogEntry, 0, len(logs))
+	for _, entry := range logs {
+		entries = append(entries, AuditLogEntry{
+			ID:           entry.ID,
+			UserID:       entry.UserID,
+			RestaurantID: entry.RestaurantID,
+			Action:       entry.Action,
+			Entity:       entry.Entity,
+			EntityID:     entry.EntityID,
+			Details:      entry.Details,
+			CreatedAt:    entry.CreatedAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success":    true,
+		"data":       entries,
+		"pagination": page,
+		"error":      nil,
+	})
+}
@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetAuditLogFilters(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_auditfilter", Password: "x", Email: "auditfilter@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Audit Filter Restaurant"}
+	database.DB.Create(&restaurant)
+
+	day := func(d int) time.Time { return time.Date(2024, time.March, d, 12, 0, 0, 0, time.Local) }
+	entries := []models.AuditLog{
+		{UserID: user.ID, RestaurantID: restaurant.ID, Action: constants.AuditActionCreate, Entity: constants.AuditEntityOrder, EntityID: 7, CreatedAt: day(1)},
+		{UserID: user.ID, RestaurantID: restaurant.ID, Action: constants.AuditActionUpdate, Entity: constants.AuditEntityOrder, EntityID: 7, CreatedAt: day(2)},
+		{UserID: user.ID + 1, RestaurantID: restaurant.ID, Action: constants.AuditActionDelete, Entity: constants.AuditEntityOrder, EntityID: 8, CreatedAt: day(3)},
+		{UserID: user.ID, RestaurantID: restaurant.ID, Action: constants.AuditActionUpdate, Entity: constants.AuditEntityMenuItem, EntityID: 7, CreatedAt: day(4)},
+		{UserID: user.ID, RestaurantID: restaurant.ID + 1, Action: constants.AuditActionUpdate, Entity: constants.AuditEntityOrder, EntityID: 7, CreatedAt: day(2)},
+	}
+	if err := database.DB.Create(&entries).Error; err != nil {
+		t.Fatalf("creating audit entries: %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/restaurant/:id/audit", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetAuditLog(c)
+	})
+
+	tests := []struct {
+		query  string
+		status int
+		ids    []uint // expected entries, newest first
+	}{
+		{"", fiber.StatusOK, []uint{entries[3].ID, entries[2].ID, entries[1].ID, entries[0].ID}},
+		{"entity=order&entity_id=7", fiber.StatusOK, []uint{entries[1].ID, entries[0].ID}},
+		{"action=update", fiber.StatusOK, []uint{entries[3].ID, entries[1].ID}},
+		{fmt.Sprintf("user_id=%d", user.ID+1), fiber.StatusOK, []uint{entries[2].ID}},
+		{"from=2024-03-02&to=2024-03-03", fiber.StatusOK, []uint{entries[2].ID, entries[1].ID}},
+		{"entity=order&limit=1&offset=1", fiber.StatusOK, []uint{entries[1].ID}},
+		{"action=cancel", fiber.StatusBadRequest, nil},
+		{"entity=customer", fiber.StatusBadRequest, nil},
+		{"user_id=abc", fiber.StatusBadRequest, nil},
+		{"from=03/02/2024", fiber.StatusBadRequest, nil},
+		{"from=2024-03-04&to=2024-03-01", fiber.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/audit?%s", restaurant.ID, tt.query), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Fatalf("%q: expected status %d, got %d", tt.query, tt.status, resp.StatusCode)
+		}
+		if tt.status != fiber.StatusOK {
+			continue
+		}
+		var body struct {
+			Data []AuditLogEntry `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		var ids []uint
+		for _, entry := range body.Data {
+			ids = append(ids, entry.ID)
+		}
+		if fmt.Sprint(ids) != fmt.Sprint(tt.ids) {
+			t.Fatalf("%q: expected entries %v, got %v", tt.query, tt.ids, ids)
+		}
+	}
+}
+
+func TestAuditRecordsTheActingUser(t *testing.T) {
+	testutil.SetupDB(t)
+
+	owner := models.User{Username: "testuser_audit_owner", Password: "x", Email: "auditowner@example.com"}
+	actor := models.User{Username: "testuser_audit_actor", Password: "x", Email: "auditactor@example.com"}
+	database.DB.Create(&owner)
+	database.DB.Create(&actor)
+	restaurant := models.Restaurant{UserID: owner.ID, Name: "Audit Actor Restaurant"}
+	database.DB.Create(&restaurant)
+
+	app := fiber.New()
+	app.Post("/claim", func(c *fiber.Ctx) error {
+		c.Locals("username", actor.Username)
+		c.Locals("user_id", float64(actor.ID))
+		recordAudit(c, &restaurant, constants.AuditActionUpdate, constants.AuditEntityRestaurant, restaurant.ID, "Updated")
+		return nil
+	})
+	app.Post("/username", func(c *fiber.Ctx) error {
+		c.Locals("username", actor.Username)
+		recordAudit(c, &restaurant, constants.AuditActionUpdate, constants.AuditEntityRestaurant, restaurant.ID, "Updated")
+		return nil
+	})
+	for _, path := range []string{"/claim", "/username"} {
+		if _, err := app.Test(httptest.NewRequest("POST", path, nil), -1); err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+	}
+
+	var entries []models.AuditLog
+	database.DB.Where("restaurant_id = ?", restaurant.ID).Find(&entries)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 audit entries, got %d", len(entries))
+	}
+	for _, entry := range entries {
+		if entry.UserID != actor.ID {
+			t.Fatalf("expected the acting user %d, not the owner %d, got %d", actor.ID, owner.ID, entry.UserID)
+		}
+	}
+}
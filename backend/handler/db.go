@@ -0,0 +1,22 @@
+package handler
+
+import (
+	"order-system/database"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// db returns the database bound to the request's context. Handlers run every query through it
+// so the request timeout cancels work still in flight; a cancelled transaction rolls back as a
+// whole. Background jobs and audit writes, which have no request to answer, use database.DB.
+func db(c *fiber.Ctx) *gorm.DB {
+	return database.DB.WithContext(c.UserContext())
+}
+
+// readDB is db for read-only queries that may be served from the read replica. Use it only for
+// public or reporting reads where a moment of replication lag is harmless, never to read back
+// a write.
+func readDB(c *fiber.Ctx) *gorm.DB {
+	return database.ReadDB().WithContext(c.UserContext())
+}
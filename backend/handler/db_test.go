@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDBUsesRequestContext(t *testing.T) {
+	testutil.SetupDB(t)
+
+	var queryErr error
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		c.SetUserContext(ctx)
+
+		var users []models.User
+		queryErr = db(c).Find(&users).Error
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	if _, err := app.Test(httptest.NewRequest(http.MethodGet, "/", nil), -1); err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if !errors.Is(queryErr, context.Canceled) {
+		t.Fatalf("expected the query to be cancelled with the request, got %v", queryErr)
+	}
+}
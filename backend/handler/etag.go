@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// sendWithETag sends data in the success envelope with an ETag of the response body, or an empty
+// 304 when the client's If-None-Match already names that body. The ETag is a hash of the content
+// rather than of updated_at timestamps, which miss deletions and would need a query of their own.
+// Cache-Control: no-cache makes browsers revalidate on every load, so stock is never shown stale.
+func sendWithETag(c *fiber.Ctx, data interface{}) error {
+	body, err := c.App().Config().JSONEncoder(fiber.Map{
+		"success": true,
+		"data":    data,
+		"error":   nil,
+	})
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(body)
+	c.Set(fiber.HeaderETag, `"`+hex.EncodeToString(sum[:16])+`"`)
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(body)
+}
@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestPublicRestaurantHonorsIfNoneMatch(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_etag", Password: "x", Email: "etag@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "ETag Restaurant"}
+	database.DB.Create(&restaurant)
+
+	app := fiber.New()
+	app.Get("/restaurant/:id", GetPublicRestaurantByID)
+	get := func(etag string) (int, string) {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d", restaurant.ID), nil)
+		if etag != "" {
+			req.Header.Set(fiber.HeaderIfNoneMatch, etag)
+		}
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		return resp.StatusCode, resp.Header.Get(fiber.HeaderETag)
+	}
+
+	status, etag := get("")
+	if status != fiber.StatusOK || etag == "" {
+		t.Fatalf("expected 200 with an ETag, got %d and %q", status, etag)
+	}
+	if status, _ := get(etag); status != fiber.StatusNotModified {
+		t.Fatalf("expected 304 for a matching If-None-Match, got %d", status)
+	}
+
+	database.DB.Model(&restaurant).Update("name", "Renamed Restaurant")
+	status, changed := get(etag)
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200 after the restaurant changed, got %d", status)
+	}
+	if changed == etag {
+		t.Fatal("expected a new ETag after the restaurant changed")
+	}
+}
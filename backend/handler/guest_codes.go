@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+const (
+	guestCodeTTL            = 10 * time.Minute // how long a code can be used
+	guestCodeResendInterval = time.Minute      // minimum gap between codes for one phone, against SMS flooding
+	maxGuestCodeAttempts    = 5                // wrong guesses before a code stops working
+)
+
+// guestCodeStore holds the phone verification codes guests use to see their order history,
+// one per restaurant and phone number. Codes live in memory only, so a restart discards them
+// and, behind several instances, a code works only on the instance that sent it.
+type guestCodeStore struct {
+	mu    sync.Mutex
+	codes map[guestCodeKey]*guestCode
+}
+
+type guestCodeKey struct {
+	restaurantID uint
+	phone        string // digits only, see utils.PhoneDigits
+}
+
+type guestCode struct {
+	hash     [sha256.Size]byte // codes are only compared, so only their hash is kept
+	sentAt   time.Time
+	expires  time.Time
+	attempts int
+}
+
+var globalGuestCodes = &guestCodeStore{codes: make(map[guestCodeKey]*guestCode)}
+
+// issue creates a new six-digit code for key, replacing any earlier one. It refuses while the
+// previous code is younger than guestCodeResendInterval.
+func (s *guestCodeStore) issue(key guestCodeKey) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if existing, ok := s.codes[key]; ok && now.Sub(existing.sentAt) < guestCodeResendInterval {
+		return "", false, nil
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", false, err
+	}
+	code := fmt.Sprintf("%06d", n.Int64())
+
+	// Drop expired codes here rather than from a background goroutine
+	for k, stored := range s.codes {
+		if now.After(stored.expires) {
+			delete(s.codes, k)
+		}
+	}
+	s.codes[key] = &guestCode{hash: sha256.Sum256([]byte(code)), sentAt: now, expires: now.Add(guestCodeTTL)}
+	return code, true, nil
+}
+
+// verify reports whether code is the unexpired code for key. A code stays usable until it
+// expires, so the guest can reload their history; maxGuestCodeAttempts wrong codes lock it. The
+// locked code is kept until it expires so its sentAt still holds back the next one.
+func (s *guestCodeStore) verify(key guestCodeKey, code string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.codes[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(stored.expires) {
+		delete(s.codes, key)
+		return false
+	}
+	if stored.attempts >= maxGuestCodeAttempts {
+		return false
+	}
+	hash := sha256.Sum256([]byte(code))
+	if subtle.ConstantTimeCompare(hash[:], stored.hash[:]) == 1 {
+		return true
+	}
+	stored.attempts++
+	return false
+}
+
+// forget discards the code for key, e.g. when it couldn't be sent
+func (s *guestCodeStore) forget(key guestCodeKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.codes, key)
+}
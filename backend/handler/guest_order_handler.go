@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// guestOrderHistoryLimit caps how many of a guest's most recent orders are returned
+const guestOrderHistoryLimit = 50
+
+// phoneDigitsSQL strips the separators utils.ValidatePhone allows from orders.customer_phone,
+// leaving what utils.PhoneDigits returns for the same number
+const phoneDigitsSQL = "REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(REPLACE(orders.customer_phone, ' ', ''), '(', ''), ')', ''), '.', ''), '-', ''), '+', '')"
+
+// smsSender sends the guest order history codes
+var smsSender utils.SMSSender = utils.LogSMSSender{}
+
+// SetSMSSender replaces the SMS sender, which by default only logs messages
+func SetSMSSender(sender utils.SMSSender) {
+	smsSender = sender
+}
+
+// RequestGuestOrderCode godoc
+// @Summary Send a code to see past orders
+// @Description Text a six-digit code to a phone number, which GET /my-orders takes to list the orders placed with that number. The code is valid for 10 minutes; a new one can be requested once a minute.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param phone body GuestOrderCodeRequest true "Phone number the orders were placed with"
+// @Success 200 {object} Envelope[GuestOrderCodeSent]
+// @Failure 400 {object} ErrorEnvelope "Invalid phone number"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 429 {object} ErrorEnvelope "A code was sent to the number less than a minute ago"
+// @Failure 500 {object} ErrorEnvelope "Error sending the code"
+// @Router /api/restaurants/{restaurant_id}/my-orders/code [post]
+func RequestGuestOrderCode(c *fiber.Ctx) error {
+	restaurantID := c.Params("restaurant_id")
+
+	var restaurant models.Restaurant
+	if err := readDB(c).First(&restaurant, restaurantID).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request GuestOrderCodeRequest
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	phone := strings.TrimSpace(request.Phone)
+	if !utils.ValidatePhone(phone) {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "phone must be a phone number of 7 to 15 digits")
+	}
+
+	// A code is sent whether or not the number has orders, so the response reveals nothing
+	key := guestCodeKey{restaurantID: restaurant.ID, phone: utils.PhoneDigits(phone)}
+	code, issued, err := globalGuestCodes.issue(key)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error sending the code")
+	}
+	if !issued {
+		return utils.SendError(c, fiber.StatusTooManyRequests, constants.ErrCodeRateLimited, "A code was just sent to this number. Please wait a minute before asking for another.")
+	}
+
+	message := fmt.Sprintf("%s: your code to see your orders is %s. It expires in %d minutes.", restaurant.Name, code, int(guestCodeTTL.Minutes()))
+	if err := smsSender.SendSMS(phone, message); err != nil {
+		globalGuestCodes.forget(key)
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error sending the code")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    GuestOrderCodeSent{ExpiresIn: int(guestCodeTTL.Seconds())},
+		"error":   nil,
+	})
+}
+
+// GetGuestOrders godoc
+// @Summary Get past orders by phone number
+// @Description Get the most recent orders, up to 50, placed at the restaurant with a phone number, newest first. The number must be confirmed with the code from POST /my-orders/code; separators in the number don't matter.
+// @Tags Order
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param phone query string true "Phone number the orders were placed with"
+// @Param code query string true "Code texted to the phone number"
+// @Success 200 {object} Envelope[[]Order]
+// @Failure 400 {object} ErrorEnvelope "Invalid phone number or missing code"
+// @Failure 401 {object} ErrorEnvelope "Wrong or expired code"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving orders"
+// @Router /api/restaurants/{restaurant_id}/my-orders [get]
+func GetGuestOrders(c *fiber.Ctx) error {
+	restaurantID := c.Params("restaurant_id")
+
+	var restaurant models.Restaurant
+	if err := readDB(c).First(&restaurant, restaurantID).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	phone := strings.TrimSpace(c.Query("phone"))
+	code := strings.TrimSpace(c.Query("code"))
+	if !utils.ValidatePhone(phone) || code == "" {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "phone and code are required")
+	}
+
+	digits := utils.PhoneDigits(phone)
+	if !globalGuestCodes.verify(guestCodeKey{restaurantID: restaurant.ID, phone: digits}, code) {
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeInvalidVerificationCode, "Invalid or expired verification code")
+	}
+
+	var orders []models.Order
+	if err := readDB(c).
+		Where("orders.restaurant_id = ? AND orders.customer_phone <> '' AND "+phoneDigitsSQL+" = ?", restaurant.ID, digits).
+		Order("orders.created_at DESC").
+		Limit(guestOrderHistoryLimit).
+		Preload("Table").
+		Preload("OrderItems").
+		Find(&orders).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving orders")
+	}
+
+	response := make([]Order, 0, len(orders))
+	for _, order := range orders {
+		response = append(response, toHandlerOrder(order))
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+		"error":   nil,
+	})
+}
+
+// Reasons a previous order's item is left out of a reorder
+const (
+	reorderSkippedRemoved    = "removed"
+	reorderSkippedOutOfStock = "out_of_stock"
+)
+
+// ReorderGuestOrder godoc
+// @Summary Order the same as last time
+// @Description Place a new pending order with the items of a previous order placed with the guest's phone number, confirmed with the code from POST /my-orders/code. Items are charged at their current price; items no longer on the menu or without enough stock are left out and listed in skipped. Dine-in orders go to the previous table unless table_id is given.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param order_id path string true "ID of the previous order"
+// @Param reorder body ReorderRequest true "Phone number, code and optional table"
+// @Success 201 {object} Envelope[ReorderResult]
+// @Failure 400 {object} ErrorEnvelope "Invalid input, unknown fields listed in data.unknown_fields, insufficient stock, or none of the items can be ordered, with data.skipped listing them"
+// @Failure 401 {object} ErrorEnvelope "Wrong or expired code"
+// @Failure 404 {object} ErrorEnvelope "Restaurant, order or table not found"
+// @Failure 429 {object} ErrorEnvelope "Kitchen at capacity, with data.estimated_wait_minutes"
+// @Failure 500 {object} ErrorEnvelope "Error creating order"
+// @Router /api/restaurants/{restaurant_id}/reorder/{order_id} [post]
+func ReorderGuestOrder(c *fiber.Ctx) error {
+	restaurantID := c.Params("restaurant_id")
+	orderID := c.Params("order_id")
+
+	var restaurant models.Restaurant
+	if err := db(c).First(&restaurant, restaurantID).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request ReorderRequest
+	if err := utils.BodyParserStrict(c, &request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	phone := strings.TrimSpace(request.Phone)
+	code := strings.TrimSpace(request.Code)
+	if !utils.ValidatePhone(phone) || code == "" {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "phone and code are required")
+	}
+
+	digits := utils.PhoneDigits(phone)
+	if !globalGuestCodes.verify(guestCodeKey{restaurantID: restaurant.ID, phone: digits}, code) {
+		return utils.SendError(c, fiber.StatusUnauthorized, constants.ErrCodeInvalidVerificationCode, "Invalid or expired verification code")
+	}
+
+	// Orders placed with another number look the same as missing ones
+	var previous models.Order
+	if err := db(c).
+		Where("orders.id = ? AND orders.restaurant_id = ? AND orders.customer_phone <> '' AND "+phoneDigitsSQL+" = ?", orderID, restaurant.ID, digits).
+		Preload("OrderItems").
+		First(&previous).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeOrderNotFound, "Order not found")
+	}
+
+	requestedTableID := request.TableID
+	if requestedTableID == 0 && previous.TableID != nil {
+		requestedTableID = *previous.TableID
+	}
+	tableID, apiErr := orderTableID(c, restaurant.ID, previous.OrderType, requestedTableID)
+	if apiErr != nil {
+		return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
+	}
+
+	items, skipped, err := reorderItems(c, restaurant.ID, previous.OrderItems)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error creating order")
+	}
+	if len(items) == 0 {
+		return utils.SendErrorWithData(c, fiber.StatusBadRequest, constants.ErrCodeNothingToReorder, "None of the items can be ordered again", fiber.Map{"skipped": skipped})
+	}
+
+	createdOrder, shortages, err := placePublicOrder(c, &restaurant, models.Order{
+		TableID:         tableID,
+		CustomerName:    previous.CustomerName,
+		CustomerPhone:   previous.CustomerPhone,
+		OrderType:       previous.OrderType,
+		DeliveryAddress: previous.DeliveryAddress,
+	}, items)
+	if len(shortages) > 0 {
+		// Stock ran out between checking the items and placing the order
+		return utils.SendErrorWithData(c, fiber.StatusBadRequest, constants.ErrCodeInsufficientStock, "Insufficient stock", fiber.Map{"shortages": shortages})
+	}
+	if err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendErrorWithData(c, apiErr.status, apiErr.code, apiErr.message, apiErr.data)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error creating order")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    ReorderResult{Order: toHandlerOrder(createdOrder), Skipped: skipped},
+		"error":   nil,
+	})
+}
+
+// reorderItems splits a previous order's lines into those that can be ordered again and those
+// that can't, because the menu item was removed or its stock no longer covers every line for it
+func reorderItems(c *fiber.Ctx, restaurantID uint, lines []models.OrderItem) ([]orderItemRequest, []ReorderSkippedItem, error) {
+	requested := make(map[uint]int, len(lines))
+	menuItemIDs := make([]uint, 0, len(lines))
+	for _, line := range lines {
+		if _, ok := requested[line.MenuItemID]; !ok {
+			menuItemIDs = append(menuItemIDs, line.MenuItemID)
+		}
+		requested[line.MenuItemID] += line.Quantity
+	}
+
+	var menuItems []models.MenuItem
+	if err := db(c).Where("id IN ? AND restaurant_id = ?", menuItemIDs, restaurantID).Find(&menuItems).Error; err != nil {
+		return nil, nil, err
+	}
+	current := make(map[uint]models.MenuItem, len(menuItems))
+	for _, menuItem := range menuItems {
+		current[menuItem.ID] = menuItem
+	}
+
+	items := make([]orderItemRequest, 0, len(lines))
+	skipped := make([]ReorderSkippedItem, 0)
+	for _, line := range lines {
+		menuItem, ok := current[line.MenuItemID]
+		switch {
+		case !ok:
+			skipped = append(skipped, ReorderSkippedItem{
+				MenuItemID: line.MenuItemID,
+				Name:       line.ItemName,
+				Quantity:   line.Quantity,
+				Reason:     reorderSkippedRemoved,
+			})
+		case menuItem.Quantity < requested[line.MenuItemID]:
+			skipped = append(skipped, ReorderSkippedItem{
+				MenuItemID: line.MenuItemID,
+				Name:       menuItem.Name,
+				Quantity:   line.Quantity,
+				Reason:     reorderSkippedOutOfStock,
+				Available:  menuItem.Quantity,
+			})
+		default:
+			items = append(items, orderItemRequest{
+				MenuItemID:          line.MenuItemID,
+				Quantity:            &line.Quantity,
+				SpecialInstructions: line.SpecialInstructions,
+			})
+		}
+	}
+	return items, skipped, nil
+}
@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http/httptest"
+	"net/url"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// recordingSMSSender keeps the messages it is asked to send
+type recordingSMSSender struct {
+	messages []string
+}
+
+func (s *recordingSMSSender) SendSMS(phone string, message string) error {
+	s.messages = append(s.messages, message)
+	return nil
+}
+
+func TestGuestOrdersNeedTheTextedCode(t *testing.T) {
+	testutil.SetupDB(t)
+	previousSender, previousCodes := smsSender, globalGuestCodes
+	t.Cleanup(func() { smsSender, globalGuestCodes = previousSender, previousCodes })
+	sender := &recordingSMSSender{}
+	SetSMSSender(sender)
+	globalGuestCodes = &guestCodeStore{codes: make(map[guestCodeKey]*guestCode)}
+
+	user := models.User{Username: "testuser_guest", Password: "x", Email: "guest@example.com"}
+	database.DB.Create(&user)
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Guest Restaurant"}
+	otherRestaurant := models.Restaurant{UserID: user.ID, Name: "Other Guest Restaurant"}
+	database.DB.Create(&restaurant)
+	database.DB.Create(&otherRestaurant)
+	for _, order := range []models.Order{
+		{RestaurantID: restaurant.ID, CustomerPhone: "+1 (555) 010-2030", OrderType: "takeaway", TotalAmount: 900},
+		{RestaurantID: restaurant.ID, CustomerPhone: "+1 555 999 0000", OrderType: "takeaway", TotalAmount: 500},
+		{RestaurantID: otherRestaurant.ID, CustomerPhone: "+1 555 010 2030", OrderType: "takeaway", TotalAmount: 700},
+	} {
+		if err := database.DB.Create(&order).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+	}
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/my-orders/code", RequestGuestOrderCode)
+	app.Get("/restaurants/:restaurant_id/my-orders", GetGuestOrders)
+	requestCode := func(phone string) int {
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurants/%d/my-orders/code", restaurant.ID), strings.NewReader(fmt.Sprintf(`{"phone": %q}`, phone)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+	listOrders := func(phone, code string) (int, []Order) {
+		query := url.Values{"phone": {phone}, "code": {code}}
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurants/%d/my-orders?%s", restaurant.ID, query.Encode()), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data []Order `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Data
+	}
+
+	if status := requestCode("call me"); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an invalid phone, got %d", status)
+	}
+	if status := requestCode("+1 555 010 2030"); status != fiber.StatusOK {
+		t.Fatalf("expected 200 sending a code, got %d", status)
+	}
+	if status := requestCode("+1 555 010 2030"); status != fiber.StatusTooManyRequests {
+		t.Fatalf("expected 429 asking again straight away, got %d", status)
+	}
+	if len(sender.messages) != 1 {
+		t.Fatalf("expected one text message, got %d", len(sender.messages))
+	}
+	code := regexp.MustCompile(`\d{6}`).FindString(sender.messages[0])
+
+	if status, _ := listOrders("+1 555 010 2030", "wrong"); status != fiber.StatusUnauthorized {
+		t.Fatalf("expected 401 for a wrong code, got %d", status)
+	}
+	if status, _ := listOrders("+1 555 999 0000", code); status != fiber.StatusUnauthorized {
+		t.Fatalf("expected 401 using the code for another number, got %d", status)
+	}
+
+	// The number matches whatever separators it was ordered with, at this restaurant only
+	status, orders := listOrders("15550102030", code)
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200 with the texted code, got %d", status)
+	}
+	if len(orders) != 1 || orders[0].TotalAmount != 900 {
+		t.Fatalf("expected only the guest's order at this restaurant, got %+v", orders)
+	}
+}
+
+func TestGuestCodeIsDiscardedAfterTooManyWrongGuesses(t *testing.T) {
+	store := &guestCodeStore{codes: make(map[guestCodeKey]*guestCode)}
+	key := guestCodeKey{restaurantID: 1, phone: "15550102030"}
+	code, issued, err := store.issue(key)
+	if err != nil || !issued {
+		t.Fatalf("expected a code, got issued=%v err=%v", issued, err)
+	}
+
+	for i := 0; i < maxGuestCodeAttempts; i++ {
+		if store.verify(key, "wrong") {
+			t.Fatal("expected a wrong code to be rejected")
+		}
+	}
+	if store.verify(key, code) {
+		t.Fatal("expected the code to be discarded after too many wrong guesses")
+	}
+	if _, issued, _ := store.issue(key); issued {
+		t.Fatal("expected no new code within the resend interval after a lockout")
+	}
+}
+
+func TestReorderSkipsItemsThatCantBeOrderedAgain(t *testing.T) {
+	testutil.SetupDB(t)
+	previousCodes := globalGuestCodes
+	t.Cleanup(func() { globalGuestCodes = previousCodes })
+	globalGuestCodes = &guestCodeStore{codes: make(map[guestCodeKey]*guestCode)}
+
+	user := models.User{Username: "testuser_reorder", Password: "x", Email: "reorder@example.com"}
+	database.DB.Create(&user)
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Reorder Restaurant"}
+	database.DB.Create(&restaurant)
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 3}
+	database.DB.Create(&table)
+	soup := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 650, Quantity: 10}
+	bread := models.MenuItem{RestaurantID: restaurant.ID, Name: "Bread", Price: 200, Quantity: 0}
+	pie := models.MenuItem{RestaurantID: restaurant.ID, Name: "Pie", Price: 400, Quantity: 5}
+	for _, item := range []*models.MenuItem{&soup, &bread, &pie} {
+		database.DB.Create(item)
+	}
+	database.DB.Delete(&pie)
+
+	previous := models.Order{
+		RestaurantID:  restaurant.ID,
+		TableID:       &table.ID,
+		CustomerName:  "Sam",
+		CustomerPhone: "+1 555 010 2030",
+		OrderType:     "dine_in",
+		Status:        "paid",
+		TotalAmount:   1800,
+		OrderItems: []models.OrderItem{
+			{MenuItemID: soup.ID, ItemName: "Soup", UnitPrice: 600, Quantity: 2, SpecialInstructions: "no salt"},
+			{MenuItemID: bread.ID, ItemName: "Bread", UnitPrice: 200, Quantity: 1},
+			{MenuItemID: pie.ID, ItemName: "Pie", UnitPrice: 400, Quantity: 1},
+		},
+	}
+	if err := database.DB.Create(&previous).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+	breadOnly := models.Order{
+		RestaurantID:  restaurant.ID,
+		CustomerPhone: "+1 555 010 2030",
+		OrderType:     "takeaway",
+		Status:        "paid",
+		OrderItems:    []models.OrderItem{{MenuItemID: bread.ID, ItemName: "Bread", UnitPrice: 200, Quantity: 1}},
+	}
+	if err := database.DB.Create(&breadOnly).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+
+	code, _, err := globalGuestCodes.issue(guestCodeKey{restaurantID: restaurant.ID, phone: "15550102030"})
+	if err != nil {
+		t.Fatalf("issuing code: %v", err)
+	}
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/reorder/:order_id", ReorderGuestOrder)
+	reorder := func(orderID uint, phone string) (int, []byte) {
+		body := fmt.Sprintf(`{"phone": %q, "code": %q}`, phone, code)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurants/%d/reorder/%d", restaurant.ID, orderID), strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, raw
+	}
+
+	if status, _ := reorder(previous.ID, "+1 555 999 0000"); status != fiber.StatusUnauthorized {
+		t.Fatalf("expected 401 with another number's code, got %d", status)
+	}
+
+	status, raw := reorder(previous.ID, "15550102030")
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", status, raw)
+	}
+	var created struct {
+		Data ReorderResult `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &created); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	order := created.Data.Order
+	if order.TableID == nil || *order.TableID != table.ID || order.CustomerName != "Sam" {
+		t.Fatalf("expected the previous table and customer, got %+v", order)
+	}
+	if len(order.OrderItems) != 1 || order.OrderItems[0].Quantity != 2 || order.OrderItems[0].SpecialInstructions != "no salt" {
+		t.Fatalf("expected only the soup, as before, got %+v", order.OrderItems)
+	}
+	if order.TotalAmount != 1300 {
+		t.Fatalf("expected the soup at today's price, 1300, got %d", order.TotalAmount)
+	}
+	skipped := created.Data.Skipped
+	if len(skipped) != 2 || skipped[0].Reason != reorderSkippedOutOfStock || skipped[1].Reason != reorderSkippedRemoved {
+		t.Fatalf("expected bread out of stock and pie removed, got %+v", skipped)
+	}
+
+	var stock models.MenuItem
+	database.DB.First(&stock, soup.ID)
+	if stock.Quantity != 8 {
+		t.Fatalf("expected the reorder to take stock, got %d left", stock.Quantity)
+	}
+
+	if status, raw := reorder(breadOnly.ID, "15550102030"); status != fiber.StatusBadRequest || !strings.Contains(string(raw), "NOTHING_TO_REORDER") {
+		t.Fatalf("expected 400 NOTHING_TO_REORDER, got %d: %s", status, raw)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"order-system/database"
 	"order-system/models"
+	"order-system/testutil"
 	"os"
 	"testing"
 
@@ -20,6 +21,10 @@ func init() {
 	os.Chdir("..")
 }
 
+func TestMain(m *testing.M) {
+	testutil.Main(m)
+}
+
 func setupTestApp() *fiber.App {
 	app := fiber.New()
 
@@ -30,6 +35,7 @@ func setupTestApp() *fiber.App {
 	user.Post("/login", Login)
 	user.Get("/profile", ProtectRoute, Profile)
 	user.Delete("/", ProtectRoute, DeleteUser)
+	user.Patch("/:id/role", ProtectRoute, UpdateUserRole)
 
 	// Restaurant routes
 	restaurant := api.Group("/restaurant", ProtectRoute)
@@ -43,18 +49,10 @@ func setupTestApp() *fiber.App {
 }
 
 func TestFullFlow(t *testing.T) {
-	// Initialize test database
-	database.ConnectDB()
+	testutil.SetupDB(t)
 
 	app := setupTestApp()
 
-	// Cleanup any existing test data first (including soft-deleted)
-	var existingUser models.User
-	if err := database.DB.Unscoped().Where("username = ?", "testuser_integration").First(&existingUser).Error; err == nil {
-		database.DB.Unscoped().Where("user_id = ?", existingUser.ID).Delete(&models.Restaurant{})
-		database.DB.Unscoped().Delete(&existingUser)
-	}
-
 	// Dummy data
 	testUser := map[string]string{
 		"username": "testuser_integration",
@@ -194,7 +192,9 @@ func TestFullFlow(t *testing.T) {
 
 	// 8. Cleanup - Delete User
 	t.Run("DeleteUser", func(t *testing.T) {
-		req := httptest.NewRequest("DELETE", "/api/user/", nil)
+		body, _ := json.Marshal(map[string]string{"confirm_username": testUser["username"]})
+		req := httptest.NewRequest("DELETE", "/api/user/", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", "Bearer "+accessToken)
 
 		resp, err := app.Test(req)
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxAdjustmentReasonLength matches the size of the inventory_adjustments.reason column
+const maxAdjustmentReasonLength = 255
+
+// applyStockDelta returns the quantity after adding delta, floored at zero, and the change actually applied
+func applyStockDelta(quantity, delta int) (newQuantity, applied int) {
+	newQuantity = quantity + delta
+	if newQuantity < 0 {
+		newQuantity = 0
+	}
+	return newQuantity, newQuantity - quantity
+}
+
+// GetOutOfStockMenuItems godoc
+// @Summary Get out-of-stock menu items
+// @Description Get a restaurant's menu items with no stock left, by name unless sorted otherwise, to see what needs restocking
+// @Tags Menu
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param sort query string false "Order by category, name or updated_at, optionally with :asc or :desc, e.g. category"
+// @Success 200 {object} Envelope[[]MenuItem]
+// @Failure 400 {object} ErrorEnvelope "Invalid sort"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving menu items"
+// @Router /api/restaurant/{restaurant_id}/menu/out-of-stock [get]
+func GetOutOfStockMenuItems(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	sortBy, err := parseSort(c, outOfStockSortColumns)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid sort: "+err.Error())
+	}
+	if sortBy.Column == "" {
+		sortBy = listSort{Column: "name"}
+	}
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	menuItems := []models.MenuItem{}
+	query := sortBy.apply(db(c).Where("restaurant_id = ? AND quantity <= 0", restaurant.ID))
+	if err := query.Find(&menuItems).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving menu items")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    menuItems,
+		"error":   nil,
+	})
+}
+
+// AdjustMenuItemStock godoc
+// @Summary Adjust a menu item's stock
+// @Description Add or remove stock for reasons other than orders (restock, spoilage, manual count). The quantity never drops below zero, and every adjustment is recorded with its reason.
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Item ID"
+// @Param adjustment body StockAdjustmentRequest true "Signed quantity change and reason"
+// @Success 200 {object} Envelope[StockAdjustmentResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or menu item not found"
+// @Failure 500 {object} ErrorEnvelope "Error adjusting stock"
+// @Router /api/restaurant/{restaurant_id}/menu/{id}/adjust-stock [post]
+func AdjustMenuItemStock(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+	itemID := c.Params("id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request StockAdjustmentRequest
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	request.Reason = strings.TrimSpace(request.Reason)
+	if request.Delta == 0 {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "delta must not be zero")
+	}
+	if request.Reason == "" || len(request.Reason) > maxAdjustmentReasonLength {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, fmt.Sprintf("reason is required and must be at most %d characters", maxAdjustmentReasonLength))
+	}
+
+	var menuItem models.MenuItem
+	var adjustment models.InventoryAdjustment
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		// Lock the item so orders and other adjustments can't interleave with this one
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND restaurant_id = ?", itemID, restaurant.ID).
+			First(&menuItem).Error; err != nil {
+			return newAPIError(fiber.StatusNotFound, constants.ErrCodeMenuItemNotFound, "Menu item not found")
+		}
+
+		newQuantity, applied := applyStockDelta(menuItem.Quantity, request.Delta)
+		if err := tx.Model(&menuItem).Updates(map[string]interface{}{
+			"quantity": newQuantity,
+			"version":  gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+
+		adjustment = models.InventoryAdjustment{
+			RestaurantID:  restaurant.ID,
+			MenuItemID:    menuItem.ID,
+			UserID:        restaurant.UserID,
+			Delta:         request.Delta,
+			AppliedDelta:  applied,
+			QuantityAfter: newQuantity,
+			Reason:        request.Reason,
+		}
+		if err := tx.Create(&adjustment).Error; err != nil {
+			return err
+		}
+
+		menuItem.Quantity = newQuantity
+		return recordStockMovement(tx, &menuItem, constants.StockMovementAdjustment, applied, nil, &adjustment.ID, request.Reason)
+	}); err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error adjusting stock")
+	}
+
+	globalMenuCache.invalidate(restaurant.ID)
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntityMenuItem, menuItem.ID,
+		fmt.Sprintf("Adjusted stock of %q by %+d (%s)", menuItem.Name, adjustment.AppliedDelta, adjustment.Reason))
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": StockAdjustmentResponse{
+			AdjustmentID: adjustment.ID,
+			MenuItemID:   menuItem.ID,
+			Delta:        adjustment.Delta,
+			AppliedDelta: adjustment.AppliedDelta,
+			Quantity:     adjustment.QuantityAfter,
+			Reason:       adjustment.Reason,
+		},
+		"error": nil,
+	})
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestApplyStockDelta(t *testing.T) {
+	tests := []struct {
+		name        string
+		quantity    int
+		delta       int
+		wantQty     int
+		wantApplied int
+	}{
+		{"restock", 4, 10, 14, 10},
+		{"spoilage within stock", 4, -3, 1, -3},
+		{"spoilage floors at zero", 4, -10, 0, -4},
+		{"already empty", 0, -2, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qty, applied := applyStockDelta(tt.quantity, tt.delta)
+			if qty != tt.wantQty || applied != tt.wantApplied {
+				t.Fatalf("applyStockDelta(%d, %d) = %d, %d; want %d, %d",
+					tt.quantity, tt.delta, qty, applied, tt.wantQty, tt.wantApplied)
+			}
+		})
+	}
+}
+
+func TestGetOutOfStockMenuItems(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_out_of_stock", Password: "x", Email: "outofstock@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Out of Stock Restaurant"}
+	database.DB.Create(&restaurant)
+	database.DB.Create(&[]models.MenuItem{
+		{RestaurantID: restaurant.ID, Name: "Tiramisu", Category: "Desserts", Price: 700, Quantity: 0},
+		{RestaurantID: restaurant.ID, Name: "Bruschetta", Category: "Starters", Price: 600, Quantity: 0},
+		{RestaurantID: restaurant.ID, Name: "Lasagne", Category: "Mains", Price: 1500, Quantity: 0},
+		{RestaurantID: restaurant.ID, Name: "Gelato", Category: "Desserts", Price: 500, Quantity: 3},
+	})
+
+	app := fiber.New()
+	app.Get("/restaurant/:restaurant_id/menu/out-of-stock", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetOutOfStockMenuItems(c)
+	})
+	outOfStock := func(query string) (int, []string) {
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/menu/out-of-stock%s", restaurant.ID, query), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data []models.MenuItem `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		names := []string{}
+		for _, item := range body.Data {
+			names = append(names, item.Name)
+		}
+		return resp.StatusCode, names
+	}
+
+	if _, names := outOfStock(""); fmt.Sprint(names) != "[Bruschetta Lasagne Tiramisu]" {
+		t.Fatalf("expected the sold-out items by name, got %v", names)
+	}
+	if _, names := outOfStock("?sort=category"); fmt.Sprint(names) != "[Tiramisu Lasagne Bruschetta]" {
+		t.Fatalf("expected the sold-out items by category, got %v", names)
+	}
+	if status, _ := outOfStock("?sort=price"); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an unsupported sort, got %d", status)
+	}
+}
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// maxStationLength matches the size of the menu_items.station column
+const maxStationLength = 50
+
+// normalizeStation trims and lowercases a kitchen station name so "Bar" and "bar " route alike
+func normalizeStation(station string) (string, error) {
+	station = strings.ToLower(strings.TrimSpace(station))
+	if len(station) > maxStationLength {
+		return "", fmt.Errorf("station must be at most %d characters", maxStationLength)
+	}
+	return station, nil
+}
+
+// GetKitchenOrders godoc
+// @Summary Get kitchen display orders
+// @Description Get active orders grouped by status and sorted oldest-first, with items inlined for a kitchen display
+// @Description and grouped by the station they are prepared at. With station, only that station's items are shown
+// @Description and orders without any are left out.
+// @Tags Order
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param station query string false "Only items prepared at this station, e.g. bar"
+// @Success 200 {object} Envelope[[]KitchenGroup]
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving orders"
+// @Router /api/restaurant/{restaurant_id}/kitchen [get]
+func GetKitchenOrders(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	stationFilter, err := normalizeStation(c.Query("station"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+	}
+
+	var orders []models.Order
+	if err := restaurantOrders(db(c), restaurant.ID).
+		Where("orders.status IN ?", constants.ActiveOrderStatuses).
+		Order("orders.created_at ASC").
+		Preload("Table").
+		Preload("OrderItems").
+		Find(&orders).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving orders")
+	}
+
+	// Items route by their menu item's current station, so moving an item to another station
+	// also moves it on orders already in the kitchen
+	stations, err := menuItemStations(c, orders)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving orders")
+	}
+
+	// One group per active status in workflow order; orders are already oldest-first
+	groups := make([]KitchenGroup, len(constants.ActiveOrderStatuses))
+	groupIndex := make(map[string]int, len(constants.ActiveOrderStatuses))
+	for i, status := range constants.ActiveOrderStatuses {
+		groups[i] = KitchenGroup{Status: status, Orders: []KitchenOrder{}}
+		groupIndex[status] = i
+	}
+
+	now := time.Now()
+	for _, order := range orders {
+		items := make([]KitchenOrderItem, 0, len(order.OrderItems))
+		for _, item := range order.OrderItems {
+			station := stations[item.MenuItemID]
+			if stationFilter != "" && station != stationFilter {
+				continue
+			}
+			items = append(items, KitchenOrderItem{
+				ID:                  item.ID,
+				MenuItemID:          item.MenuItemID,
+				Name:                item.ItemName,
+				Quantity:            item.Quantity,
+				SpecialInstructions: item.SpecialInstructions,
+				Station:             station,
+				Status:              item.Status,
+			})
+		}
+		if stationFilter != "" && len(items) == 0 {
+			continue
+		}
+
+		var tableNumber int
+		if order.Table != nil {
+			tableNumber = order.Table.TableNumber
+		}
+
+		idx := groupIndex[order.Status]
+		groups[idx].Orders = append(groups[idx].Orders, KitchenOrder{
+			ID:             order.ID,
+			OrderRef:       order.OrderRef,
+			TableID:        order.TableID,
+			TableNumber:    tableNumber,
+			CustomerName:   order.CustomerName,
+			OrderType:      order.OrderType,
+			Status:         order.Status,
+			CreatedAt:      order.CreatedAt,
+			ElapsedSeconds: int64(now.Sub(order.CreatedAt).Seconds()),
+			Items:          items,
+			Stations:       groupItemsByStation(items),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    groups,
+		"error":   nil,
+	})
+}
+
+// menuItemStations maps the menu items on the orders to their stations. Deleted menu items keep
+// theirs, so their lines still reach the right station.
+func menuItemStations(c *fiber.Ctx, orders []models.Order) (map[uint]string, error) {
+	var menuItemIDs []uint
+	for _, order := range orders {
+		for _, item := range order.OrderItems {
+			menuItemIDs = append(menuItemIDs, item.MenuItemID)
+		}
+	}
+	stations := make(map[uint]string)
+	if len(menuItemIDs) == 0 {
+		return stations, nil
+	}
+
+	var menuItems []models.MenuItem
+	if err := db(c).Unscoped().Select("id", "station").Where("id IN ?", menuItemIDs).Find(&menuItems).Error; err != nil {
+		return nil, err
+	}
+	for _, menuItem := range menuItems {
+		stations[menuItem.ID] = menuItem.Station
+	}
+	return stations, nil
+}
+
+// groupItemsByStation splits an order's items by station, keeping their order within each
+// station. Stations are sorted by name, with items that have none last.
+func groupItemsByStation(items []KitchenOrderItem) []KitchenStation {
+	groups := []KitchenStation{}
+	groupIndex := make(map[string]int)
+	for _, item := range items {
+		idx, ok := groupIndex[item.Station]
+		if !ok {
+			idx = len(groups)
+			groupIndex[item.Station] = idx
+			groups = append(groups, KitchenStation{Station: item.Station})
+		}
+		groups[idx].Items = append(groups[idx].Items, item)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if (groups[i].Station == "") != (groups[j].Station == "") {
+			return groups[j].Station == ""
+		}
+		return groups[i].Station < groups[j].Station
+	})
+	return groups
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestKitchenOrdersAreGroupedAndFilteredByStation(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_station", Password: "x", Email: "station@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Station Restaurant"}
+	database.DB.Create(&restaurant)
+	steak := models.MenuItem{RestaurantID: restaurant.ID, Name: "Steak", Price: 2200, Station: "grill"}
+	beer := models.MenuItem{RestaurantID: restaurant.ID, Name: "Beer", Price: 600, Station: "bar"}
+	bread := models.MenuItem{RestaurantID: restaurant.ID, Name: "Bread", Price: 300}
+	database.DB.Create(&steak)
+	database.DB.Create(&beer)
+	database.DB.Create(&bread)
+
+	mixed := models.Order{RestaurantID: restaurant.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusPending, OrderItems: []models.OrderItem{
+		{MenuItemID: bread.ID, ItemName: "Bread", Quantity: 1},
+		{MenuItemID: steak.ID, ItemName: "Steak", Quantity: 2},
+		{MenuItemID: beer.ID, ItemName: "Beer", Quantity: 2},
+	}}
+	kitchenOnly := models.Order{RestaurantID: restaurant.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusPending, OrderItems: []models.OrderItem{
+		{MenuItemID: steak.ID, ItemName: "Steak", Quantity: 1},
+	}}
+	database.DB.Create(&mixed)
+	database.DB.Create(&kitchenOnly)
+
+	app := fiber.New()
+	app.Get("/restaurant/:restaurant_id/kitchen", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetKitchenOrders(c)
+	})
+	pendingOrders := func(query string) []KitchenOrder {
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/kitchen%s", restaurant.ID, query), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		if resp.StatusCode != fiber.StatusOK {
+			t.Fatalf("expected 200, got %d", resp.StatusCode)
+		}
+		var body struct {
+			Data []KitchenGroup `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return body.Data[0].Orders
+	}
+
+	orders := pendingOrders("")
+	if len(orders) != 2 {
+		t.Fatalf("expected both orders, got %d", len(orders))
+	}
+	var stations []string
+	for _, group := range orders[0].Stations {
+		stations = append(stations, fmt.Sprintf("%s:%d", group.Station, len(group.Items)))
+	}
+	if fmt.Sprint(stations) != "[bar:1 grill:1 :1]" {
+		t.Fatalf("expected stations by name with unassigned items last, got %v", stations)
+	}
+
+	// Station names are matched case-insensitively, and orders with nothing for the station are left out
+	orders = pendingOrders("?station=Bar")
+	if len(orders) != 1 || orders[0].ID != mixed.ID {
+		t.Fatalf("expected only the order with a drink, got %+v", orders)
+	}
+	if len(orders[0].Items) != 1 || orders[0].Items[0].Name != "Beer" || orders[0].Items[0].Station != "bar" {
+		t.Fatalf("expected only the bar's items, got %+v", orders[0].Items)
+	}
+
+	// Moving an item to another station reroutes it on orders already in the kitchen
+	database.DB.Model(&steak).Update("station", "bar")
+	if orders := pendingOrders("?station=bar"); len(orders) != 2 {
+		t.Fatalf("expected the rerouted steak to reach the bar, got %d orders", len(orders))
+	}
+}
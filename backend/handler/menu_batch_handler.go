@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// maxBatchMenuItems caps how many menu items a single batch request may delete
+const maxBatchMenuItems = 200
+
+var (
+	// errMenuItemsNotFound is returned when some of the requested menu items don't belong to the restaurant
+	errMenuItemsNotFound = errors.New("menu items not found")
+	// errMenuItemsInUse is returned when some of the requested menu items are part of active orders
+	errMenuItemsInUse = errors.New("menu items used by active orders")
+)
+
+// DeleteMenuItemsBatch godoc
+// @Summary Delete menu items in batch
+// @Description Delete several menu items at once. Nothing is deleted unless every ID belongs to the restaurant; otherwise the response lists the IDs that weren't found in data.missing_ids.
+// @Description Items in active orders are listed in data.in_use_ids with 409 unless force=true is passed.
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param items body BatchDeleteMenuItemsRequest true "IDs of the menu items to delete"
+// @Param force query bool false "Delete even if active orders contain some of the items"
+// @Success 200 {object} Envelope[BatchDeleteMenuItemsResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or menu items not found"
+// @Failure 409 {object} ErrorEnvelope "Items are used by active orders"
+// @Failure 500 {object} ErrorEnvelope "Error deleting menu items"
+// @Router /api/restaurant/{restaurant_id}/menu [delete]
+func DeleteMenuItemsBatch(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request BatchDeleteMenuItemsRequest
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+
+	ids := slices.Compact(slices.Sorted(slices.Values(request.IDs)))
+	if len(ids) == 0 || len(ids) > maxBatchMenuItems {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, fmt.Sprintf("Provide between 1 and %d menu item IDs", maxBatchMenuItems))
+	}
+
+	force := c.QueryBool("force")
+	var menuItems []models.MenuItem
+	var missing, inUse []uint
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id IN ? AND restaurant_id = ?", ids, restaurant.ID).Order("id").Find(&menuItems).Error; err != nil {
+			return err
+		}
+
+		// All or nothing: report every ID that isn't one of the restaurant's items
+		found := make(map[uint]struct{}, len(menuItems))
+		for _, item := range menuItems {
+			found[item.ID] = struct{}{}
+		}
+		for _, id := range ids {
+			if _, ok := found[id]; !ok {
+				missing = append(missing, id)
+			}
+		}
+		if len(missing) > 0 {
+			return errMenuItemsNotFound
+		}
+
+		if !force {
+			var err error
+			if inUse, err = menuItemsInActiveOrders(tx, ids); err != nil {
+				return err
+			}
+			if len(inUse) > 0 {
+				return errMenuItemsInUse
+			}
+		}
+
+		return tx.Delete(&menuItems).Error
+	}); err != nil {
+		if errors.Is(err, errMenuItemsNotFound) {
+			return utils.SendErrorWithData(c, fiber.StatusNotFound, constants.ErrCodeMenuItemNotFound, "Menu items not found", fiber.Map{"missing_ids": missing})
+		}
+		if errors.Is(err, errMenuItemsInUse) {
+			return utils.SendErrorWithData(c, fiber.StatusConflict, constants.ErrCodeMenuItemInUse, "Items are used by active orders", fiber.Map{"in_use_ids": inUse})
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error deleting menu items")
+	}
+
+	globalMenuCache.invalidate(restaurant.ID)
+	for _, item := range menuItems {
+		recordAudit(c, restaurant, constants.AuditActionDelete, constants.AuditEntityMenuItem, item.ID, fmt.Sprintf("Deleted menu item %q", item.Name))
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    BatchDeleteMenuItemsResponse{Deleted: ids},
+		"error":   nil,
+	})
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDeleteMenuItemsBatchIsAllOrNothing(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_batchdelete", Password: "x", Email: "batchdelete@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Batch Delete Restaurant"}
+	database.DB.Create(&restaurant)
+	other := models.Restaurant{UserID: user.ID, Name: "Other Restaurant"}
+	database.DB.Create(&other)
+
+	first := models.MenuItem{RestaurantID: restaurant.ID, Name: "First", Price: 500}
+	second := models.MenuItem{RestaurantID: restaurant.ID, Name: "Second", Price: 700}
+	foreign := models.MenuItem{RestaurantID: other.ID, Name: "Foreign", Price: 900}
+	for _, item := range []*models.MenuItem{&first, &second, &foreign} {
+		database.DB.Create(item)
+	}
+
+	app := fiber.New()
+	app.Delete("/restaurant/:restaurant_id/menu", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return DeleteMenuItemsBatch(c)
+	})
+	deleteItems := func(ids ...uint) (int, map[string]any) {
+		payload, _ := json.Marshal(BatchDeleteMenuItemsRequest{IDs: ids})
+		req := httptest.NewRequest("DELETE", fmt.Sprintf("/restaurant/%d/menu", restaurant.ID), strings.NewReader(string(payload)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		return resp.StatusCode, body
+	}
+
+	status, body := deleteItems(first.ID, foreign.ID, 999999)
+	if status != fiber.StatusNotFound {
+		t.Fatalf("expected 404 when an item isn't owned, got %d", status)
+	}
+	if body["code"] != constants.ErrCodeMenuItemNotFound {
+		t.Fatalf("expected code %s, got %v", constants.ErrCodeMenuItemNotFound, body["code"])
+	}
+	missing := body["data"].(map[string]any)["missing_ids"].([]any)
+	if len(missing) != 2 || uint(missing[0].(float64)) != foreign.ID || uint(missing[1].(float64)) != 999999 {
+		t.Fatalf("expected missing_ids [%d 999999], got %v", foreign.ID, missing)
+	}
+	var remaining int64
+	database.DB.Model(&models.MenuItem{}).Where("restaurant_id = ?", restaurant.ID).Count(&remaining)
+	if remaining != 2 {
+		t.Fatalf("expected nothing to be deleted after a rejected batch, %d items remain", remaining)
+	}
+
+	if status, _ := deleteItems(); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an empty batch, got %d", status)
+	}
+
+	if status, _ := deleteItems(second.ID, first.ID, first.ID); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	database.DB.Model(&models.MenuItem{}).Where("restaurant_id = ?", restaurant.ID).Count(&remaining)
+	if remaining != 0 {
+		t.Fatalf("expected both items to be deleted, %d remain", remaining)
+	}
+	var deleted int64
+	database.DB.Unscoped().Model(&models.MenuItem{}).Where("restaurant_id = ? AND deleted_at IS NOT NULL", restaurant.ID).Count(&deleted)
+	if deleted != 2 {
+		t.Fatalf("expected both items to be soft-deleted, got %d", deleted)
+	}
+}
+
+func TestDeleteMenuItemsBatchRejectsItemsInActiveOrders(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_batchinuse", Password: "x", Email: "batchinuse@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Batch In Use Restaurant"}
+	database.DB.Create(&restaurant)
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 1}
+	database.DB.Create(&table)
+	free := models.MenuItem{RestaurantID: restaurant.ID, Name: "Free", Price: 500}
+	ordered := models.MenuItem{RestaurantID: restaurant.ID, Name: "Ordered", Price: 700}
+	database.DB.Create(&free)
+	database.DB.Create(&ordered)
+	order := models.Order{RestaurantID: restaurant.ID, TableID: &table.ID, TotalAmount: 700, OrderItems: []models.OrderItem{{MenuItemID: ordered.ID, Quantity: 1}}}
+	if err := database.DB.Create(&order).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+
+	app := fiber.New()
+	app.Delete("/restaurant/:restaurant_id/menu", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return DeleteMenuItemsBatch(c)
+	})
+	deleteItems := func(query string) (int, map[string]any) {
+		payload, _ := json.Marshal(BatchDeleteMenuItemsRequest{IDs: []uint{free.ID, ordered.ID}})
+		req := httptest.NewRequest("DELETE", fmt.Sprintf("/restaurant/%d/menu%s", restaurant.ID, query), strings.NewReader(string(payload)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		return resp.StatusCode, body
+	}
+
+	status, body := deleteItems("")
+	if status != fiber.StatusConflict {
+		t.Fatalf("expected 409 when an item is in an active order, got %d", status)
+	}
+	inUse := body["data"].(map[string]any)["in_use_ids"].([]any)
+	if len(inUse) != 1 || uint(inUse[0].(float64)) != ordered.ID {
+		t.Fatalf("expected in_use_ids [%d], got %v", ordered.ID, inUse)
+	}
+
+	if status, _ := deleteItems("?force=true"); status != fiber.StatusOK {
+		t.Fatalf("expected force=true to delete the items, got %d", status)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultPublicMenuCacheTTL is how long a public menu is served from memory when
+// PUBLIC_MENU_CACHE_TTL is not set
+const DefaultPublicMenuCacheTTL = 30 * time.Second
+
+// menuCache keeps each restaurant's public menu in memory, so a rush of QR scans reads the
+// database once per TTL instead of once per scan. Every write to a restaurant's menu items,
+// categories or stock invalidates that restaurant; the TTL bounds staleness from anything else.
+type menuCache struct {
+	mu          sync.Mutex
+	ttl         time.Duration // zero disables the cache
+	restaurants map[uint]*cachedRestaurantMenu
+}
+
+type cachedRestaurantMenu struct {
+	generation uint64 // bumped on invalidate so a menu loaded before a write is never stored
+	entries    map[menuCacheKey]cachedMenu
+}
+
+// menuCacheKey identifies one variant of a restaurant's public menu
+type menuCacheKey struct {
+	dietary string // normalized tags, comma-separated
+	sort    listSort
+}
+
+type cachedMenu struct {
+	items   []PublicMenuItem
+	expires time.Time
+}
+
+var globalMenuCache = &menuCache{restaurants: make(map[uint]*cachedRestaurantMenu)}
+
+// SetPublicMenuCacheTTL sets how long public menus are cached; zero turns caching off
+func SetPublicMenuCacheTTL(ttl time.Duration) {
+	globalMenuCache.mu.Lock()
+	defer globalMenuCache.mu.Unlock()
+	globalMenuCache.ttl = ttl
+	globalMenuCache.restaurants = make(map[uint]*cachedRestaurantMenu)
+}
+
+func newMenuCacheKey(dietary []string, sort listSort) menuCacheKey {
+	return menuCacheKey{dietary: strings.Join(dietary, ","), sort: sort}
+}
+
+// get returns the cached menu for key, if fresh, and the generation to pass to set after a miss
+func (m *menuCache) get(restaurantID uint, key menuCacheKey) ([]PublicMenuItem, uint64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	menus := m.restaurants[restaurantID]
+	if m.ttl <= 0 || menus == nil {
+		return nil, 0, false
+	}
+	entry, ok := menus.entries[key]
+	if !ok || time.Now().After(entry.expires) {
+		return nil, menus.generation, false
+	}
+	return entry.items, menus.generation, true
+}
+
+// set stores a menu loaded at generation, unless the restaurant's menu changed since
+func (m *menuCache) set(restaurantID uint, key menuCacheKey, generation uint64, items []PublicMenuItem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ttl <= 0 {
+		return
+	}
+	menus := m.restaurants[restaurantID]
+	if menus == nil {
+		menus = &cachedRestaurantMenu{}
+		m.restaurants[restaurantID] = menus
+	}
+	if menus.generation != generation {
+		return
+	}
+	if menus.entries == nil {
+		menus.entries = make(map[menuCacheKey]cachedMenu)
+	}
+
+	now := time.Now()
+	for existing, entry := range menus.entries {
+		if now.After(entry.expires) {
+			delete(menus.entries, existing)
+		}
+	}
+	menus.entries[key] = cachedMenu{items: items, expires: now.Add(m.ttl)}
+}
+
+// invalidate drops every cached menu of a restaurant; call it after a write to its menu commits
+func (m *menuCache) invalidate(restaurantIDs ...uint) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.ttl <= 0 {
+		return
+	}
+	for _, restaurantID := range restaurantIDs {
+		menus := m.restaurants[restaurantID]
+		if menus == nil {
+			// Record the bump so a load already in flight doesn't store what it read before the write
+			m.restaurants[restaurantID] = &cachedRestaurantMenu{generation: 1}
+			continue
+		}
+		menus.generation++
+		menus.entries = nil
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestPublicMenuCacheInvalidatedByMenuWrites(t *testing.T) {
+	testutil.SetupDB(t)
+	SetPublicMenuCacheTTL(time.Minute)
+	defer SetPublicMenuCacheTTL(0)
+
+	user := models.User{Username: "testuser_menucache", Password: "x", Email: "menucache@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Menu Cache Restaurant"}
+	database.DB.Create(&restaurant)
+	soup := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 500, Quantity: 5}
+	database.DB.Create(&soup)
+
+	app := fiber.New()
+	app.Get("/restaurants/:restaurant_id/menu", GetPublicMenuItems)
+	app.Delete("/restaurant/:restaurant_id/menu/:id", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return DeleteMenuItem(c)
+	})
+	menuLength := func() int {
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurants/%d/menu", restaurant.ID), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data []PublicMenuItem `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		return len(body.Data)
+	}
+
+	if n := menuLength(); n != 1 {
+		t.Fatalf("expected 1 item, got %d", n)
+	}
+
+	// Written behind the handlers' back, so the cached menu is still served
+	salad := models.MenuItem{RestaurantID: restaurant.ID, Name: "Salad", Price: 700, Quantity: 5}
+	database.DB.Create(&salad)
+	if n := menuLength(); n != 1 {
+		t.Fatalf("expected the cached menu with 1 item, got %d", n)
+	}
+
+	req := httptest.NewRequest("DELETE", fmt.Sprintf("/restaurant/%d/menu/%d", restaurant.ID, soup.ID), nil)
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("delete menu item: expected status 200, got %d", resp.StatusCode)
+	}
+	if n := menuLength(); n != 1 {
+		t.Fatalf("expected only the salad after the delete, got %d items", n)
+	}
+}
+
+func TestMenuCacheDropsMenuLoadedBeforeInvalidate(t *testing.T) {
+	cache := &menuCache{ttl: time.Minute, restaurants: make(map[uint]*cachedRestaurantMenu)}
+	key := newMenuCacheKey(nil, listSort{})
+
+	_, generation, ok := cache.get(1, key)
+	if ok {
+		t.Fatal("expected a miss on an empty cache")
+	}
+	cache.invalidate(1)
+	cache.set(1, key, generation, []PublicMenuItem{{}})
+	if _, _, ok := cache.get(1, key); ok {
+		t.Fatal("expected a menu loaded before the invalidation not to be cached")
+	}
+
+	_, generation, _ = cache.get(1, key)
+	cache.set(1, key, generation, []PublicMenuItem{{}})
+	if items, _, ok := cache.get(1, key); !ok || len(items) != 1 {
+		t.Fatalf("expected a cached menu, got %v (hit %v)", items, ok)
+	}
+}
@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// errMenuCategoryNotFound is returned when a category_id doesn't belong to the restaurant
+var errMenuCategoryNotFound = errors.New("menu category not found")
+
+// resolveMenuCategory returns the category for a menu item: the given category ID if set,
+// otherwise the restaurant's category with a case-insensitively matching name, created if missing.
+// It returns nil when neither is provided.
+func resolveMenuCategory(tx *gorm.DB, restaurantID uint, categoryID *uint, name string) (*models.MenuCategory, error) {
+	var category models.MenuCategory
+	if categoryID != nil {
+		if err := tx.Where("id = ? AND restaurant_id = ?", *categoryID, restaurantID).First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errMenuCategoryNotFound
+			}
+			return nil, err
+		}
+		return &category, nil
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, nil
+	}
+	findByName := func() error {
+		return tx.Where("restaurant_id = ? AND LOWER(name) = LOWER(?)", restaurantID, name).First(&category).Error
+	}
+	err := findByName()
+	if err == nil {
+		return &category, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// New categories go to the end of the menu
+	var count int64
+	if err := tx.Model(&models.MenuCategory{}).Where("restaurant_id = ?", restaurantID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	category = models.MenuCategory{RestaurantID: restaurantID, Name: name, DisplayOrder: int(count)}
+
+	// A concurrent request may create the same category first; the unique name index turns that
+	// into a conflict, and the savepoint keeps tx usable to load the winner's category instead
+	err = tx.Transaction(func(savepoint *gorm.DB) error {
+		return savepoint.Create(&category).Error
+	})
+	if status, _, _ := utils.MapDBError(err); err != nil && status == fiber.StatusConflict {
+		category = models.MenuCategory{}
+		err = findByName()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// applyMenuCategory links a menu item to its category, keeping the denormalized name in sync
+func applyMenuCategory(menuItem *models.MenuItem, category *models.MenuCategory) {
+	if category == nil {
+		menuItem.CategoryID = nil
+		menuItem.Category = ""
+		return
+	}
+	menuItem.CategoryID = &category.ID
+	menuItem.Category = category.Name
+}
+
+// CreateMenuCategory godoc
+// @Summary Create a menu category
+// @Description Create a menu category for a restaurant
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param category body MenuCategory true "Category data"
+// @Success 201 {object} Envelope[MenuCategory]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 409 {object} ErrorEnvelope "Category already exists"
+// @Failure 500 {object} ErrorEnvelope "Error creating category"
+// @Router /api/restaurant/{restaurant_id}/menu-categories [post]
+func CreateMenuCategory(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request MenuCategory
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid input")
+	}
+
+	var existing int64
+	db(c).Model(&models.MenuCategory{}).
+		Where("restaurant_id = ? AND LOWER(name) = LOWER(?)", restaurant.ID, strings.TrimSpace(request.Name)).
+		Count(&existing)
+	if existing > 0 {
+		return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeCategoryExists, "Category already exists")
+	}
+
+	category := models.MenuCategory{
+		RestaurantID: restaurant.ID,
+		Name:         strings.TrimSpace(request.Name),
+		DisplayOrder: request.DisplayOrder,
+	}
+	if err := db(c).Create(&category).Error; err != nil {
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error creating category"
+		}
+		return utils.SendError(c, status, code, message)
+	}
+
+	globalMenuCache.invalidate(restaurant.ID)
+	recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityMenuCategory, category.ID, fmt.Sprintf("Created menu category %q", category.Name))
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    category,
+		"error":   nil,
+	})
+}
+
+// GetMenuCategories godoc
+// @Summary Get menu categories
+// @Description Get a restaurant's menu categories in display order
+// @Tags Menu
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Success 200 {object} Envelope[[]MenuCategory]
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving categories"
+// @Router /api/restaurant/{restaurant_id}/menu-categories [get]
+func GetMenuCategories(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	categories := []models.MenuCategory{}
+	if err := db(c).Where("restaurant_id = ?", restaurant.ID).Order("display_order, name").Find(&categories).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving categories")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    categories,
+		"error":   nil,
+	})
+}
+
+// UpdateMenuCategory godoc
+// @Summary Update a menu category
+// @Description Rename or reorder a menu category; renaming updates the category shown on its menu items
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Category ID"
+// @Param category body MenuCategory true "Category data"
+// @Success 200 {object} Envelope[MenuCategory]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or category not found"
+// @Failure 409 {object} ErrorEnvelope "Category already exists"
+// @Failure 500 {object} ErrorEnvelope "Error updating category"
+// @Router /api/restaurant/{restaurant_id}/menu-categories/{id} [put]
+func UpdateMenuCategory(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+	categoryID := c.Params("id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var category models.MenuCategory
+	if err := db(c).Where("id = ? AND restaurant_id = ?", categoryID, restaurant.ID).First(&category).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeCategoryNotFound, "Category not found")
+	}
+
+	var request MenuCategory
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if strings.TrimSpace(request.Name) == "" {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid input")
+	}
+
+	var existing int64
+	db(c).Model(&models.MenuCategory{}).
+		Where("restaurant_id = ? AND LOWER(name) = LOWER(?) AND id <> ?", restaurant.ID, strings.TrimSpace(request.Name), category.ID).
+		Count(&existing)
+	if existing > 0 {
+		return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeCategoryExists, "Category already exists")
+	}
+
+	category.Name = strings.TrimSpace(request.Name)
+	category.DisplayOrder = request.DisplayOrder
+
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&category).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.MenuItem{}).Where("category_id = ?", category.ID).Update("category", category.Name).Error
+	}); err != nil {
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error updating category"
+		}
+		return utils.SendError(c, status, code, message)
+	}
+
+	globalMenuCache.invalidate(restaurant.ID)
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntityMenuCategory, category.ID, fmt.Sprintf("Updated menu category %q", category.Name))
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    category,
+		"error":   nil,
+	})
+}
+
+// DeleteMenuCategory godoc
+// @Summary Delete a menu category
+// @Description Delete a menu category; its menu items are kept and become uncategorized
+// @Tags Menu
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Category ID"
+// @Success 200 {object} Envelope[string]
+// @Failure 404 {object} ErrorEnvelope "Restaurant or category not found"
+// @Failure 500 {object} ErrorEnvelope "Error deleting category"
+// @Router /api/restaurant/{restaurant_id}/menu-categories/{id} [delete]
+func DeleteMenuCategory(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+	categoryID := c.Params("id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var category models.MenuCategory
+	if err := db(c).Where("id = ? AND restaurant_id = ?", categoryID, restaurant.ID).First(&category).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeCategoryNotFound, "Category not found")
+	}
+
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.MenuItem{}).Where("category_id = ?", category.ID).
+			Updates(map[string]interface{}{"category_id": nil, "category": ""}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&category).Error
+	}); err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error deleting category")
+	}
+
+	globalMenuCache.invalidate(restaurant.ID)
+	recordAudit(c, restaurant, constants.AuditActionDelete, constants.AuditEntityMenuCategory, category.ID, fmt.Sprintf("Deleted menu category %q", category.Name))
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    "Category deleted successfully",
+		"error":   nil,
+	})
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"order-system/utils"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMenuCategoryNamesAreUniqueIgnoringCase(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_category_case", Password: "x", Email: "categorycase@example.com"}
+	assert.NoError(t, database.DB.Create(&user).Error)
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Category Case Restaurant"}
+	assert.NoError(t, database.DB.Create(&restaurant).Error)
+
+	drinks, err := resolveMenuCategory(database.DB, restaurant.ID, nil, "Drinks")
+	assert.NoError(t, err)
+	again, err := resolveMenuCategory(database.DB, restaurant.ID, nil, " drinks ")
+	assert.NoError(t, err)
+	assert.Equal(t, drinks.ID, again.ID, "a differently cased name should reuse the category")
+
+	status, code, _ := utils.MapDBError(database.DB.Create(&models.MenuCategory{RestaurantID: restaurant.ID, Name: "DRINKS"}).Error)
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, constants.ErrCodeCategoryExists, code)
+
+	// Deleted categories don't hold on to their name
+	assert.NoError(t, database.DB.Delete(&models.MenuCategory{}, drinks.ID).Error)
+	assert.NoError(t, database.DB.Create(&models.MenuCategory{RestaurantID: restaurant.ID, Name: "drinks"}).Error)
+}
+
+func TestConcurrentMenuItemsShareANewCategory(t *testing.T) {
+	testutil.RequirePostgres(t)
+
+	const attempts = 10
+
+	user := models.User{Username: "testuser_category_race", Password: "x", Email: "categoryrace@example.com"}
+	assert.NoError(t, database.DB.Create(&user).Error)
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Category Race Restaurant"}
+	assert.NoError(t, database.DB.Create(&restaurant).Error)
+
+	app := fiber.New()
+	app.Post("/restaurant/:restaurant_id/menu", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return CreateMenuItem(c)
+	})
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	statuses := map[int]int{}
+	start := make(chan struct{})
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+
+			name := "Specials"
+			if i%2 == 1 {
+				name = "specials"
+			}
+			payload := fmt.Sprintf(`{"name": "Dish %d", "price": 500, "category": %q}`, i, name)
+			req := httptest.NewRequest("POST", fmt.Sprintf("/restaurant/%d/menu", restaurant.ID), strings.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[resp.StatusCode]++
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, attempts, statuses[fiber.StatusCreated], "every item should be created")
+
+	var categories int64
+	assert.NoError(t, database.DB.Model(&models.MenuCategory{}).Where("restaurant_id = ?", restaurant.ID).Count(&categories).Error)
+	assert.Equal(t, int64(1), categories, "the items should share one category")
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"errors"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxFeaturedItems caps how many menu items a restaurant can feature at once
+const maxFeaturedItems = 10
+
+// errFeaturedLimitReached is returned when featuring another item would exceed maxFeaturedItems
+var errFeaturedLimitReached = errors.New("featured item limit reached")
+
+// checkFeaturedLimit returns errFeaturedLimitReached if the restaurant already features
+// maxFeaturedItems items other than excludeID. The restaurant row is locked so concurrent
+// updates can't both take the last slot.
+func checkFeaturedLimit(tx *gorm.DB, restaurantID, excludeID uint) error {
+	var restaurant models.Restaurant
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&restaurant, restaurantID).Error; err != nil {
+		return err
+	}
+
+	var count int64
+	if err := tx.Model(&models.MenuItem{}).
+		Where("restaurant_id = ? AND is_featured = ? AND id <> ?", restaurantID, true, excludeID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count >= maxFeaturedItems {
+		return errFeaturedLimitReached
+	}
+	return nil
+}
+
+// GetFeaturedMenuItems godoc
+// @Summary Get featured menu items
+// @Description Get a restaurant's featured menu items in featured order without authentication
+// @Tags Menu
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Success 200 {object} Envelope[[]PublicMenuItem]
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving menu items"
+// @Router /api/restaurants/{restaurant_id}/menu/featured [get]
+func GetFeaturedMenuItems(c *fiber.Ctx) error {
+	restaurantID := c.Params("restaurant_id")
+
+	var restaurant models.Restaurant
+	if err := readDB(c).First(&restaurant, restaurantID).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var menuItems []models.MenuItem
+	if err := readDB(c).Where("restaurant_id = ? AND is_featured = ?", restaurant.ID, true).
+		Order("featured_order, id").
+		Limit(maxFeaturedItems).
+		Find(&menuItems).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving menu items")
+	}
+
+	newSince := newMenuItemSince()
+	featured := make([]PublicMenuItem, 0, len(menuItems))
+	for _, item := range menuItems {
+		featured = append(featured, newPublicMenuItem(item, newSince))
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    featured,
+		"error":   nil,
+	})
+}
@@ -1,12 +1,23 @@
 package handler
 
 import (
-	"order-system/database"
+	"errors"
+	"fmt"
+	"order-system/constants"
 	"order-system/models"
+	"order-system/utils"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// errMenuItemVersionConflict is returned when an update was based on an outdated version of the item
+var errMenuItemVersionConflict = errors.New("menu item version conflict")
+
 // CreateMenuItem godoc
 // @Summary Create a new menu item
 // @Description Create a new menu item for a restaurant
@@ -16,59 +27,114 @@ import (
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param menu_item body MenuItem true "Menu item data"
-// @Success 201 {object} MenuItem
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "Restaurant not found"
-// @Failure 500 {string} string "Error creating menu item"
+// @Success 201 {object} Envelope[MenuItem]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 409 {object} ErrorEnvelope "SKU already in use"
+// @Failure 500 {object} ErrorEnvelope "Error creating menu item"
 // @Router /api/restaurant/{restaurant_id}/menu [post]
 func CreateMenuItem(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var request struct {
-		Name        string  `json:"name"`
-		Description string  `json:"description"`
-		Price       float64 `json:"price"`
-		Category    string  `json:"category"`
-		ImageURL    string  `json:"image_url"`
-		Quantity    int     `json:"quantity"`
+		SKU           string      `json:"sku"`
+		Name          string      `json:"name"`
+		Description   string      `json:"description"`
+		Price         utils.Money `json:"price"`
+		Category      string      `json:"category"`
+		CategoryID    *uint       `json:"category_id"`
+		ImageURL      string      `json:"image_url"`
+		Quantity      int         `json:"quantity"`
+		DietaryTags   []string    `json:"dietary_tags"`
+		Allergens     []string    `json:"allergens"`
+		IsFeatured    bool        `json:"is_featured"`
+		FeaturedOrder int         `json:"featured_order"`
+		Station       string      `json:"station"`
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
+	}
+
+	sku, err := normalizeSKU(request.SKU)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+	}
+
+	dietaryTags, err := utils.NormalizeDietaryTags(request.DietaryTags)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+	}
+	allergens, err := utils.NormalizeAllergens(request.Allergens)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+	}
+	station, err := normalizeStation(request.Station)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
 	}
 
 	menuItem := models.MenuItem{
-		RestaurantID: restaurant.ID,
-		Name:         request.Name,
-		Description:  request.Description,
-		Price:        request.Price,
-		Category:     request.Category,
-		ImageURL:     request.ImageURL,
-		Quantity:     request.Quantity,
-	}
-
-	if err := database.DB.Create(&menuItem).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error creating menu item",
-		})
+		RestaurantID:  restaurant.ID,
+		SKU:           sku,
+		Name:          request.Name,
+		Description:   request.Description,
+		Price:         request.Price,
+		ImageURL:      request.ImageURL,
+		Quantity:      request.Quantity,
+		DietaryTags:   dietaryTags,
+		Allergens:     allergens,
+		IsFeatured:    request.IsFeatured,
+		FeaturedOrder: request.FeaturedOrder,
+		Station:       station,
+	}
+
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		// Link to the chosen category, or find/create one from the free-text name
+		category, err := resolveMenuCategory(tx, restaurant.ID, request.CategoryID, request.Category)
+		if err != nil {
+			return err
+		}
+		applyMenuCategory(&menuItem, category)
+		if err := checkSKUAvailable(tx, restaurant.ID, menuItem.SKU, menuItem.ID); err != nil {
+			return err
+		}
+		if menuItem.IsFeatured {
+			if err := checkFeaturedLimit(tx, restaurant.ID, 0); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(&menuItem).Error; err != nil {
+			return err
+		}
+		return recordStockMovement(tx, &menuItem, constants.StockMovementInitial, menuItem.Quantity, nil, nil, "")
+	}); err != nil {
+		if errors.Is(err, errMenuCategoryNotFound) {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeCategoryNotFound, "Menu category not found")
+		}
+		if errors.Is(err, errSKUTaken) {
+			return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeSKUInUse, "SKU already in use")
+		}
+		if errors.Is(err, errFeaturedLimitReached) {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeFeaturedLimitReached, fmt.Sprintf("A restaurant can feature at most %d menu items", maxFeaturedItems))
+		}
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error creating menu item"
+		}
+		return utils.SendError(c, status, code, message)
 	}
 
+	globalMenuCache.invalidate(restaurant.ID)
+	recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityMenuItem, menuItem.ID, fmt.Sprintf("Created menu item %q", menuItem.Name))
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"success": true,
 		"data":    menuItem,
@@ -76,37 +142,91 @@ func CreateMenuItem(c *fiber.Ctx) error {
 	})
 }
 
+// priceRange is an optional inclusive price band from the min_price and max_price query parameters
+type priceRange struct {
+	Min *utils.Money
+	Max *utils.Money
+}
+
+// parsePriceRange reads min_price and max_price as decimal amounts, rejecting negative values
+// and a minimum above the maximum
+func parsePriceRange(c *fiber.Ctx) (priceRange, error) {
+	var prices priceRange
+	var err error
+	if prices.Min, err = parsePriceQuery(c, "min_price"); err != nil {
+		return prices, err
+	}
+	if prices.Max, err = parsePriceQuery(c, "max_price"); err != nil {
+		return prices, err
+	}
+	if prices.Min != nil && prices.Max != nil && *prices.Min > *prices.Max {
+		return prices, errors.New("min_price must not be greater than max_price")
+	}
+	return prices, nil
+}
+
+// parsePriceQuery reads an optional non-negative amount from the named query parameter
+func parsePriceQuery(c *fiber.Ctx, name string) (*utils.Money, error) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	amount, err := utils.ParseMoney(raw)
+	if err != nil || amount < 0 {
+		return nil, fmt.Errorf("%s must be a non-negative amount", name)
+	}
+	return &amount, nil
+}
+
+// apply restricts query to menu items priced within the range
+func (prices priceRange) apply(query *gorm.DB) *gorm.DB {
+	if prices.Min != nil {
+		query = query.Where("price >= ?", *prices.Min)
+	}
+	if prices.Max != nil {
+		query = query.Where("price <= ?", *prices.Max)
+	}
+	return query
+}
+
 // GetMenuItems godoc
 // @Summary Get all menu items
-// @Description Get all menu items for a restaurant
+// @Description Get all menu items for a restaurant, optionally only those priced within min_price and max_price (inclusive)
 // @Tags Menu
 // @Produce json
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
-// @Success 200 {array} MenuItem
-// @Failure 404 {string} string "Restaurant not found"
-// @Failure 500 {string} string "Error retrieving menu items"
+// @Param min_price query string false "Lowest price to include, e.g. 5.00"
+// @Param max_price query string false "Highest price to include, e.g. 20.00"
+// @Param sort query string false "Order by price, name or created_at, optionally with :asc or :desc, e.g. price:desc"
+// @Success 200 {object} Envelope[[]MenuItem]
+// @Failure 400 {object} ErrorEnvelope "Invalid price range or sort"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving menu items"
 // @Router /api/restaurant/{restaurant_id}/menu [get]
 func GetMenuItems(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	prices, err := parsePriceRange(c)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid price range: "+err.Error())
+	}
+	sortBy, err := parseSort(c, menuItemSortColumns)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid sort: "+err.Error())
+	}
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var menuItems []models.MenuItem
-	if err := database.DB.Where("restaurant_id = ?", restaurant.ID).Find(&menuItems).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving menu items",
-		})
+	query := sortBy.apply(prices.apply(db(c).Where("restaurant_id = ?", restaurant.ID)))
+	if err := query.Find(&menuItems).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving menu items")
 	}
 
 	return c.JSON(fiber.Map{
@@ -118,7 +238,7 @@ func GetMenuItems(c *fiber.Ctx) error {
 
 // UpdateMenuItem godoc
 // @Summary Update a menu item
-// @Description Update a menu item
+// @Description Update a menu item. quantity is only changed when sent. Send the item's version to reject the update with 409 if the item changed since it was loaded, including stock taken by orders.
 // @Tags Menu
 // @Accept json
 // @Produce json
@@ -126,66 +246,154 @@ func GetMenuItems(c *fiber.Ctx) error {
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param id path string true "Item ID"
 // @Param menu_item body MenuItem true "Menu item data"
-// @Success 200 {object} MenuItem
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "Restaurant or menu item not found"
-// @Failure 500 {string} string "Error updating menu item"
+// @Success 200 {object} Envelope[MenuItem]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or menu item not found"
+// @Failure 409 {object} ErrorEnvelope "SKU already in use, or version conflict with the current item in data.current"
+// @Failure 500 {object} ErrorEnvelope "Error updating menu item"
 // @Router /api/restaurant/{restaurant_id}/menu/{id} [put]
 func UpdateMenuItem(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 	itemID := c.Params("id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var menuItem models.MenuItem
-	if err := database.DB.Where("id = ? AND restaurant_id = ?", itemID, restaurant.ID).First(&menuItem).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Menu item not found",
-		})
+	if err := db(c).Where("id = ? AND restaurant_id = ?", itemID, restaurant.ID).First(&menuItem).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeMenuItemNotFound, "Menu item not found")
 	}
 
 	var request struct {
-		Name        string  `json:"name"`
-		Description string  `json:"description"`
-		Price       float64 `json:"price"`
-		Category    string  `json:"category"`
-		ImageURL    string  `json:"image_url"`
-		Quantity    int     `json:"quantity"`
+		SKU           *string     `json:"sku"`
+		Name          string      `json:"name"`
+		Description   string      `json:"description"`
+		Price         utils.Money `json:"price"`
+		Category      string      `json:"category"`
+		CategoryID    *uint       `json:"category_id"`
+		ImageURL      string      `json:"image_url"`
+		Quantity      *int        `json:"quantity"`
+		DietaryTags   []string    `json:"dietary_tags"`
+		Allergens     []string    `json:"allergens"`
+		IsFeatured    *bool       `json:"is_featured"`
+		FeaturedOrder *int        `json:"featured_order"`
+		Station       *string     `json:"station"`
+		Version       *int        `json:"version"`
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
+	}
+
+	var dietaryTags, allergens utils.StringList
+	var sku *string
+	var station string
+	if request.DietaryTags != nil {
+		if dietaryTags, err = utils.NormalizeDietaryTags(request.DietaryTags); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+		}
+	}
+	if request.Allergens != nil {
+		if allergens, err = utils.NormalizeAllergens(request.Allergens); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+		}
+	}
+	if request.SKU != nil {
+		if sku, err = normalizeSKU(*request.SKU); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+		}
+	}
+	if request.Station != nil {
+		if station, err = normalizeStation(*request.Station); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+		}
 	}
 
-	menuItem.Name = request.Name
-	menuItem.Description = request.Description
-	menuItem.Price = request.Price
-	menuItem.Category = request.Category
-	menuItem.ImageURL = request.ImageURL
-	menuItem.Quantity = request.Quantity
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		// Re-read under lock: orders decrement stock concurrently, so the copy loaded above may be stale
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&menuItem, menuItem.ID).Error; err != nil {
+			return err
+		}
+		if request.Version != nil && *request.Version != menuItem.Version {
+			return errMenuItemVersionConflict
+		}
 
-	if err := database.DB.Save(&menuItem).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error updating menu item",
-		})
+		menuItem.Name = request.Name
+		menuItem.Description = request.Description
+		menuItem.Price = request.Price
+		menuItem.ImageURL = request.ImageURL
+
+		// Stock is only overwritten when the client sends it
+		previousQuantity := menuItem.Quantity
+		if request.Quantity != nil {
+			menuItem.Quantity = *request.Quantity
+		}
+
+		// Omitted fields keep their current values so older clients don't wipe them
+		if request.DietaryTags != nil {
+			menuItem.DietaryTags = dietaryTags
+		}
+		if request.Allergens != nil {
+			menuItem.Allergens = allergens
+		}
+		if request.SKU != nil {
+			menuItem.SKU = sku
+		}
+		if request.IsFeatured != nil {
+			menuItem.IsFeatured = *request.IsFeatured
+		}
+		if request.FeaturedOrder != nil {
+			menuItem.FeaturedOrder = *request.FeaturedOrder
+		}
+		if request.Station != nil {
+			menuItem.Station = station
+		}
+
+		category, err := resolveMenuCategory(tx, restaurant.ID, request.CategoryID, request.Category)
+		if err != nil {
+			return err
+		}
+		applyMenuCategory(&menuItem, category)
+		if err := checkSKUAvailable(tx, restaurant.ID, menuItem.SKU, menuItem.ID); err != nil {
+			return err
+		}
+		if menuItem.IsFeatured {
+			if err := checkFeaturedLimit(tx, restaurant.ID, menuItem.ID); err != nil {
+				return err
+			}
+		}
+		menuItem.Version++
+		if err := tx.Save(&menuItem).Error; err != nil {
+			return err
+		}
+		return recordStockMovement(tx, &menuItem, constants.StockMovementManual, menuItem.Quantity-previousQuantity, nil, nil, "")
+	}); err != nil {
+		if errors.Is(err, errMenuItemVersionConflict) {
+			return utils.SendErrorWithData(c, fiber.StatusConflict, constants.ErrCodeVersionConflict, "Menu item was changed since it was loaded; reload and try again", fiber.Map{"current": menuItem})
+		}
+		if errors.Is(err, errMenuCategoryNotFound) {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeCategoryNotFound, "Menu category not found")
+		}
+		if errors.Is(err, errSKUTaken) {
+			return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeSKUInUse, "SKU already in use")
+		}
+		if errors.Is(err, errFeaturedLimitReached) {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeFeaturedLimitReached, fmt.Sprintf("A restaurant can feature at most %d menu items", maxFeaturedItems))
+		}
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error updating menu item"
+		}
+		return utils.SendError(c, status, code, message)
 	}
 
+	globalMenuCache.invalidate(restaurant.ID)
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntityMenuItem, menuItem.ID, fmt.Sprintf("Updated menu item %q", menuItem.Name))
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"data":    menuItem,
@@ -195,47 +403,51 @@ func UpdateMenuItem(c *fiber.Ctx) error {
 
 // DeleteMenuItem godoc
 // @Summary Delete a menu item
-// @Description Delete a menu item
+// @Description Delete a menu item. Items in active orders are only deleted with force=true; the orders keep referencing the deleted item.
 // @Tags Menu
 // @Produce json
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param id path string true "Item ID"
-// @Success 200 {object} string
-// @Failure 404 {string} string "Restaurant or menu item not found"
-// @Failure 500 {string} string "Error deleting menu item"
+// @Param force query bool false "Delete even if active orders contain the item"
+// @Success 200 {object} Envelope[string]
+// @Failure 404 {object} ErrorEnvelope "Restaurant or menu item not found"
+// @Failure 409 {object} ErrorEnvelope "Item is used by active orders"
+// @Failure 500 {object} ErrorEnvelope "Error deleting menu item"
 // @Router /api/restaurant/{restaurant_id}/menu/{id} [delete]
 func DeleteMenuItem(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 	itemID := c.Params("id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var menuItem models.MenuItem
-	if err := database.DB.Where("id = ? AND restaurant_id = ?", itemID, restaurant.ID).First(&menuItem).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Menu item not found",
-		})
+	if err := db(c).Where("id = ? AND restaurant_id = ?", itemID, restaurant.ID).First(&menuItem).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeMenuItemNotFound, "Menu item not found")
 	}
 
-	if err := database.DB.Delete(&menuItem).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error deleting menu item",
-		})
+	if !c.QueryBool("force") {
+		inUse, err := menuItemsInActiveOrders(db(c), []uint{menuItem.ID})
+		if err != nil {
+			return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error deleting menu item")
+		}
+		if len(inUse) > 0 {
+			return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeMenuItemInUse, "Item is used by active orders")
+		}
 	}
 
+	if err := db(c).Delete(&menuItem).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error deleting menu item")
+	}
+
+	globalMenuCache.invalidate(restaurant.ID)
+	recordAudit(c, restaurant, constants.AuditActionDelete, constants.AuditEntityMenuItem, menuItem.ID, fmt.Sprintf("Deleted menu item %q", menuItem.Name))
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"data":    "Menu item deleted successfully",
@@ -243,42 +455,144 @@ func DeleteMenuItem(c *fiber.Ctx) error {
 	})
 }
 
+// menuItemsInActiveOrders returns which of the given menu items are part of orders still being worked on or scheduled
+func menuItemsInActiveOrders(tx *gorm.DB, ids []uint) ([]uint, error) {
+	var inUse []uint
+	err := tx.Model(&models.OrderItem{}).
+		Joins("JOIN orders ON orders.id = order_items.order_id AND orders.deleted_at IS NULL").
+		Where("order_items.menu_item_id IN ? AND orders.status IN ?", ids, constants.OpenOrderStatuses).
+		Distinct().Order("order_items.menu_item_id").
+		Pluck("order_items.menu_item_id", &inUse).Error
+	return inUse, err
+}
+
 // GetPublicMenuItems godoc
 // @Summary Get public menu items
-// @Description Get all menu items for a restaurant without authentication
+// @Description Get all menu items for a restaurant without authentication, including remaining stock (quantity) and in_stock
 // @Tags Menu
 // @Produce json
 // @Param restaurant_id path string true "Restaurant ID"
-// @Success 200 {array} MenuItem
-// @Failure 404 {string} string "Restaurant not found"
-// @Failure 500 {string} string "Error retrieving menu items"
+// @Param dietary query string false "Comma-separated dietary tags every returned item must have, e.g. vegan,gluten-free"
+// @Param sort query string false "Order by price, name or created_at instead of category display order, optionally with :asc or :desc"
+// @Param If-None-Match header string false "ETag from an earlier response"
+// @Success 200 {object} Envelope[[]PublicMenuItem]
+// @Success 304 {string} string "Menu unchanged since the given ETag"
+// @Failure 400 {object} ErrorEnvelope "Unknown dietary tag or invalid sort"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving menu items"
 // @Router /api/restaurants/{restaurant_id}/menu [get]
 func GetPublicMenuItems(c *fiber.Ctx) error {
 	restaurantID := c.Params("restaurant_id")
 
+	// ?dietary=vegan,gluten-free keeps only items carrying every listed tag
+	var requiredTags utils.StringList
+	if dietary := c.Query("dietary"); dietary != "" {
+		tags, err := utils.NormalizeDietaryTags(strings.Split(dietary, ","))
+		if err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, err.Error())
+		}
+		requiredTags = tags
+	}
+	sortBy, err := parseSort(c, menuItemSortColumns)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid sort: "+err.Error())
+	}
+
+	cacheKey := newMenuCacheKey(requiredTags, sortBy)
+	cached, generation, ok := globalMenuCache.get(parseUint(restaurantID), cacheKey)
+	if ok {
+		return sendWithETag(c, cached)
+	}
+
 	// Check if restaurant exists
 	var restaurant models.Restaurant
-	if err := database.DB.First(&restaurant, restaurantID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+	if err := readDB(c).First(&restaurant, restaurantID).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 	println(restaurant.Name)
 
+	// Cached menus come from the primary: one filled with a lagging replica's read would outlive
+	// the invalidation of the write it missed
+	publicItems, err := loadPublicMenu(db(c), restaurant.ID, requiredTags, sortBy)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving menu items")
+	}
+	globalMenuCache.set(restaurant.ID, cacheKey, generation, publicItems)
+
+	return sendWithETag(c, publicItems)
+}
+
+// defaultNewMenuItemDays is used when MENU_NEW_ITEM_DAYS is not set
+const defaultNewMenuItemDays = 14
+
+// newMenuItemSince returns the time after which added menu items are shown as new
+func newMenuItemSince() time.Time {
+	days := getEnvIntOrDefault("MENU_NEW_ITEM_DAYS", defaultNewMenuItemDays)
+	return time.Now().AddDate(0, 0, -days)
+}
+
+// newPublicMenuItem wraps a menu item for the public menu, flagging it new if it was added after newSince
+func newPublicMenuItem(item models.MenuItem, newSince time.Time) PublicMenuItem {
+	return PublicMenuItem{
+		MenuItem:          item,
+		RemainingQuantity: item.Quantity,
+		InStock:           item.Quantity > 0,
+		IsNew:             item.CreatedAt.After(newSince),
+	}
+}
+
+// loadPublicMenu builds a restaurant's public menu: items carrying every tag in requiredTags,
+// with their stock, ordered by category display order unless sortBy names a column
+func loadPublicMenu(tx *gorm.DB, restaurantID uint, requiredTags utils.StringList, sortBy listSort) ([]PublicMenuItem, error) {
 	var menuItems []models.MenuItem
-	if err := database.DB.Where("restaurant_id = ?", restaurant.ID).Find(&menuItems).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving menu items",
+	if err := sortBy.apply(tx.Where("restaurant_id = ?", restaurantID)).Find(&menuItems).Error; err != nil {
+		return nil, err
+	}
+
+	var categories []models.MenuCategory
+	if err := tx.Where("restaurant_id = ?", restaurantID).Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	displayOrder := make(map[uint]int, len(categories))
+	for _, category := range categories {
+		displayOrder[category.ID] = category.DisplayOrder
+	}
+
+	// Expose remaining stock so the ordering UI can disable sold-out items before checkout
+	newSince := newMenuItemSince()
+	publicItems := make([]PublicMenuItem, 0, len(menuItems))
+	for _, item := range menuItems {
+		if !hasDietaryTags(item, requiredTags) {
+			continue
+		}
+		publicItem := newPublicMenuItem(item, newSince)
+		if item.CategoryID != nil {
+			if order, ok := displayOrder[*item.CategoryID]; ok {
+				publicItem.CategoryDisplayOrder = &order
+			}
+		}
+		publicItems = append(publicItems, publicItem)
+	}
+
+	// Owner-defined category order first, uncategorized items last, unless the client asked for another order
+	if sortBy.Column == "" {
+		sort.SliceStable(publicItems, func(i, j int) bool {
+			a, b := publicItems[i].CategoryDisplayOrder, publicItems[j].CategoryDisplayOrder
+			if a == nil || b == nil {
+				return a != nil && b == nil
+			}
+			return *a < *b
 		})
 	}
+	return publicItems, nil
+}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    menuItems,
-		"error":   nil,
-	})
+// hasDietaryTags reports whether item carries every tag in tags
+func hasDietaryTags(item models.MenuItem, tags utils.StringList) bool {
+	for _, tag := range tags {
+		if !item.DietaryTags.Contains(tag) {
+			return false
+		}
+	}
+	return true
 }
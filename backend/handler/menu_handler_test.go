@@ -0,0 +1,185 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDeleteMenuItemInActiveOrderRequiresForce(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_menudelete", Password: "x", Email: "menudelete@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Menu Delete Restaurant"}
+	database.DB.Create(&restaurant)
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 1}
+	database.DB.Create(&table)
+	active := models.MenuItem{RestaurantID: restaurant.ID, Name: "Active", Price: 500}
+	served := models.MenuItem{RestaurantID: restaurant.ID, Name: "Served", Price: 700}
+	database.DB.Create(&active)
+	database.DB.Create(&served)
+	orders := []models.Order{
+		{RestaurantID: restaurant.ID, TableID: &table.ID, Status: constants.OrderStatusPreparing, TotalAmount: 500, OrderItems: []models.OrderItem{{MenuItemID: active.ID, Quantity: 1}}},
+		{RestaurantID: restaurant.ID, TableID: &table.ID, Status: constants.OrderStatusCompleted, TotalAmount: 700, OrderItems: []models.OrderItem{{MenuItemID: served.ID, Quantity: 1}}},
+	}
+	if err := database.DB.Create(&orders).Error; err != nil {
+		t.Fatalf("creating orders: %v", err)
+	}
+
+	app := fiber.New()
+	app.Delete("/restaurant/:restaurant_id/menu/:id", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return DeleteMenuItem(c)
+	})
+	deleteItem := func(id uint, query string) int {
+		req := httptest.NewRequest("DELETE", fmt.Sprintf("/restaurant/%d/menu/%d%s", restaurant.ID, id, query), nil)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := deleteItem(active.ID, ""); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 for an item in an active order, got %d", status)
+	}
+	if status := deleteItem(served.ID, ""); status != fiber.StatusOK {
+		t.Fatalf("expected items in completed orders to be deletable, got %d", status)
+	}
+	if status := deleteItem(active.ID, "?force=true"); status != fiber.StatusOK {
+		t.Fatalf("expected force=true to delete the item, got %d", status)
+	}
+
+	var item models.OrderItem
+	if err := database.DB.Where("menu_item_id = ?", active.ID).First(&item).Error; err != nil {
+		t.Fatalf("expected the active order to keep its item: %v", err)
+	}
+}
+
+func TestPublicMenuFlagsRecentlyAddedItems(t *testing.T) {
+	testutil.SetupDB(t)
+	t.Setenv("MENU_NEW_ITEM_DAYS", "7")
+
+	user := models.User{Username: "testuser_new_items", Password: "x", Email: "newitems@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "New Items Restaurant"}
+	database.DB.Create(&restaurant)
+	classic := models.MenuItem{RestaurantID: restaurant.ID, Name: "Classic", Price: 900}
+	classic.CreatedAt = time.Now().AddDate(0, 0, -30)
+	special := models.MenuItem{RestaurantID: restaurant.ID, Name: "Special", Price: 1200}
+	special.CreatedAt = time.Now().AddDate(0, 0, -2)
+	database.DB.Create(&classic)
+	database.DB.Create(&special)
+
+	app := fiber.New()
+	app.Get("/restaurants/:restaurant_id/menu", GetPublicMenuItems)
+	resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurants/%d/menu", restaurant.ID), nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	var body struct {
+		Data []map[string]interface{} `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+
+	isNew := make(map[string]interface{})
+	for _, item := range body.Data {
+		if _, ok := item["CreatedAt"].(string); !ok {
+			t.Fatalf("expected CreatedAt on %v, got %v", item["Name"], item["CreatedAt"])
+		}
+		if _, ok := item["created_at"]; ok {
+			t.Fatalf("expected the creation time only once, as CreatedAt, got %v", item)
+		}
+		if _, ok := item["quantity"]; ok || item["remaining_quantity"] != item["Quantity"] {
+			t.Fatalf("expected stock only as Quantity and remaining_quantity, got %v", item)
+		}
+		isNew[item["Name"].(string)] = item["is_new"]
+	}
+	if isNew["Classic"] != false || isNew["Special"] != true {
+		t.Fatalf("expected only the item added 2 days ago to be new, got %v", isNew)
+	}
+}
+
+func TestUpdateMenuItemVersionAndQuantity(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_menuupdate", Password: "x", Email: "menuupdate@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Menu Update Restaurant"}
+	database.DB.Create(&restaurant)
+	item := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 450, Quantity: 12, Version: 3}
+	database.DB.Create(&item)
+
+	app := fiber.New()
+	app.Put("/restaurant/:restaurant_id/menu/:id", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return UpdateMenuItem(c)
+	})
+	update := func(payload string) (int, map[string]interface{}) {
+		req := httptest.NewRequest("PUT", fmt.Sprintf("/restaurant/%d/menu/%d", restaurant.ID, item.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+	stored := func() models.MenuItem {
+		var current models.MenuItem
+		database.DB.First(&current, item.ID)
+		return current
+	}
+
+	// A client that loaded an older version gets the stored item back instead of overwriting it
+	status, body := update(`{"name": "Tomato Soup", "price": "5.00", "version": 2}`)
+	if status != fiber.StatusConflict || body["code"] != constants.ErrCodeVersionConflict {
+		t.Fatalf("expected 409 VERSION_CONFLICT for a stale version, got %d: %v", status, body)
+	}
+	data, _ := body["data"].(map[string]interface{})
+	current, _ := data["current"].(map[string]interface{})
+	if current["Version"] != float64(3) || current["Name"] != "Soup" {
+		t.Fatalf("expected the stored item in data.current, got %v", body["data"])
+	}
+	if got := stored(); got.Name != "Soup" || got.Version != 3 {
+		t.Fatalf("expected a conflicting update to change nothing, got %q version %d", got.Name, got.Version)
+	}
+
+	// Without a quantity the stock is left alone
+	if status, body := update(`{"name": "Tomato Soup", "price": "5.00", "version": 3}`); status != fiber.StatusOK {
+		t.Fatalf("expected 200 for the current version, got %d: %v", status, body)
+	}
+	got := stored()
+	if got.Name != "Tomato Soup" || got.Quantity != 12 || got.Version != 4 {
+		t.Fatalf("expected the rename to keep the stock of 12 and bump the version to 4, got %q with %d at version %d", got.Name, got.Quantity, got.Version)
+	}
+	var movements int64
+	database.DB.Model(&models.StockMovement{}).Where("menu_item_id = ?", item.ID).Count(&movements)
+	if movements != 0 {
+		t.Fatalf("expected no stock movement when the quantity is left out, got %d", movements)
+	}
+
+	if status, body := update(`{"name": "Tomato Soup", "price": "5.00", "quantity": 5}`); status != fiber.StatusOK {
+		t.Fatalf("expected 200 setting the quantity, got %d: %v", status, body)
+	}
+	if got := stored(); got.Quantity != 5 {
+		t.Fatalf("expected the sent quantity to be stored, got %d", got.Quantity)
+	}
+}
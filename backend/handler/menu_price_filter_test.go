@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetMenuItemsFiltersByPriceRange(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_pricefilter", Password: "x", Email: "pricefilter@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Price Filter Restaurant"}
+	database.DB.Create(&restaurant)
+	for _, item := range []models.MenuItem{
+		{RestaurantID: restaurant.ID, Name: "Bread", Price: 300},
+		{RestaurantID: restaurant.ID, Name: "Pasta", Price: 1250},
+		{RestaurantID: restaurant.ID, Name: "Steak", Price: 2800},
+	} {
+		database.DB.Create(&item)
+	}
+
+	app := fiber.New()
+	app.Get("/restaurant/:restaurant_id/menu", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetMenuItems(c)
+	})
+
+	tests := []struct {
+		query  string
+		status int
+		names  []string
+	}{
+		{"", fiber.StatusOK, []string{"Bread", "Pasta", "Steak"}},
+		{"min_price=12.50", fiber.StatusOK, []string{"Pasta", "Steak"}},
+		{"max_price=12.50", fiber.StatusOK, []string{"Bread", "Pasta"}},
+		{"min_price=5&max_price=20", fiber.StatusOK, []string{"Pasta"}},
+		{"min_price=20&max_price=5", fiber.StatusBadRequest, nil},
+		{"min_price=-1", fiber.StatusBadRequest, nil},
+		{"max_price=cheap", fiber.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/menu?%s", restaurant.ID, tt.query), nil)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Fatalf("%q: expected status %d, got %d", tt.query, tt.status, resp.StatusCode)
+		}
+		if tt.status != fiber.StatusOK {
+			continue
+		}
+		var body struct {
+			Data []models.MenuItem `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		names := map[string]bool{}
+		for _, item := range body.Data {
+			names[item.Name] = true
+		}
+		if len(names) != len(tt.names) {
+			t.Fatalf("%q: expected %v, got %v", tt.query, tt.names, names)
+		}
+		for _, name := range tt.names {
+			if !names[name] {
+				t.Fatalf("%q: expected %v, got %v", tt.query, tt.names, names)
+			}
+		}
+	}
+}
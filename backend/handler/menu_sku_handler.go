@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// maxSKULength matches the size of the menu_items.sku column
+const maxSKULength = 100
+
+// errSKUTaken is returned when another menu item of the restaurant already uses the SKU
+var errSKUTaken = errors.New("sku already in use")
+
+// normalizeSKU trims an owner-supplied SKU, returning nil for blank values
+func normalizeSKU(sku string) (*string, error) {
+	sku = strings.TrimSpace(sku)
+	if sku == "" {
+		return nil, nil
+	}
+	if len(sku) > maxSKULength {
+		return nil, fmt.Errorf("sku must be at most %d characters", maxSKULength)
+	}
+	return &sku, nil
+}
+
+// checkSKUAvailable returns errSKUTaken if another item of the restaurant, including a deleted one,
+// already uses sku. Deleted items are included because the unique index still covers them.
+func checkSKUAvailable(tx *gorm.DB, restaurantID uint, sku *string, excludeID uint) error {
+	if sku == nil {
+		return nil
+	}
+	var count int64
+	if err := tx.Unscoped().Model(&models.MenuItem{}).
+		Where("restaurant_id = ? AND sku = ? AND id <> ?", restaurantID, *sku, excludeID).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return errSKUTaken
+	}
+	return nil
+}
+
+// UpsertMenuItemBySKU godoc
+// @Summary Create or update a menu item by SKU
+// @Description Create the menu item with the given SKU, or update the restaurant's existing item with that SKU. Safe to repeat, so external inventory syncs don't need internal IDs. Omitted dietary_tags, allergens, is_featured and featured_order keep their current values.
+// @Tags Menu
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param sku path string true "External SKU"
+// @Param menu_item body MenuItemUpsert true "Menu item data"
+// @Success 200 {object} Envelope[MenuItem] "Existing item updated"
+// @Success 201 {object} Envelope[MenuItem] "New item created"
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 409 {object} ErrorEnvelope "Duplicate value"
+// @Failure 500 {object} ErrorEnvelope "Error saving menu item"
+// @Router /api/restaurant/{restaurant_id}/menu/by-sku/{sku} [put]
+func UpsertMenuItemBySKU(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	rawSKU, err := url.PathUnescape(c.Params("sku"))
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid sku")
+	}
+	sku, err := normalizeSKU(rawSKU)
+	if err != nil || sku == nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid sku")
+	}
+
+	var request MenuItemUpsert
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+
+	var dietaryTags, allergens utils.StringList
+	var station string
+	if request.DietaryTags != nil {
+		if dietaryTags, err = utils.NormalizeDietaryTags(request.DietaryTags); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+		}
+	}
+	if request.Allergens != nil {
+		if allergens, err = utils.NormalizeAllergens(request.Allergens); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+		}
+	}
+
+	if request.Station != nil {
+		if station, err = normalizeStation(*request.Station); err != nil {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+		}
+	}
+
+	var menuItem models.MenuItem
+	created := false
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		// Lock the restaurant so concurrent syncs of the same SKU can't both create it
+		var locked models.Restaurant
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, restaurant.ID).Error; err != nil {
+			return err
+		}
+
+		// A deleted item keeps its SKU, so syncing it again brings it back
+		err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("restaurant_id = ? AND sku = ?", restaurant.ID, *sku).
+			First(&menuItem).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			created = true
+			menuItem = models.MenuItem{RestaurantID: restaurant.ID, SKU: sku}
+		} else if err != nil {
+			return err
+		}
+		menuItem.DeletedAt = gorm.DeletedAt{}
+
+		menuItem.Name = request.Name
+		menuItem.Description = request.Description
+		menuItem.Price = request.Price
+		menuItem.ImageURL = request.ImageURL
+		previousQuantity := menuItem.Quantity
+		menuItem.Quantity = request.Quantity
+		if request.DietaryTags != nil {
+			menuItem.DietaryTags = dietaryTags
+		}
+		if request.Allergens != nil {
+			menuItem.Allergens = allergens
+		}
+		if request.IsFeatured != nil {
+			menuItem.IsFeatured = *request.IsFeatured
+		}
+		if request.FeaturedOrder != nil {
+			menuItem.FeaturedOrder = *request.FeaturedOrder
+		}
+		if request.Station != nil {
+			menuItem.Station = station
+		}
+
+		category, err := resolveMenuCategory(tx, restaurant.ID, request.CategoryID, request.Category)
+		if err != nil {
+			return err
+		}
+		applyMenuCategory(&menuItem, category)
+		if menuItem.IsFeatured {
+			if err := checkFeaturedLimit(tx, restaurant.ID, menuItem.ID); err != nil {
+				return err
+			}
+		}
+		menuItem.Version++
+		if created {
+			if err := tx.Create(&menuItem).Error; err != nil {
+				return err
+			}
+			return recordStockMovement(tx, &menuItem, constants.StockMovementInitial, menuItem.Quantity, nil, nil, "SKU sync")
+		}
+		if err := tx.Unscoped().Save(&menuItem).Error; err != nil {
+			return err
+		}
+		return recordStockMovement(tx, &menuItem, constants.StockMovementManual, menuItem.Quantity-previousQuantity, nil, nil, "SKU sync")
+	}); err != nil {
+		if errors.Is(err, errMenuCategoryNotFound) {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeCategoryNotFound, "Menu category not found")
+		}
+		if errors.Is(err, errFeaturedLimitReached) {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeFeaturedLimitReached, fmt.Sprintf("A restaurant can feature at most %d menu items", maxFeaturedItems))
+		}
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error saving menu item"
+		}
+		return utils.SendError(c, status, code, message)
+	}
+
+	globalMenuCache.invalidate(restaurant.ID)
+	if created {
+		recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityMenuItem, menuItem.ID, fmt.Sprintf("Created menu item %q from SKU %s", menuItem.Name, *sku))
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+			"success": true,
+			"data":    menuItem,
+			"error":   nil,
+		})
+	}
+
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntityMenuItem, menuItem.ID, fmt.Sprintf("Updated menu item %q from SKU %s", menuItem.Name, *sku))
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    menuItem,
+		"error":   nil,
+	})
+}
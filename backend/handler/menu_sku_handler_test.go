@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSKU(t *testing.T) {
+	sku, err := normalizeSKU("  PIZ-001 ")
+	if err != nil || sku == nil || *sku != "PIZ-001" {
+		t.Fatalf("normalizeSKU trimmed = %v, %v; want PIZ-001", sku, err)
+	}
+
+	if sku, err := normalizeSKU("   "); err != nil || sku != nil {
+		t.Fatalf("normalizeSKU blank = %v, %v; want nil", sku, err)
+	}
+
+	if _, err := normalizeSKU(strings.Repeat("x", maxSKULength+1)); err == nil {
+		t.Fatal("expected overlong SKU to be rejected")
+	}
+}
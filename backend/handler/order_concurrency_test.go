@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreatePublicOrderPreventsOverselling(t *testing.T) {
+	testutil.RequirePostgres(t)
+
+	const stock = 3
+	const attempts = 10
+
+	// Seed an owner, restaurant, table and a menu item with limited stock
+	user := models.User{Username: "testuser_concurrency", Password: "x", Email: "testconcurrency@example.com"}
+	assert.NoError(t, database.DB.Create(&user).Error)
+
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Concurrency Test Restaurant"}
+	assert.NoError(t, database.DB.Create(&restaurant).Error)
+
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 1}
+	assert.NoError(t, database.DB.Create(&table).Error)
+
+	menuItem := models.MenuItem{RestaurantID: restaurant.ID, Name: "Limited Dish", Price: 1000, Quantity: stock}
+	assert.NoError(t, database.DB.Create(&menuItem).Error)
+
+	app := fiber.New()
+	app.Post("/api/restaurants/:restaurant_id/order", CreatePublicOrder)
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"table_id":      table.ID,
+		"customer_name": "Concurrent Customer",
+		"order_items": []map[string]interface{}{
+			{"menu_item_id": menuItem.ID, "quantity": 1},
+		},
+	})
+	url := fmt.Sprintf("/api/restaurants/%d/order", restaurant.ID)
+
+	// Fire all orders at once so they race for the same row
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	statuses := map[int]int{}
+	errorMessages := map[string]int{}
+	start := make(chan struct{})
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+
+			req := httptest.NewRequest("POST", url, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+
+			var result struct {
+				Success bool        `json:"success"`
+				Error   interface{} `json:"error"`
+			}
+			json.NewDecoder(resp.Body).Decode(&result)
+
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[resp.StatusCode]++
+			if message, ok := result.Error.(string); ok {
+				errorMessages[message]++
+			}
+		}()
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, stock, statuses[fiber.StatusCreated], "exactly the available stock should be sold")
+	assert.Equal(t, attempts-stock, statuses[fiber.StatusBadRequest], "the remaining orders should be rejected")
+	assert.Equal(t, attempts-stock, errorMessages["Insufficient stock"])
+
+	var reloaded models.MenuItem
+	assert.NoError(t, database.DB.First(&reloaded, menuItem.ID).Error)
+	assert.Equal(t, 0, reloaded.Quantity, "stock must never go below zero")
+}
+
+func TestCreatePublicOrderOppositeItemOrderDoesNotDeadlock(t *testing.T) {
+	testutil.RequirePostgres(t)
+
+	const pairs = 10
+
+	user := models.User{Username: "testuser_lockorder", Password: "x", Email: "testlockorder@example.com"}
+	assert.NoError(t, database.DB.Create(&user).Error)
+
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Lock Order Test Restaurant"}
+	assert.NoError(t, database.DB.Create(&restaurant).Error)
+
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 1}
+	assert.NoError(t, database.DB.Create(&table).Error)
+
+	first := models.MenuItem{RestaurantID: restaurant.ID, Name: "First Dish", Price: 500, Quantity: pairs * 2}
+	second := models.MenuItem{RestaurantID: restaurant.ID, Name: "Second Dish", Price: 700, Quantity: pairs * 2}
+	assert.NoError(t, database.DB.Create(&first).Error)
+	assert.NoError(t, database.DB.Create(&second).Error)
+
+	app := fiber.New()
+	app.Post("/api/restaurants/:restaurant_id/order", CreatePublicOrder)
+	url := fmt.Sprintf("/api/restaurants/%d/order", restaurant.ID)
+
+	orderBody := func(ids ...uint) []byte {
+		items := make([]map[string]interface{}, 0, len(ids))
+		for _, id := range ids {
+			items = append(items, map[string]interface{}{"menu_item_id": id, "quantity": 1})
+		}
+		body, _ := json.Marshal(map[string]interface{}{
+			"table_id":      table.ID,
+			"customer_name": "Lock Order Customer",
+			"order_items":   items,
+		})
+		return body
+	}
+	// Half the orders list the items one way round, half the other
+	bodies := [][]byte{orderBody(first.ID, second.ID), orderBody(second.ID, first.ID)}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	statuses := map[int]int{}
+	start := make(chan struct{})
+
+	for i := 0; i < pairs*2; i++ {
+		wg.Add(1)
+		go func(body []byte) {
+			defer wg.Done()
+			<-start
+
+			req := httptest.NewRequest("POST", url, bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Errorf("request failed: %v", err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			statuses[resp.StatusCode]++
+		}(bodies[i%2])
+	}
+	close(start)
+	wg.Wait()
+
+	assert.Equal(t, pairs*2, statuses[fiber.StatusCreated], "every order should succeed without deadlock errors")
+
+	var reloaded models.MenuItem
+	assert.NoError(t, database.DB.First(&reloaded, first.ID).Error)
+	assert.Equal(t, 0, reloaded.Quantity)
+	assert.NoError(t, database.DB.First(&reloaded, second.ID).Error)
+	assert.Equal(t, 0, reloaded.Quantity)
+}
@@ -1,9 +1,17 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
+	"order-system/constants"
 	"order-system/database"
 	"order-system/models"
 	"order-system/utils"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
@@ -19,105 +27,117 @@ import (
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param order body Order true "Order data"
-// @Success 201 {object} Order
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "Restaurant, table, or menu item not found"
-// @Failure 500 {string} string "Error creating order"
+// @Success 201 {object} Envelope[Order]
+// @Failure 400 {object} ErrorEnvelope "Invalid input, unknown fields listed in data.unknown_fields, or insufficient stock with data.shortages listing requested vs available per item"
+// @Failure 404 {object} ErrorEnvelope "Restaurant, table, or menu item not found"
+// @Failure 500 {object} ErrorEnvelope "Error creating order"
 // @Router /api/restaurant/{restaurant_id}/order [post]
 func CreateOrder(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var request struct {
-		TableID      uint   `json:"table_id"`
-		CustomerName string `json:"customer_name"`
-		OrderItems   []struct {
-			MenuItemID          uint   `json:"menu_item_id"`
-			Quantity            int    `json:"quantity"`
-			SpecialInstructions string `json:"special_instructions"`
-		} `json:"order_items"`
+		orderContact
+		TableID      uint               `json:"table_id"`
+		CustomerName string             `json:"customer_name"`
+		Discount     utils.Money        `json:"discount"`
+		Tip          utils.Money        `json:"tip"`
+		ScheduledFor *time.Time         `json:"scheduled_for"`
+		OrderItems   []orderItemRequest `json:"order_items"`
 	}
 
-	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+	if err := utils.BodyParserStrict(c, &request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if err := request.orderContact.validate(); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+	}
+	orderItems, err := validateOrderItems(request.OrderItems)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+	}
+	status, err := orderStartStatus(request.ScheduledFor)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
 	}
 
-	// Verify table belongs to restaurant
-	var table models.Table
-	if err := database.DB.Where("id = ? AND restaurant_id = ?", request.TableID, restaurant.ID).First(&table).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Table not found",
-		})
+	tableID, apiErr := orderTableID(c, restaurant.ID, request.OrderType, request.TableID)
+	if apiErr != nil {
+		return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
 	}
 
-	// Calculate total amount
-	var totalAmount float64
-	var orderItems []models.OrderItem
+	order := models.Order{
+		RestaurantID:    restaurant.ID,
+		TableID:         tableID,
+		CustomerName:    request.CustomerName,
+		CustomerPhone:   request.CustomerPhone,
+		OrderType:       request.OrderType,
+		DeliveryAddress: request.DeliveryAddress,
+		Status:          status,
+		ScheduledFor:    request.ScheduledFor,
+		Discount:        request.Discount,
+		Tip:             request.Tip,
+	}
 
-	for _, item := range request.OrderItems {
-		var menuItem models.MenuItem
-		if err := database.DB.Where("id = ? AND restaurant_id = ?", item.MenuItemID, restaurant.ID).First(&menuItem).Error; err != nil {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"success": false,
-				"data":    nil,
-				"error":   "Menu item not found",
-			})
+	// Take the stock, create and reload in one transaction so an order is never left behind
+	// without its stock or its event
+	var shortages []StockShortage
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		reservation, err := reserveOrderStock(tx, restaurant.ID, orderItems)
+		if err != nil {
+			shortages = reservation.shortages
+			return err
 		}
 
-		quantity := item.Quantity
-		if quantity <= 0 {
-			quantity = 1
+		order.TotalAmount, err = orderTotal(reservation.subtotal, request.Discount, request.Tip)
+		if err != nil {
+			return err
 		}
+		order.OrderItems = reservation.orderItems
 
-		totalAmount += menuItem.Price * float64(quantity)
+		ref, err := database.NextOrderRef(tx, restaurant.ID)
+		if err != nil {
+			return err
+		}
+		order.OrderRef = ref
 
-		orderItems = append(orderItems, models.OrderItem{
-			MenuItemID:          item.MenuItemID,
-			Quantity:            quantity,
-			SpecialInstructions: item.SpecialInstructions,
-		})
-	}
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+		if err := reservation.record(tx, order.ID); err != nil {
+			return err
+		}
 
-	order := models.Order{
-		TableID:      request.TableID,
-		CustomerName: request.CustomerName,
-		Status:       "pending",
-		TotalAmount:  totalAmount,
-		OrderItems:   orderItems,
-	}
-
-	if err := database.DB.Create(&order).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error creating order",
-		})
+		// Load order with items
+		return tx.Preload("Table").Preload("OrderItems").First(&order, order.ID).Error
+	}); err != nil {
+		if len(shortages) > 0 {
+			return utils.SendErrorWithData(c, fiber.StatusBadRequest, constants.ErrCodeInsufficientStock, "Insufficient stock", fiber.Map{"shortages": shortages})
+		}
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendErrorWithData(c, apiErr.status, apiErr.code, apiErr.message, apiErr.data)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error creating order")
 	}
 
-	// Load order with items
-	database.DB.Preload("OrderItems").First(&order, order.ID)
+	// The order took stock, which the public menu shows
+	globalMenuCache.invalidate(restaurant.ID)
 
+	// Publish only after commit; publish never blocks the request
 	orderResponse := buildOrderResponse(order, restaurant)
 	globalOrderHub.publish("order_created", orderResponse)
 
+	recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityOrder, order.ID, fmt.Sprintf("Created order %s %s", order.OrderRef, orderDestination(order)))
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"success": true,
-		"data":    order,
+		"data":    orderResponse.Order,
 		"error":   nil,
 	})
 }
@@ -129,50 +149,113 @@ func CreateOrder(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
-// @Success 200 {array} Order
-// @Failure 404 {string} string "Restaurant not found"
-// @Failure 500 {string} string "Error retrieving orders"
+// @Param include_items query bool false "Include each order's items (default true); item_count and subtotal are always set"
+// @Param sort query string false "Order by created_at or total_amount, optionally with :asc or :desc, e.g. created_at:desc"
+// @Success 200 {object} Envelope[[]Order]
+// @Failure 400 {object} ErrorEnvelope "Invalid sort"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving orders"
 // @Router /api/restaurant/{restaurant_id}/order [get]
 func GetOrders(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	sortBy, err := parseSort(c, orderSortColumns)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid sort: "+err.Error())
 	}
 
-	// Get all table IDs for the restaurant
-	var tables []models.Table
-	database.DB.Where("restaurant_id = ?", restaurant.ID).Find(&tables)
-
-	var tableIDs []uint
-	for _, table := range tables {
-		tableIDs = append(tableIDs, table.ID)
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var orders []models.Order
-	if len(tableIDs) > 0 {
-		if err := database.DB.Where("table_id IN ?", tableIDs).Preload("OrderItems").Find(&orders).Error; err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"success": false,
-				"data":    nil,
-				"error":   "Error retrieving orders",
-			})
+	if err := sortBy.apply(restaurantOrders(db(c), restaurant.ID)).Preload("Table").Preload("OrderItems").Find(&orders).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving orders")
+	}
+
+	includeItems := c.QueryBool("include_items", true)
+	entries := make([]Order, 0, len(orders))
+	for _, order := range orders {
+		entry := toHandlerOrder(order)
+		if !includeItems {
+			entry.OrderItems = nil
 		}
+		entries = append(entries, entry)
 	}
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data":    orders,
+		"data":    entries,
 		"error":   nil,
 	})
 }
 
+// restaurantOrders scopes an orders query to the restaurant, leaving out orders at deleted tables.
+// Takeaway and delivery orders without a table are included.
+func restaurantOrders(query *gorm.DB, restaurantID uint) *gorm.DB {
+	return query.Where("orders.restaurant_id = ? AND (orders.table_id IS NULL OR orders.table_id IN (SELECT id FROM tables WHERE tables.deleted_at IS NULL))", restaurantID)
+}
+
+// orderItemCount returns the total quantity of items, e.g. 3 for two burgers and a drink
+func orderItemCount(items []models.OrderItem) int {
+	count := 0
+	for _, item := range items {
+		count += item.Quantity
+	}
+	return count
+}
+
+// orderItemLineTotal prices an order item at the unit price snapshotted when it was ordered
+func orderItemLineTotal(item models.OrderItem) utils.Money {
+	return item.UnitPrice * utils.Money(item.Quantity)
+}
+
+// orderSubtotal returns the order's items total before discount and tip, as charged when it was placed
+func orderSubtotal(order models.Order) utils.Money {
+	return order.TotalAmount + order.Discount - order.Tip
+}
+
+// GetActiveOrderCount godoc
+// @Summary Get active order count
+// @Description Get the number of active orders for a restaurant without loading order data
+// @Tags Order
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Success 200 {object} Envelope[ActiveOrderCount]
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error counting orders"
+// @Router /api/restaurant/{restaurant_id}/orders/active-count [get]
+func GetActiveOrderCount(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	// Single COUNT query, no order rows are loaded
+	var count int64
+	if err := restaurantOrders(db(c).Model(&models.Order{}), restaurant.ID).
+		Where("orders.status IN ?", constants.ActiveOrderStatuses).
+		Count(&count).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error counting orders")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": ActiveOrderCount{
+			ActiveCount: count,
+		},
+		"error": nil,
+	})
+}
+
 // GetOrder godoc
 // @Summary Get order by ID
 // @Description Get a single order by ID
@@ -181,45 +264,29 @@ func GetOrders(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param id path string true "Order ID"
-// @Success 200 {object} Order
-// @Failure 404 {string} string "Restaurant or order not found"
-// @Failure 500 {string} string "Error retrieving order"
+// @Success 200 {object} Envelope[Order]
+// @Failure 404 {object} ErrorEnvelope "Restaurant or order not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving order"
 // @Router /api/restaurant/{restaurant_id}/order/{id} [get]
 func GetOrder(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 	orderID := c.Params("id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
-	}
-
-	// Get all table IDs for the restaurant
-	var tables []models.Table
-	database.DB.Where("restaurant_id = ?", restaurant.ID).Find(&tables)
-
-	var tableIDs []uint
-	for _, table := range tables {
-		tableIDs = append(tableIDs, table.ID)
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var order models.Order
-	if err := database.DB.Where("id = ? AND table_id IN ?", orderID, tableIDs).Preload("OrderItems").First(&order).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Order not found",
-		})
+	if err := restaurantOrders(db(c), restaurant.ID).Where("orders.id = ?", orderID).Preload("Table").Preload("OrderItems").First(&order).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeOrderNotFound, "Order not found")
 	}
 
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data":    order,
+		"data":    toHandlerOrder(order),
 		"error":   nil,
 	})
 }
@@ -234,41 +301,26 @@ func GetOrder(c *fiber.Ctx) error {
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param id path string true "Order ID"
 // @Param status body OrderStatusUpdate true "Order status"
-// @Success 200 {object} Order
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "Restaurant or order not found"
-// @Failure 500 {string} string "Error updating order"
+// @Success 200 {object} Envelope[Order]
+// @Failure 400 {object} ErrorEnvelope "Invalid input or unknown status"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or order not found"
+// @Failure 409 {object} ErrorEnvelope "The order can't move to that status from its current one"
+// @Failure 500 {object} ErrorEnvelope "Error updating order"
 // @Router /api/restaurant/{restaurant_id}/order/{id} [patch]
 func UpdateOrderStatus(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 	orderID := c.Params("id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
-	}
-
-	// Get all table IDs for the restaurant
-	var tables []models.Table
-	database.DB.Where("restaurant_id = ?", restaurant.ID).Find(&tables)
-
-	var tableIDs []uint
-	for _, table := range tables {
-		tableIDs = append(tableIDs, table.ID)
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var order models.Order
-	if err := database.DB.Where("id = ? AND table_id IN ?", orderID, tableIDs).First(&order).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Order not found",
-		})
+	if err := restaurantOrders(db(c), restaurant.ID).Where("orders.id = ?", orderID).First(&order).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeOrderNotFound, "Order not found")
 	}
 
 	var request struct {
@@ -276,32 +328,101 @@ func UpdateOrderStatus(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
 	}
 
 	// Map the simplified frontend status to internal status value
 	internalStatus := utils.MapFrontendStatusToInternal(request.Status)
+	if !utils.IsValidOrderStatus(internalStatus) {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid status")
+	}
+	if !utils.CanTransitionOrderStatus(order.OrderType, order.Status, internalStatus) {
+		return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeInvalidStatusTransition,
+			fmt.Sprintf("Cannot change a %s order from %s to %s", order.OrderType, order.Status, internalStatus))
+	}
 	order.Status = internalStatus
 
-	if err := database.DB.Save(&order).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error updating order",
-		})
+	if err := db(c).Save(&order).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error updating order")
+	}
+
+	db(c).Preload("Table").Preload("OrderItems").First(&order, order.ID)
+	orderResponse := buildOrderResponse(order, restaurant)
+	globalOrderHub.publish("order_updated", orderResponse)
+
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntityOrder, order.ID, fmt.Sprintf("Changed order status to %s", order.Status))
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    orderResponse.Order,
+		"error":   nil,
+	})
+}
+
+// TransferOrderTable godoc
+// @Summary Move an order to another table
+// @Description Move a dine-in order to a different table of the same restaurant, e.g. when a party changes tables mid-meal
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Order ID"
+// @Param table body OrderTableTransfer true "Target table"
+// @Success 200 {object} Envelope[Order]
+// @Failure 400 {object} ErrorEnvelope "Invalid input, the order isn't a dine-in order, or it is completed or cancelled"
+// @Failure 404 {object} ErrorEnvelope "Restaurant, order or table not found"
+// @Failure 500 {object} ErrorEnvelope "Error updating order"
+// @Router /api/restaurant/{restaurant_id}/order/{id}/table [patch]
+func TransferOrderTable(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+	orderID := c.Params("id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request OrderTableTransfer
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if request.TableID == 0 {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid input")
+	}
+
+	var order models.Order
+	if err := db(c).Where("id = ? AND restaurant_id = ?", orderID, restaurant.ID).First(&order).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeOrderNotFound, "Order not found")
+	}
+	if order.OrderType != constants.OrderTypeDineIn {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Only dine_in orders are placed at a table")
+	}
+	if order.Status == constants.OrderStatusCompleted || order.Status == constants.OrderStatusCancelled {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeOrderNotOpen, fmt.Sprintf("The order is %s and can't be moved", order.Status))
+	}
+
+	// The target table must belong to the same restaurant
+	var table models.Table
+	if err := db(c).Where("id = ? AND restaurant_id = ?", request.TableID, restaurant.ID).First(&table).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeTableNotFound, "Table not found")
+	}
+
+	if err := db(c).Model(&order).Update("table_id", table.ID).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error updating order")
 	}
 
-	database.DB.Preload("OrderItems").First(&order, order.ID)
+	db(c).Preload("Table").Preload("OrderItems").First(&order, order.ID)
 	orderResponse := buildOrderResponse(order, restaurant)
 	globalOrderHub.publish("order_updated", orderResponse)
 
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntityOrder, order.ID, fmt.Sprintf("Moved order to table %d", table.TableNumber))
+
 	return c.JSON(fiber.Map{
 		"success": true,
-		"data":    order,
+		"data":    orderResponse.Order,
 		"error":   nil,
 	})
 }
@@ -314,53 +435,35 @@ func UpdateOrderStatus(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param id path string true "Order ID"
-// @Success 200 {object} string
-// @Failure 404 {string} string "Restaurant or order not found"
-// @Failure 500 {string} string "Error deleting order"
+// @Success 200 {object} Envelope[string]
+// @Failure 404 {object} ErrorEnvelope "Restaurant or order not found"
+// @Failure 500 {object} ErrorEnvelope "Error deleting order"
 // @Router /api/restaurant/{restaurant_id}/order/{id} [delete]
 func DeleteOrder(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 	orderID := c.Params("id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
-	}
-
-	// Get all table IDs for the restaurant
-	var tables []models.Table
-	database.DB.Where("restaurant_id = ?", restaurant.ID).Find(&tables)
-
-	var tableIDs []uint
-	for _, table := range tables {
-		tableIDs = append(tableIDs, table.ID)
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var order models.Order
-	if err := database.DB.Where("id = ? AND table_id IN ?", orderID, tableIDs).First(&order).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Order not found",
-		})
+	if err := restaurantOrders(db(c), restaurant.ID).Where("orders.id = ?", orderID).First(&order).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeOrderNotFound, "Order not found")
 	}
 
 	// Delete order items first
-	database.DB.Where("order_id = ?", order.ID).Delete(&models.OrderItem{})
+	db(c).Where("order_id = ?", order.ID).Delete(&models.OrderItem{})
 
-	if err := database.DB.Delete(&order).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error deleting order",
-		})
+	if err := db(c).Delete(&order).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error deleting order")
 	}
 
+	recordAudit(c, restaurant, constants.AuditActionDelete, constants.AuditEntityOrder, order.ID, "Deleted order")
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"data":    "Order deleted successfully",
@@ -376,160 +479,536 @@ func DeleteOrder(c *fiber.Ctx) error {
 // @Produce json
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param order body Order true "Order data"
-// @Success 201 {object} Order
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "Restaurant, table, or menu item not found"
-// @Failure 500 {string} string "Error creating order"
+// @Success 201 {object} Envelope[Order]
+// @Failure 400 {object} ErrorEnvelope "Invalid input, unknown fields listed in data.unknown_fields, or insufficient stock with data.shortages listing requested vs available per item"
+// @Failure 404 {object} ErrorEnvelope "Restaurant, table, or menu item not found"
+// @Failure 429 {object} ErrorEnvelope "Kitchen at capacity, with data.estimated_wait_minutes"
+// @Failure 500 {object} ErrorEnvelope "Error creating order"
 // @Router /api/restaurants/{restaurant_id}/order [post]
 func CreatePublicOrder(c *fiber.Ctx) error {
 	restaurantID := c.Params("restaurant_id")
 
 	// Verify restaurant exists
 	var restaurant models.Restaurant
-	if err := database.DB.First(&restaurant, restaurantID).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+	if err := db(c).First(&restaurant, restaurantID).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var request struct {
-		TableID      uint   `json:"table_id"`
-		CustomerName string `json:"customer_name"`
-		OrderItems   []struct {
-			MenuItemID          uint   `json:"menu_item_id"`
-			Quantity            int    `json:"quantity"`
-			SpecialInstructions string `json:"special_instructions"`
-		} `json:"order_items"`
+		orderContact
+		TableID      uint               `json:"table_id"`
+		CustomerName string             `json:"customer_name"`
+		Tip          utils.Money        `json:"tip"`
+		ScheduledFor *time.Time         `json:"scheduled_for"`
+		OrderItems   []orderItemRequest `json:"order_items"`
 	}
 
-	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+	if err := utils.BodyParserStrict(c, &request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if err := request.orderContact.validate(); err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+	}
+	orderItems, err := validateOrderItems(request.OrderItems)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+	}
+	status, err := orderStartStatus(request.ScheduledFor)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
 	}
 
-	// Verify table belongs to restaurant
-	var table models.Table
-	if err := database.DB.Where("id = ? AND restaurant_id = ?", request.TableID, restaurant.ID).First(&table).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Table not found",
-		})
+	tableID, apiErr := orderTableID(c, restaurant.ID, request.OrderType, request.TableID)
+	if apiErr != nil {
+		return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
 	}
 
-	var createdOrder models.Order
-	if err := database.DB.Transaction(func(tx *gorm.DB) error {
-		var totalAmount float64
-		var orderItems []models.OrderItem
+	createdOrder, shortages, err := placePublicOrder(c, &restaurant, models.Order{
+		TableID:         tableID,
+		CustomerName:    request.CustomerName,
+		CustomerPhone:   request.CustomerPhone,
+		OrderType:       request.OrderType,
+		DeliveryAddress: request.DeliveryAddress,
+		Status:          status,
+		ScheduledFor:    request.ScheduledFor,
+		Tip:             request.Tip,
+	}, orderItems)
+	if len(shortages) > 0 {
+		return utils.SendErrorWithData(c, fiber.StatusBadRequest, constants.ErrCodeInsufficientStock, "Insufficient stock", fiber.Map{"shortages": shortages})
+	}
+	if err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendErrorWithData(c, apiErr.status, apiErr.code, apiErr.message, apiErr.data)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error creating order")
+	}
 
-		for _, item := range request.OrderItems {
-			var menuItem models.MenuItem
-			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
-				Where("id = ? AND restaurant_id = ?", item.MenuItemID, restaurant.ID).
-				First(&menuItem).Error; err != nil {
-				return fiber.NewError(fiber.StatusNotFound, "Menu item not found")
-			}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    toHandlerOrder(createdOrder),
+		"error":   nil,
+	})
+}
 
-			quantity := item.Quantity
-			if quantity <= 0 {
-				quantity = 1
-			}
+// placePublicOrder creates a customer's pending order from the contact details, table and tip in
+// order and the given items, taking their stock, and announces it. Items short on stock come back
+// as shortages instead; the error is an *apiError for other client errors.
+func placePublicOrder(c *fiber.Ctx, restaurant *models.Restaurant, order models.Order, items []orderItemRequest) (models.Order, []StockShortage, error) {
+	items = mergeOrderItems(items)
 
-			if menuItem.Quantity < quantity {
-				return fiber.NewError(fiber.StatusBadRequest, "Insufficient quantity for item: "+menuItem.Name)
-			}
+	requested, menuItemIDs := requestedQuantities(items)
 
-			totalAmount += menuItem.Price * float64(quantity)
+	// Read-only availability pass: report every short item at once, without taking locks
+	var available []models.MenuItem
+	if err := db(c).Where("id IN ? AND restaurant_id = ?", menuItemIDs, restaurant.ID).Find(&available).Error; err != nil {
+		return models.Order{}, nil, err
+	}
+	if len(available) != len(menuItemIDs) {
+		return models.Order{}, nil, newAPIError(fiber.StatusNotFound, constants.ErrCodeMenuItemNotFound, "Menu item not found")
+	}
+	if shortages := findStockShortages(available, requested); len(shortages) > 0 {
+		return models.Order{}, shortages, nil
+	}
+	if err := checkMinOrderAmount(c, restaurant.ID, order.OrderType, available, requested); err != nil {
+		return models.Order{}, nil, err
+	}
+	if order.Status != constants.OrderStatusScheduled {
+		if err := checkKitchenCapacity(c, restaurant.ID); err != nil {
+			return models.Order{}, nil, err
+		}
+	}
 
-			menuItem.Quantity -= quantity
-			if menuItem.Quantity < 0 {
-				menuItem.Quantity = 0
-			}
+	var shortages []StockShortage
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		reservation, err := reserveOrderStock(tx, restaurant.ID, items)
+		if err != nil {
+			shortages = reservation.shortages
+			return err
+		}
 
-			if err := tx.Save(&menuItem).Error; err != nil {
-				return err
-			}
+		// Customers can add a tip; discounts are only applied by staff through CreateOrder
+		totalAmount, err := orderTotal(reservation.subtotal, 0, order.Tip)
+		if err != nil {
+			return err
+		}
 
-			orderItems = append(orderItems, models.OrderItem{
-				MenuItemID:          item.MenuItemID,
-				Quantity:            quantity,
-				SpecialInstructions: item.SpecialInstructions,
-			})
+		// Taken last, the counter row stays locked only while the order is written
+		ref, err := database.NextOrderRef(tx, restaurant.ID)
+		if err != nil {
+			return err
 		}
 
-		createdOrder = models.Order{
-			TableID:      request.TableID,
-			CustomerName: request.CustomerName,
-			Status:       "pending",
-			TotalAmount:  totalAmount,
-			OrderItems:   orderItems,
+		order.OrderRef = ref
+		order.RestaurantID = restaurant.ID
+		if order.Status == "" {
+			order.Status = constants.OrderStatusPending
 		}
+		order.TotalAmount = totalAmount
+		order.OrderItems = reservation.orderItems
 
-		if err := tx.Create(&createdOrder).Error; err != nil {
+		if err := tx.Create(&order).Error; err != nil {
+			return err
+		}
+		if err := reservation.record(tx, order.ID); err != nil {
 			return err
 		}
 
-		return tx.Preload("OrderItems").First(&createdOrder, createdOrder.ID).Error
+		return tx.Preload("Table").Preload("OrderItems").First(&order, order.ID).Error
 	}); err != nil {
-		if fiberErr, ok := err.(*fiber.Error); ok {
-			return c.Status(fiberErr.Code).JSON(fiber.Map{
-				"success": false,
-				"data":    nil,
-				"error":   fiberErr.Message,
-			})
-		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error creating order",
-		})
+		return models.Order{}, shortages, err
 	}
 
-	orderResponse := buildOrderResponse(createdOrder, &restaurant)
+	// The order took stock, which the public menu shows
+	globalMenuCache.invalidate(restaurant.ID)
+
+	orderResponse := buildOrderResponse(order, restaurant)
 	globalOrderHub.publish("order_created", orderResponse)
 
-	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
-		"success": true,
-		"data":    createdOrder,
-		"error":   nil,
-	})
+	return order, nil, nil
+}
+
+// requestedQuantities totals the requested quantity per menu item and lists the items in
+// ascending ID order, the order they are locked in so concurrent orders touching the same
+// items in a different sequence can't deadlock
+func requestedQuantities(items []orderItemRequest) (map[uint]int, []uint) {
+	requested := make(map[uint]int, len(items))
+	menuItemIDs := make([]uint, 0, len(items))
+	for _, item := range items {
+		if _, ok := requested[item.MenuItemID]; !ok {
+			menuItemIDs = append(menuItemIDs, item.MenuItemID)
+		}
+		requested[item.MenuItemID] += item.quantity()
+	}
+	sort.Slice(menuItemIDs, func(i, j int) bool { return menuItemIDs[i] < menuItemIDs[j] })
+	return requested, menuItemIDs
+}
+
+// stockReservation is the stock an order takes from its locked menu items
+type stockReservation struct {
+	orderItems  []models.OrderItem
+	subtotal    utils.Money
+	requested   map[uint]int
+	menuItemIDs []uint
+	lockedItems map[uint]*models.MenuItem
+	shortages   []StockShortage
+}
+
+// reserveOrderStock locks the menu items of an order in tx, takes their stock and builds the
+// order lines. Items short on stock are listed in the reservation's shortages alongside an
+// insufficient stock error.
+func reserveOrderStock(tx *gorm.DB, restaurantID uint, items []orderItemRequest) (*stockReservation, error) {
+	requested, menuItemIDs := requestedQuantities(items)
+	reservation := &stockReservation{
+		requested:   requested,
+		menuItemIDs: menuItemIDs,
+		lockedItems: make(map[uint]*models.MenuItem, len(menuItemIDs)),
+	}
+
+	locked := make([]models.MenuItem, len(menuItemIDs))
+	for i, id := range menuItemIDs {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND restaurant_id = ?", id, restaurantID).
+			First(&locked[i]).Error; err != nil {
+			return reservation, newAPIError(fiber.StatusNotFound, constants.ErrCodeMenuItemNotFound, "Menu item not found")
+		}
+		reservation.lockedItems[id] = &locked[i]
+	}
+
+	// Stock may have moved since any earlier read; check under lock
+	if reservation.shortages = findStockShortages(locked, requested); len(reservation.shortages) > 0 {
+		return reservation, newAPIError(fiber.StatusBadRequest, constants.ErrCodeInsufficientStock, "Insufficient stock")
+	}
+
+	for _, item := range items {
+		menuItem := reservation.lockedItems[item.MenuItemID]
+		quantity := item.quantity()
+
+		menuItem.Quantity -= quantity
+
+		orderItem := models.OrderItem{
+			MenuItemID:          item.MenuItemID,
+			ItemName:            menuItem.Name,
+			UnitPrice:           menuItem.Price,
+			Quantity:            quantity,
+			SpecialInstructions: item.SpecialInstructions,
+			Status:              constants.OrderItemStatusPending,
+		}
+		reservation.subtotal += orderItemLineTotal(orderItem)
+		reservation.orderItems = append(reservation.orderItems, orderItem)
+	}
+
+	// The rows are already locked, so the write order doesn't matter for deadlocks
+	for _, id := range menuItemIDs {
+		if err := tx.Model(reservation.lockedItems[id]).Updates(map[string]interface{}{
+			"quantity": reservation.lockedItems[id].Quantity,
+			"version":  gorm.Expr("version + 1"),
+		}).Error; err != nil {
+			return reservation, err
+		}
+	}
+	return reservation, nil
+}
+
+// record writes the stock movements of the reservation once its order has an ID
+func (r *stockReservation) record(tx *gorm.DB, orderID uint) error {
+	for _, id := range r.menuItemIDs {
+		if err := recordStockMovement(tx, r.lockedItems[id], constants.StockMovementOrder, -r.requested[id], &orderID, nil, ""); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// maxDeliveryAddressLength matches the size of the orders.delivery_address column
+const maxDeliveryAddressLength = 500
+
+// orderContact is how an order reaches the customer, as sent when creating an order
+type orderContact struct {
+	CustomerPhone   string `json:"customer_phone"`
+	OrderType       string `json:"order_type"`
+	DeliveryAddress string `json:"delivery_address"`
+}
+
+// validate trims the contact details, defaults the order type to dine-in and checks them.
+// Only delivery orders keep an address.
+func (o *orderContact) validate() error {
+	o.CustomerPhone = strings.TrimSpace(o.CustomerPhone)
+	o.DeliveryAddress = strings.TrimSpace(o.DeliveryAddress)
+	if o.OrderType == "" {
+		o.OrderType = constants.OrderTypeDineIn
+	}
+
+	if !slices.Contains(constants.OrderTypes, o.OrderType) {
+		return errors.New("order_type must be one of dine_in, takeaway or delivery")
+	}
+	if o.CustomerPhone != "" && !utils.ValidatePhone(o.CustomerPhone) {
+		return errors.New("customer_phone must be a phone number of 7 to 15 digits")
+	}
+	if o.OrderType != constants.OrderTypeDelivery {
+		o.DeliveryAddress = ""
+		return nil
+	}
+	if o.DeliveryAddress == "" {
+		return errors.New("delivery_address is required for delivery orders")
+	}
+	if len(o.DeliveryAddress) > maxDeliveryAddressLength {
+		return fmt.Errorf("delivery_address must be at most %d characters", maxDeliveryAddressLength)
+	}
+	return nil
+}
+
+// orderTableID checks the table a dine-in order is placed at and returns its ID. Takeaway and
+// delivery orders aren't served at a table, so their table_id is ignored, e.g. when they are
+// placed from a table's QR code, and they get none.
+func orderTableID(c *fiber.Ctx, restaurantID uint, orderType string, tableID uint) (*uint, *apiError) {
+	if orderType != constants.OrderTypeDineIn {
+		return nil, nil
+	}
+	if tableID == 0 {
+		return nil, newAPIError(fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "table_id is required for dine_in orders")
+	}
+
+	var table models.Table
+	if err := db(c).Where("id = ? AND restaurant_id = ?", tableID, restaurantID).First(&table).Error; err != nil {
+		return nil, newAPIError(fiber.StatusNotFound, constants.ErrCodeTableNotFound, "Table not found")
+	}
+	return &table.ID, nil
+}
+
+// maxScheduleAhead is how far ahead an order can be placed for
+const maxScheduleAhead = 7 * 24 * time.Hour
+
+// orderStartStatus returns the status a new order starts in: scheduled when it is wanted later,
+// pending when it is wanted now. A scheduled time must be in the future and within maxScheduleAhead.
+func orderStartStatus(scheduledFor *time.Time) (string, error) {
+	if scheduledFor == nil {
+		return constants.OrderStatusPending, nil
+	}
+	now := time.Now()
+	if !scheduledFor.After(now) {
+		return "", errors.New("scheduled_for must be in the future")
+	}
+	if scheduledFor.Sub(now) > maxScheduleAhead {
+		return "", fmt.Errorf("scheduled_for must be within %d days", int(maxScheduleAhead.Hours()/24))
+	}
+	return constants.OrderStatusScheduled, nil
+}
+
+// orderDestination describes where an order goes for audit messages, e.g. "for table 4"
+func orderDestination(order models.Order) string {
+	if order.Table != nil {
+		return fmt.Sprintf("for table %d", order.Table.TableNumber)
+	}
+	if order.OrderType == constants.OrderTypeDelivery {
+		return "for delivery"
+	}
+	return "for takeaway"
+}
+
+// orderTotal applies the order-level discount and tip to the items subtotal
+func orderTotal(subtotal, discount, tip utils.Money) (utils.Money, error) {
+	if discount < 0 || tip < 0 {
+		return 0, newAPIError(fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Tip and discount must not be negative")
+	}
+	if discount > subtotal {
+		return 0, newAPIError(fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Discount cannot exceed the order subtotal")
+	}
+	return subtotal - discount + tip, nil
+}
+
+// checkMinOrderAmount rejects a delivery order whose items total, before tip, is below the
+// restaurant's minimum, stating the minimum and how much is missing
+func checkMinOrderAmount(c *fiber.Ctx, restaurantID uint, orderType string, menuItems []models.MenuItem, requested map[uint]int) error {
+	if orderType != constants.OrderTypeDelivery {
+		return nil
+	}
+	settings, err := loadRestaurantSettings(db(c), restaurantID)
+	if err != nil {
+		return err
+	}
+	if settings.MinOrderAmount <= 0 {
+		return nil
+	}
+
+	var subtotal utils.Money
+	for _, menuItem := range menuItems {
+		subtotal += menuItem.Price * utils.Money(requested[menuItem.ID])
+	}
+	if subtotal >= settings.MinOrderAmount {
+		return nil
+	}
+	shortfall := settings.MinOrderAmount - subtotal
+	return newAPIErrorWithData(fiber.StatusBadRequest, constants.ErrCodeBelowMinimumOrder,
+		fmt.Sprintf("Delivery orders must be at least %s; add %s more", settings.MinOrderAmount, shortfall),
+		fiber.Map{"minimum": settings.MinOrderAmount, "shortfall": shortfall})
+}
+
+// kitchenOrderMinutes is the rough time the kitchen takes to clear one order, used to estimate
+// how long customers turned away by checkKitchenCapacity should wait
+const kitchenOrderMinutes = 10
+
+// checkKitchenCapacity turns a customer's order away with 429 KITCHEN_BUSY while the restaurant
+// has as many active orders as its max_active_orders setting allows, with an estimated wait in
+// data.estimated_wait_minutes and the Retry-After header. The count isn't taken under a lock, so
+// a burst of orders may overshoot the limit slightly.
+func checkKitchenCapacity(c *fiber.Ctx, restaurantID uint) error {
+	settings, err := loadRestaurantSettings(db(c), restaurantID)
+	if err != nil {
+		return err
+	}
+	if settings.MaxActiveOrders <= 0 {
+		return nil
+	}
+
+	var active int64
+	if err := restaurantOrders(db(c).Model(&models.Order{}), restaurantID).
+		Where("orders.status IN ?", constants.ActiveOrderStatuses).
+		Count(&active).Error; err != nil {
+		return err
+	}
+	if active < int64(settings.MaxActiveOrders) {
+		return nil
+	}
+
+	// One order has to finish for each order over the limit, plus one to make room
+	waitMinutes := int(active-int64(settings.MaxActiveOrders)+1)*kitchenOrderMinutes + settings.PrepBufferMinutes
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(waitMinutes*60))
+	return newAPIErrorWithData(fiber.StatusTooManyRequests, constants.ErrCodeKitchenBusy,
+		fmt.Sprintf("The kitchen is at capacity; please try again in about %d minutes", waitMinutes),
+		fiber.Map{"estimated_wait_minutes": waitMinutes})
+}
+
+// orderItemRequest is a line of an order as sent when creating one
+type orderItemRequest struct {
+	MenuItemID          uint   `json:"menu_item_id"`
+	Quantity            *int   `json:"quantity"` // one portion when left out
+	SpecialInstructions string `json:"special_instructions"`
+}
+
+// quantity applies the default of one portion when no quantity is given
+func (item orderItemRequest) quantity() int {
+	if item.Quantity == nil {
+		return 1
+	}
+	return *item.Quantity
+}
+
+// mergeOrderItems combines lines for the same menu item with the same special instructions into
+// one line with their total quantity, so the kitchen ticket lists each dish once. Lines keep the
+// position of their first occurrence; different instructions stay separate lines.
+func mergeOrderItems(items []orderItemRequest) []orderItemRequest {
+	type lineKey struct {
+		menuItemID          uint
+		specialInstructions string
+	}
+	merged := make([]orderItemRequest, 0, len(items))
+	positions := make(map[lineKey]int, len(items))
+	for _, item := range items {
+		key := lineKey{item.MenuItemID, item.SpecialInstructions}
+		if i, ok := positions[key]; ok {
+			quantity := merged[i].quantity() + item.quantity()
+			merged[i].Quantity = &quantity
+			continue
+		}
+		positions[key] = len(merged)
+		merged = append(merged, item)
+	}
+	return merged
+}
+
+// defaultMaxOrderItemQuantity is used when MAX_ORDER_ITEM_QUANTITY is not set
+const defaultMaxOrderItemQuantity = 999
+
+// validateOrderItems rejects a quantity of zero or less and returns the lines merged. Only a
+// missing quantity defaults to one portion, so a client's 0 is never charged as an item.
+// Quantities are capped per merged line so an unauthenticated order can't overflow totals and
+// stock, even by splitting one dish across several lines.
+func validateOrderItems(items []orderItemRequest) ([]orderItemRequest, error) {
+	for i, item := range items {
+		if item.Quantity != nil && *item.Quantity <= 0 {
+			return nil, fmt.Errorf("order_items.%d.quantity must be at least 1", i)
+		}
+	}
+
+	maxQuantity := getEnvIntOrDefault("MAX_ORDER_ITEM_QUANTITY", defaultMaxOrderItemQuantity)
+	merged := mergeOrderItems(items)
+	for _, item := range merged {
+		if item.quantity() > maxQuantity {
+			return nil, fmt.Errorf("order_items for menu item %d must total at most %d", item.MenuItemID, maxQuantity)
+		}
+	}
+	return merged, nil
+}
+
+// findStockShortages lists the menu items whose stock can't cover the requested quantity
+func findStockShortages(items []models.MenuItem, requested map[uint]int) []StockShortage {
+	var shortages []StockShortage
+	for _, item := range items {
+		if want := requested[item.ID]; item.Quantity < want {
+			shortages = append(shortages, StockShortage{
+				MenuItemID: item.ID,
+				Name:       item.Name,
+				Requested:  want,
+				Available:  item.Quantity,
+			})
+		}
+	}
+	sort.Slice(shortages, func(i, j int) bool { return shortages[i].MenuItemID < shortages[j].MenuItemID })
+	return shortages
 }
 
 func buildOrderResponse(order models.Order, restaurant *models.Restaurant) OrderResponse {
-	updatedOrder := order
-	updatedOrder.Status = utils.MapInternalStatusToFrontend(order.Status)
+	return OrderResponse{
+		Order:          toHandlerOrder(order),
+		RestaurantName: restaurant.Name,
+		RestaurantID:   restaurant.ID,
+	}
+}
 
-	// Convert models.Order to handler.Order
+// toHandlerOrder converts models.Order to handler.Order with the simplified frontend status
+func toHandlerOrder(order models.Order) Order {
 	handlerOrder := Order{
-		ID:           updatedOrder.ID,
-		TableID:      updatedOrder.TableID,
-		CustomerName: updatedOrder.CustomerName,
-		Status:       updatedOrder.Status,
-		TotalAmount:  updatedOrder.TotalAmount,
-		OrderItems:   make([]OrderItem, len(updatedOrder.OrderItems)),
+		ID:              order.ID,
+		OrderRef:        order.OrderRef,
+		TableID:         order.TableID,
+		CustomerName:    order.CustomerName,
+		CustomerPhone:   order.CustomerPhone,
+		OrderType:       order.OrderType,
+		DeliveryAddress: order.DeliveryAddress,
+		Status:          utils.MapInternalStatusToFrontend(order.Status),
+		ItemCount:       orderItemCount(order.OrderItems),
+		Subtotal:        orderSubtotal(order),
+		Discount:        order.Discount,
+		Tip:             order.Tip,
+		TotalAmount:     order.TotalAmount,
+		GroupID:         order.GroupID,
+		ScheduledFor:    order.ScheduledFor,
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+		OrderItems:      make([]OrderItem, len(order.OrderItems)),
 	}
 
-	// Convert order items
-	for i, item := range updatedOrder.OrderItems {
+	// Table number is only available when the Table relation was preloaded
+	if order.Table != nil {
+		handlerOrder.TableNumber = order.Table.TableNumber
+	}
+
+	// Name and price are the snapshots taken when the order was placed, not the current menu
+	for i, item := range order.OrderItems {
 		handlerOrder.OrderItems[i] = OrderItem{
 			ID:                  item.ID,
 			OrderID:             item.OrderID,
 			MenuItemID:          item.MenuItemID,
+			Name:                item.ItemName,
+			Price:               item.UnitPrice,
 			Quantity:            item.Quantity,
+			LineTotal:           orderItemLineTotal(item),
 			SpecialInstructions: item.SpecialInstructions,
+			Status:              item.Status,
 		}
 	}
 
-	return OrderResponse{
-		Order:          handlerOrder,
-		RestaurantName: restaurant.Name,
-		RestaurantID:   restaurant.ID,
-	}
+	return handlerOrder
 }
 
 // GetAllUserOrders godoc
@@ -538,30 +1017,23 @@ func buildOrderResponse(order models.Order, restaurant *models.Restaurant) Order
 // @Tags Order
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} OrderResponse
-// @Failure 404 {string} string "User not found"
-// @Failure 500 {string} string "Error retrieving orders"
+// @Param include_items query bool false "Include each order's items (default true); item_count and subtotal are always set"
+// @Success 200 {object} Envelope[[]OrderResponse]
+// @Failure 404 {object} ErrorEnvelope "User not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving orders"
 // @Router /api/order [get]
 func GetAllUserOrders(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 
 	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "User not found",
-		})
+	if err := db(c).Where("username = ?", username).First(&user).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeUserNotFound, "User not found")
 	}
 
 	// Get all restaurants for the user
 	var restaurants []models.Restaurant
-	if err := database.DB.Where("user_id = ?", user.ID).Find(&restaurants).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving restaurants",
-		})
+	if err := db(c).Where("user_id = ?", user.ID).Find(&restaurants).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving restaurants")
 	}
 
 	// Extract restaurant IDs
@@ -579,87 +1051,32 @@ func GetAllUserOrders(c *fiber.Ctx) error {
 		})
 	}
 
-	// Get all tables for these restaurants to get the table IDs
-	var tables []models.Table
-	if err := database.DB.Where("restaurant_id IN ?", restaurantIDs).Find(&tables).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving tables",
-		})
-	}
-
-	// Extract table IDs
-	var tableIDs []uint
-	for _, table := range tables {
-		tableIDs = append(tableIDs, table.ID)
-	}
-
-	// If user has no tables, return empty array
-	if len(tableIDs) == 0 {
-		return c.JSON(fiber.Map{
-			"success": true,
-			"data":    []OrderResponse{},
-			"error":   nil,
-		})
+	// Get all orders of these restaurants, leaving out those at deleted tables
+	includeItems := c.QueryBool("include_items", true)
+	var orders []models.Order
+	if err := db(c).
+		Where("orders.restaurant_id IN ? AND (orders.table_id IS NULL OR orders.table_id IN (SELECT id FROM tables WHERE tables.deleted_at IS NULL))", restaurantIDs).
+		Preload("Table").Preload("OrderItems").Find(&orders).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving orders")
 	}
 
-	// Get all orders for these tables
-	var orders []models.Order
-	if err := database.DB.Where("table_id IN ?", tableIDs).Preload("OrderItems").Preload("OrderItems.MenuItem").Find(&orders).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving orders",
-		})
+	restaurantNames := make(map[uint]string, len(restaurants))
+	for _, restaurant := range restaurants {
+		restaurantNames[restaurant.ID] = restaurant.Name
 	}
 
 	// Convert orders to OrderResponse with restaurant name and ID
 	var orderResponses []OrderResponse
 	for _, order := range orders {
-		restaurantName := ""
-		var restaurantID uint = 0
-		for _, restaurant := range restaurants {
-			if isRestaurantForTable(restaurant.ID, order.TableID, tables) {
-				restaurantName = restaurant.Name
-				restaurantID = restaurant.ID
-				break
-			}
+		response := OrderResponse{
+			Order:          toHandlerOrder(order),
+			RestaurantName: restaurantNames[order.RestaurantID],
+			RestaurantID:   order.RestaurantID,
 		}
-
-		// Map internal status to simplified frontend status
-		simplifiedStatus := utils.MapInternalStatusToFrontend(order.Status)
-
-		// Create a copy of the order with the simplified status
-		updatedOrder := order
-		updatedOrder.Status = simplifiedStatus
-
-		// Convert models.Order to handler.Order
-		handlerOrder := Order{
-			ID:           updatedOrder.ID,
-			TableID:      updatedOrder.TableID,
-			CustomerName: updatedOrder.CustomerName,
-			Status:       updatedOrder.Status,
-			TotalAmount:  updatedOrder.TotalAmount,
-			OrderItems:   make([]OrderItem, len(updatedOrder.OrderItems)),
-		}
-
-		// Convert order items
-		for i, item := range updatedOrder.OrderItems {
-			handlerOrder.OrderItems[i] = OrderItem{
-				ID:                  item.ID,
-				OrderID:             item.OrderID,
-				MenuItemID:          item.MenuItemID,
-				Quantity:            item.Quantity,
-				SpecialInstructions: item.SpecialInstructions,
-			}
+		if !includeItems {
+			response.OrderItems = nil
 		}
-
-		orderResponses = append(orderResponses, OrderResponse{
-			Order:          handlerOrder,
-			RestaurantName: restaurantName,
-			RestaurantID:   restaurantID,
-		})
+		orderResponses = append(orderResponses, response)
 	}
 
 	return c.JSON(fiber.Map{
@@ -668,13 +1085,3 @@ func GetAllUserOrders(c *fiber.Ctx) error {
 		"error":   nil,
 	})
 }
-
-// Helper function to determine if a restaurant owns a specific table
-func isRestaurantForTable(restaurantID uint, tableID uint, tables []models.Table) bool {
-	for _, table := range tables {
-		if table.ID == tableID {
-			return table.RestaurantID == restaurantID
-		}
-	}
-	return false
-}
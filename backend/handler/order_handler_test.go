@@ -0,0 +1,821 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"order-system/utils"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+func TestFindStockShortagesReportsEveryShortItem(t *testing.T) {
+	items := []models.MenuItem{
+		{Model: gorm.Model{ID: 3}, Name: "Soup", Quantity: 1},
+		{Model: gorm.Model{ID: 1}, Name: "Salad", Quantity: 0},
+		{Model: gorm.Model{ID: 2}, Name: "Bread", Quantity: 10},
+	}
+	requested := map[uint]int{1: 2, 2: 4, 3: 3}
+
+	shortages := findStockShortages(items, requested)
+	if len(shortages) != 2 {
+		t.Fatalf("expected 2 shortages, got %+v", shortages)
+	}
+	if shortages[0] != (StockShortage{MenuItemID: 1, Name: "Salad", Requested: 2, Available: 0}) {
+		t.Fatalf("unexpected first shortage: %+v", shortages[0])
+	}
+	if shortages[1] != (StockShortage{MenuItemID: 3, Name: "Soup", Requested: 3, Available: 1}) {
+		t.Fatalf("unexpected second shortage: %+v", shortages[1])
+	}
+}
+
+func TestOrderTotalAppliesDiscountAndTip(t *testing.T) {
+	total, err := orderTotal(3500, 250, 500)
+	if err != nil {
+		t.Fatalf("orderTotal returned error: %v", err)
+	}
+	if total != 3750 {
+		t.Fatalf("expected total 3750, got %d", total)
+	}
+
+	if _, err := orderTotal(3500, -1, 0); err == nil {
+		t.Fatal("expected negative discount to be rejected")
+	}
+	if _, err := orderTotal(3500, 0, -1); err == nil {
+		t.Fatal("expected negative tip to be rejected")
+	}
+	if _, err := orderTotal(3500, 3501, 0); err == nil {
+		t.Fatal("expected discount above the subtotal to be rejected")
+	}
+}
+
+func TestGetOrdersIncludesItemCountAndSubtotal(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_orderlist", Password: "x", Email: "orderlist@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Order List Restaurant"}
+	database.DB.Create(&restaurant)
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 7}
+	database.DB.Create(&table)
+	dish := models.MenuItem{RestaurantID: restaurant.ID, Name: "Dish", Price: 1200}
+	database.DB.Create(&dish)
+	order := models.Order{
+		RestaurantID: restaurant.ID,
+		TableID:      &table.ID,
+		TotalAmount:  3500 - 300 + 200,
+		Discount:     300,
+		Tip:          200,
+		OrderItems: []models.OrderItem{
+			{MenuItemID: dish.ID, Quantity: 2},
+			{MenuItemID: dish.ID, Quantity: 1},
+		},
+	}
+	if err := database.DB.Create(&order).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/restaurant/:restaurant_id/order", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetOrders(c)
+	})
+	app.Get("/restaurant/:restaurant_id/order/:id", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetOrder(c)
+	})
+
+	for _, includeItems := range []bool{true, false} {
+		req := httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/order?include_items=%t", restaurant.ID, includeItems), nil)
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data []struct {
+				TableNumber int              `json:"table_number"`
+				ItemCount   int              `json:"item_count"`
+				Subtotal    utils.Money      `json:"subtotal"`
+				OrderItems  []map[string]any `json:"order_items"`
+			} `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		if len(body.Data) != 1 {
+			t.Fatalf("include_items=%t: expected 1 order, got %d", includeItems, len(body.Data))
+		}
+		entry := body.Data[0]
+		if entry.TableNumber != table.TableNumber {
+			t.Fatalf("include_items=%t: expected table number %d, got %d", includeItems, table.TableNumber, entry.TableNumber)
+		}
+		if entry.ItemCount != 3 || entry.Subtotal != 3500 {
+			t.Fatalf("include_items=%t: expected 3 items and subtotal 3500, got %d and %d", includeItems, entry.ItemCount, entry.Subtotal)
+		}
+		if includeItems && len(entry.OrderItems) != 2 {
+			t.Fatalf("expected 2 order items, got %d", len(entry.OrderItems))
+		}
+		if !includeItems && entry.OrderItems != nil {
+			t.Fatalf("expected no order items with include_items=false, got %d", len(entry.OrderItems))
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/order/%d", restaurant.ID, order.ID), nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	var body struct {
+		Data Order `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if body.Data.TableNumber != table.TableNumber || body.Data.Subtotal != 3500 {
+		t.Fatalf("expected table %d and subtotal 3500 for the single order, got %+v", table.TableNumber, body.Data)
+	}
+}
+
+func TestOrderItemsKeepMenuItemSnapshot(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_snapshot", Password: "x", Email: "snapshot@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Snapshot Restaurant"}
+	database.DB.Create(&restaurant)
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 1}
+	database.DB.Create(&table)
+	soup := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 450, Quantity: 10}
+	database.DB.Create(&soup)
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	app.Get("/order", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetAllUserOrders(c)
+	})
+
+	payload := fmt.Sprintf(`{"table_id": %d, "order_items": [{"menu_item_id": %d, "quantity": 2}]}`, table.ID, soup.ID)
+	req := httptest.NewRequest("POST", fmt.Sprintf("/restaurants/%d/order", restaurant.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil || resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("creating order: status %v, err %v", resp.StatusCode, err)
+	}
+
+	// Reprice, rename and finally delete the item; the order must still show what was ordered
+	database.DB.Model(&soup).Updates(map[string]interface{}{"name": "Soup of the Day", "price": 600})
+	database.DB.Delete(&soup)
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/order", nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	var body struct {
+		Data []OrderResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(body.Data) != 1 || len(body.Data[0].OrderItems) != 1 {
+		t.Fatalf("expected 1 order with 1 item, got %+v", body.Data)
+	}
+	item := body.Data[0].OrderItems[0]
+	if item.Name != "Soup" || item.Price != 450 {
+		t.Fatalf("expected the ordered name and price Soup/4.50, got %s/%s", item.Name, item.Price)
+	}
+	if item.LineTotal != 900 || body.Data[0].Subtotal != 900 {
+		t.Fatalf("expected line total and subtotal 9.00, got %s and %s", item.LineTotal, body.Data[0].Subtotal)
+	}
+}
+
+func TestOrderRefsCountPerRestaurantAndDay(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_orderref", Password: "x", Email: "orderref@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Ref Restaurant"}
+	database.DB.Create(&restaurant)
+	other := models.Restaurant{UserID: user.ID, Name: "Other Ref Restaurant"}
+	database.DB.Create(&other)
+	tables := map[uint]models.Table{}
+	for _, r := range []models.Restaurant{restaurant, other} {
+		table := models.Table{RestaurantID: r.ID, TableNumber: 1}
+		database.DB.Create(&table)
+		tables[r.ID] = table
+	}
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	app.Post("/restaurant/:restaurant_id/order", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return CreateOrder(c)
+	})
+	placeOrder := func(path string, restaurantID uint) string {
+		payload := fmt.Sprintf(`{"table_id": %d, "order_items": []}`, tables[restaurantID].ID)
+		req := httptest.NewRequest("POST", fmt.Sprintf(path, restaurantID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil || resp.StatusCode != fiber.StatusCreated {
+			t.Fatalf("creating order: status %v, err %v", resp.StatusCode, err)
+		}
+		var body struct {
+			Data Order `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		return body.Data.OrderRef
+	}
+
+	// Public and staff orders share the sequence
+	if ref := placeOrder("/restaurants/%d/order", restaurant.ID); ref != "A-001" {
+		t.Fatalf("expected A-001, got %q", ref)
+	}
+	if ref := placeOrder("/restaurant/%d/order", restaurant.ID); ref != "A-002" {
+		t.Fatalf("expected A-002, got %q", ref)
+	}
+	if ref := placeOrder("/restaurants/%d/order", other.ID); ref != "A-001" {
+		t.Fatalf("expected another restaurant to start at A-001, got %q", ref)
+	}
+
+	database.DB.Create(&models.RestaurantSettings{RestaurantID: restaurant.ID, Currency: "USD", OrderRefPrefix: "T"})
+	if ref := placeOrder("/restaurants/%d/order", restaurant.ID); ref != "T-003" {
+		t.Fatalf("expected the new prefix to continue the day's count as T-003, got %q", ref)
+	}
+}
+
+func TestCreatePublicOrderContactDetails(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_contact", Password: "x", Email: "contact@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Contact Restaurant"}
+	database.DB.Create(&restaurant)
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 1}
+	database.DB.Create(&table)
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	placeOrder := func(contact string) (int, Order) {
+		payload := fmt.Sprintf(`{"table_id": %d, "order_items": [], %s}`, table.ID, contact)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurants/%d/order", restaurant.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data Order `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Data
+	}
+
+	for _, invalid := range []string{
+		`"order_type": "drive_through"`,
+		`"customer_phone": "call me"`,
+		`"order_type": "delivery", "delivery_address": "  "`,
+	} {
+		if status, _ := placeOrder(invalid); status != fiber.StatusBadRequest {
+			t.Fatalf("expected 400 for %s, got %d", invalid, status)
+		}
+	}
+
+	status, order := placeOrder(`"customer_name": "Ana", "customer_phone": " +1 555 010 2030 ", "order_type": "delivery", "delivery_address": "12 Harbour Road"`)
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for a delivery order, got %d", status)
+	}
+	if order.OrderType != constants.OrderTypeDelivery || order.CustomerPhone != "+1 555 010 2030" || order.DeliveryAddress != "12 Harbour Road" {
+		t.Fatalf("expected the delivery contact details to be stored, got %+v", order)
+	}
+
+	// Orders default to dine-in, and only delivery orders keep an address
+	status, order = placeOrder(`"delivery_address": "12 Harbour Road"`)
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for a dine-in order, got %d", status)
+	}
+	if order.OrderType != constants.OrderTypeDineIn || order.DeliveryAddress != "" {
+		t.Fatalf("expected a dine-in order without address, got type %q address %q", order.OrderType, order.DeliveryAddress)
+	}
+}
+
+func TestOrderStatusFollowsOrderType(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_order_flow", Password: "x", Email: "orderflow@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Order Flow Restaurant"}
+	database.DB.Create(&restaurant)
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 1}
+	database.DB.Create(&table)
+	takeaway := models.Order{RestaurantID: restaurant.ID, TableID: &table.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusPending}
+	delivery := models.Order{RestaurantID: restaurant.ID, TableID: &table.ID, OrderType: constants.OrderTypeDelivery, DeliveryAddress: "12 Harbour Road", Status: constants.OrderStatusPending}
+	database.DB.Create(&takeaway)
+	database.DB.Create(&delivery)
+
+	app := fiber.New()
+	app.Patch("/restaurant/:restaurant_id/order/:id", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return UpdateOrderStatus(c)
+	})
+	setStatus := func(order models.Order, status string) int {
+		payload := fmt.Sprintf(`{"status": %q}`, status)
+		req := httptest.NewRequest("PATCH", fmt.Sprintf("/restaurant/%d/order/%d", restaurant.ID, order.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	if status := setStatus(takeaway, constants.FrontendOrderStatusOutForDelivery); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 sending a takeaway order out for delivery, got %d", status)
+	}
+	if status := setStatus(delivery, constants.FrontendOrderStatusOutForDelivery); status != fiber.StatusOK {
+		t.Fatalf("expected 200 sending a delivery order out, got %d", status)
+	}
+	if status := setStatus(delivery, constants.FrontendOrderStatusActive); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 moving an order back to active, got %d", status)
+	}
+	if status := setStatus(delivery, constants.FrontendOrderStatusPaid); status != fiber.StatusOK {
+		t.Fatalf("expected 200 marking the delivered order paid, got %d", status)
+	}
+	if status := setStatus(delivery, "lost"); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown status, got %d", status)
+	}
+}
+
+func TestTakeawayAndDeliveryOrdersWithoutTable(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_tableless", Password: "x", Email: "tableless@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Tableless Restaurant"}
+	database.DB.Create(&restaurant)
+	table := models.Table{RestaurantID: restaurant.ID, TableNumber: 1}
+	database.DB.Create(&table)
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	app.Get("/restaurant/:restaurant_id/order/:id", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetOrder(c)
+	})
+	send := func(method, url, payload string) (int, Order) {
+		req := httptest.NewRequest(method, url, strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data Order `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Data
+	}
+	placeOrder := func(contact string) (int, Order) {
+		return send("POST", fmt.Sprintf("/restaurants/%d/order", restaurant.ID), fmt.Sprintf(`{"order_items": [], %s}`, contact))
+	}
+
+	if status, _ := placeOrder(`"order_type": "dine_in"`); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a dine-in order without a table, got %d", status)
+	}
+
+	status, takeaway := placeOrder(`"order_type": "takeaway"`)
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for a takeaway order without a table, got %d", status)
+	}
+	if takeaway.TableID != nil || takeaway.TableNumber != 0 {
+		t.Fatalf("expected a tableless order, got table %v number %d", takeaway.TableID, takeaway.TableNumber)
+	}
+
+	// A table's QR code doesn't put a delivery order at the table
+	status, delivery := placeOrder(fmt.Sprintf(`"table_id": %d, "order_type": "delivery", "delivery_address": "12 Harbour Road"`, table.ID))
+	if status != fiber.StatusCreated || delivery.TableID != nil {
+		t.Fatalf("expected 201 and no table for a delivery order, got %d with table %v", status, delivery.TableID)
+	}
+
+	// The restaurant finds its tableless orders
+	if status, _ := send("GET", fmt.Sprintf("/restaurant/%d/order/%d", restaurant.ID, takeaway.ID), ""); status != fiber.StatusOK {
+		t.Fatalf("expected 200 reading a tableless order, got %d", status)
+	}
+}
+
+func TestOrderItemQuantityIsDefaultedAndBounded(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_quantity", Password: "x", Email: "quantity@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Quantity Restaurant"}
+	database.DB.Create(&restaurant)
+	dish := models.MenuItem{RestaurantID: restaurant.ID, Name: "Dish", Price: 500, Quantity: 10}
+	database.DB.Create(&dish)
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	app.Post("/restaurant/:restaurant_id/order", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return CreateOrder(c)
+	})
+
+	for _, prefix := range []string{"/restaurants", "/restaurant"} {
+		placeOrder := func(item string) (int, Order) {
+			payload := fmt.Sprintf(`{"order_type": "takeaway", "order_items": [%s]}`, item)
+			req := httptest.NewRequest("POST", fmt.Sprintf("%s/%d/order", prefix, restaurant.ID), strings.NewReader(payload))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, -1)
+			if err != nil {
+				t.Fatalf("fiber app test failed: %v", err)
+			}
+			var body struct {
+				Data Order `json:"data"`
+			}
+			json.NewDecoder(resp.Body).Decode(&body)
+			return resp.StatusCode, body.Data
+		}
+
+		for _, quantity := range []int{0, -2, defaultMaxOrderItemQuantity + 1, 2_000_000_000} {
+			if status, _ := placeOrder(fmt.Sprintf(`{"menu_item_id": %d, "quantity": %d}`, dish.ID, quantity)); status != fiber.StatusBadRequest {
+				t.Fatalf("%s: expected 400 for quantity %d, got %d", prefix, quantity, status)
+			}
+		}
+
+		t.Setenv("MAX_ORDER_ITEM_QUANTITY", "5")
+		if status, _ := placeOrder(fmt.Sprintf(`{"menu_item_id": %d, "quantity": 6}`, dish.ID)); status != fiber.StatusBadRequest {
+			t.Fatalf("%s: expected 400 over the configured maximum, got %d", prefix, status)
+		}
+		if status, _ := placeOrder(fmt.Sprintf(`{"menu_item_id": %[1]d, "quantity": 3}, {"menu_item_id": %[1]d, "quantity": 3}`, dish.ID)); status != fiber.StatusBadRequest {
+			t.Fatalf("%s: expected 400 for lines of one dish adding up over the maximum, got %d", prefix, status)
+		}
+		if status, _ := placeOrder(fmt.Sprintf(`{"menu_item_id": %[1]d, "quantity": 0}, {"menu_item_id": %[1]d, "quantity": 2}`, dish.ID)); status != fiber.StatusBadRequest {
+			t.Fatalf("%s: expected 400 for a zero quantity merged with another line, got %d", prefix, status)
+		}
+
+		status, order := placeOrder(fmt.Sprintf(`{"menu_item_id": %d}`, dish.ID))
+		if status != fiber.StatusCreated {
+			t.Fatalf("%s: expected 201 without a quantity, got %d", prefix, status)
+		}
+		if len(order.OrderItems) != 1 || order.OrderItems[0].Quantity != 1 || order.TotalAmount != 500 {
+			t.Fatalf("%s: expected one portion when the quantity is left out, got %+v", prefix, order.OrderItems)
+		}
+	}
+}
+
+func TestStaffOrdersTakeStock(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_staff_stock", Password: "x", Email: "staffstock@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Staff Stock Restaurant"}
+	database.DB.Create(&restaurant)
+	dish := models.MenuItem{RestaurantID: restaurant.ID, Name: "Dish", Price: 500, Quantity: 3}
+	database.DB.Create(&dish)
+
+	app := fiber.New()
+	app.Post("/restaurant/:restaurant_id/order", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return CreateOrder(c)
+	})
+	placeOrder := func(quantity int) (int, Order) {
+		payload := fmt.Sprintf(`{"order_type": "takeaway", "order_items": [{"menu_item_id": %d, "quantity": %d}]}`, dish.ID, quantity)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurant/%d/order", restaurant.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data Order `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Data
+	}
+	stock := func() int {
+		var item models.MenuItem
+		database.DB.First(&item, dish.ID)
+		return item.Quantity
+	}
+
+	if status, _ := placeOrder(4); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 ordering more than the stock, got %d", status)
+	}
+	status, order := placeOrder(2)
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if got := stock(); got != 1 {
+		t.Fatalf("expected the order to take 2 of 3 in stock, got %d left", got)
+	}
+	var movement models.StockMovement
+	if err := database.DB.Where("order_id = ? AND type = ?", order.ID, constants.StockMovementOrder).First(&movement).Error; err != nil || movement.Delta != -2 {
+		t.Fatalf("expected an order stock movement of -2, got %+v (%v)", movement, err)
+	}
+
+	// Abandoned, the order gives back exactly what it took
+	database.DB.Model(&models.Order{}).Where("id = ?", order.ID).Update("created_at", time.Now().Add(-48*time.Hour))
+	cancelStaleOrders(2 * time.Hour)
+	if got := stock(); got != 3 {
+		t.Fatalf("expected the stale order to restock to 3, got %d", got)
+	}
+}
+
+func TestCreatePublicOrderMergesIdenticalLines(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_merge_lines", Password: "x", Email: "mergelines@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Merge Lines Restaurant"}
+	database.DB.Create(&restaurant)
+	soup := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 400, Quantity: 10}
+	bread := models.MenuItem{RestaurantID: restaurant.ID, Name: "Bread", Price: 150, Quantity: 10}
+	database.DB.Create(&soup)
+	database.DB.Create(&bread)
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	payload := fmt.Sprintf(`{"order_type": "takeaway", "order_items": [
+		{"menu_item_id": %[1]d, "quantity": 2},
+		{"menu_item_id": %[2]d},
+		{"menu_item_id": %[1]d, "quantity": 1, "special_instructions": "no cream"},
+		{"menu_item_id": %[1]d}
+	]}`, soup.ID, bread.ID)
+	req := httptest.NewRequest("POST", fmt.Sprintf("/restaurants/%d/order", restaurant.ID), strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	var body struct {
+		Data Order `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&body)
+
+	lines := make(map[string]int)
+	for _, item := range body.Data.OrderItems {
+		lines[fmt.Sprintf("%s/%s", item.Name, item.SpecialInstructions)] = item.Quantity
+	}
+	expected := map[string]int{"Soup/": 3, "Bread/": 1, "Soup/no cream": 1}
+	if len(lines) != len(expected) || len(body.Data.OrderItems) != len(expected) {
+		t.Fatalf("expected lines %v, got %v", expected, lines)
+	}
+	for line, quantity := range expected {
+		if lines[line] != quantity {
+			t.Fatalf("expected lines %v, got %v", expected, lines)
+		}
+	}
+	if body.Data.TotalAmount != 4*400+150 {
+		t.Fatalf("expected total 1750, got %d", body.Data.TotalAmount)
+	}
+}
+
+func TestDeliveryOrdersNeedTheMinimumAmount(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_minimum", Password: "x", Email: "minimum@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Minimum Restaurant"}
+	database.DB.Create(&restaurant)
+	database.DB.Create(&models.RestaurantSettings{RestaurantID: restaurant.ID, LowStockThreshold: 5, MinOrderAmount: 1500})
+	pizza := models.MenuItem{RestaurantID: restaurant.ID, Name: "Pizza", Price: 1000, Quantity: 10}
+	database.DB.Create(&pizza)
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	placeOrder := func(orderType string, quantity int) (int, map[string]interface{}) {
+		payload := fmt.Sprintf(`{"order_type": %q, "delivery_address": "12 Harbour Road", "tip": "9.00", "order_items": [{"menu_item_id": %d, "quantity": %d}]}`, orderType, pizza.ID, quantity)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurants/%d/order", restaurant.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	// The tip doesn't count towards the minimum
+	status, body := placeOrder(constants.OrderTypeDelivery, 1)
+	if status != fiber.StatusBadRequest || body["code"] != constants.ErrCodeBelowMinimumOrder {
+		t.Fatalf("expected 400 BELOW_MINIMUM_ORDER, got %d: %v", status, body)
+	}
+	if body["error"] != "Delivery orders must be at least 15.00; add 5.00 more" {
+		t.Fatalf("expected the minimum and shortfall in the message, got %v", body["error"])
+	}
+	if data, _ := body["data"].(map[string]interface{}); data["minimum"] != "15.00" || data["shortfall"] != "5.00" {
+		t.Fatalf("expected the minimum and shortfall in data, got %v", body["data"])
+	}
+
+	if status, body := placeOrder(constants.OrderTypeTakeaway, 1); status != fiber.StatusCreated {
+		t.Fatalf("expected takeaway orders to have no minimum, got %d: %v", status, body)
+	}
+	if status, body := placeOrder(constants.OrderTypeDelivery, 2); status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for a delivery order over the minimum, got %d: %v", status, body)
+	}
+}
+
+func TestCustomerOrdersWaitWhileTheKitchenIsFull(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_capacity", Password: "x", Email: "capacity@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Capacity Restaurant"}
+	database.DB.Create(&restaurant)
+	database.DB.Create(&models.RestaurantSettings{RestaurantID: restaurant.ID, LowStockThreshold: 5, PrepBufferMinutes: 5, MaxActiveOrders: 2})
+	soup := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 600, Quantity: 50}
+	database.DB.Create(&soup)
+	// Finished orders don't take up the kitchen
+	database.DB.Create(&models.Order{RestaurantID: restaurant.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusCompleted})
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	placeOrder := func(extra string) (*http.Response, map[string]interface{}) {
+		payload := fmt.Sprintf(`{"order_type": "takeaway", %s"order_items": [{"menu_item_id": %d}]}`, extra, soup.ID)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurants/%d/order", restaurant.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp, body
+	}
+
+	for i := 0; i < 2; i++ {
+		if resp, body := placeOrder(""); resp.StatusCode != fiber.StatusCreated {
+			t.Fatalf("expected order %d to be accepted, got %d: %v", i+1, resp.StatusCode, body)
+		}
+	}
+
+	resp, body := placeOrder("")
+	if resp.StatusCode != fiber.StatusTooManyRequests || body["code"] != constants.ErrCodeKitchenBusy {
+		t.Fatalf("expected 429 KITCHEN_BUSY, got %d: %v", resp.StatusCode, body)
+	}
+	if data, _ := body["data"].(map[string]interface{}); data["estimated_wait_minutes"] != float64(15) {
+		t.Fatalf("expected a 15 minute wait, got %v", body["data"])
+	}
+	if retry := resp.Header.Get("Retry-After"); retry != "900" {
+		t.Fatalf("expected Retry-After 900, got %q", retry)
+	}
+	var soupAfter models.MenuItem
+	database.DB.First(&soupAfter, soup.ID)
+	if soupAfter.Quantity != 48 {
+		t.Fatalf("expected the refused order to leave stock alone, got %d", soupAfter.Quantity)
+	}
+
+	// Pre-orders don't need the kitchen yet
+	scheduledFor := time.Now().Add(3 * time.Hour).UTC().Format(time.RFC3339)
+	if resp, body := placeOrder(fmt.Sprintf(`"scheduled_for": %q, `, scheduledFor)); resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("expected a scheduled order to be accepted, got %d: %v", resp.StatusCode, body)
+	}
+}
+
+func TestTransferOrderTable(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_transfer", Password: "x", Email: "transfer@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Transfer Restaurant"}
+	database.DB.Create(&restaurant)
+	other := models.Restaurant{UserID: user.ID, Name: "Other Transfer Restaurant"}
+	database.DB.Create(&other)
+	from := models.Table{RestaurantID: restaurant.ID, TableNumber: 3}
+	to := models.Table{RestaurantID: restaurant.ID, TableNumber: 8}
+	elsewhere := models.Table{RestaurantID: other.ID, TableNumber: 5}
+	for _, table := range []*models.Table{&from, &to, &elsewhere} {
+		database.DB.Create(table)
+	}
+	createOrder := func(orderType, status string) models.Order {
+		order := models.Order{RestaurantID: restaurant.ID, OrderType: orderType, Status: status, TotalAmount: 1500}
+		if orderType == constants.OrderTypeDineIn {
+			order.TableID = &from.ID
+		}
+		if err := database.DB.Create(&order).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+		return order
+	}
+	order := createOrder(constants.OrderTypeDineIn, constants.OrderStatusPreparing)
+
+	// Listen for the restaurant's events like a dashboard would
+	client := &wsClient{send: make(chan []byte, 16), restaurantIDs: map[uint]struct{}{restaurant.ID: {}}}
+	if !globalOrderHub.add(client) {
+		t.Fatal("expected the hub to accept the client")
+	}
+	defer globalOrderHub.remove(client)
+
+	app := fiber.New()
+	app.Patch("/restaurant/:restaurant_id/order/:id/table", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return TransferOrderTable(c)
+	})
+	move := func(orderID, tableID uint) (int, map[string]interface{}) {
+		payload := fmt.Sprintf(`{"table_id": %d}`, tableID)
+		req := httptest.NewRequest("PATCH", fmt.Sprintf("/restaurant/%d/order/%d/table", restaurant.ID, orderID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+	tableOf := func(orderID uint) *uint {
+		var current models.Order
+		database.DB.First(&current, orderID)
+		return current.TableID
+	}
+
+	if status, body := move(order.ID, elsewhere.ID); status != fiber.StatusNotFound || body["code"] != constants.ErrCodeTableNotFound {
+		t.Fatalf("expected 404 TABLE_NOT_FOUND for another restaurant's table, got %d: %v", status, body)
+	}
+	if tableID := tableOf(order.ID); tableID == nil || *tableID != from.ID {
+		t.Fatalf("expected the order to stay at table %d, got %v", from.ID, tableID)
+	}
+
+	takeaway := createOrder(constants.OrderTypeTakeaway, constants.OrderStatusPending)
+	if status, body := move(takeaway.ID, to.ID); status != fiber.StatusBadRequest || body["code"] != constants.ErrCodeInvalidInput {
+		t.Fatalf("expected 400 INVALID_INPUT moving a takeaway order, got %d: %v", status, body)
+	}
+	if tableOf(takeaway.ID) != nil {
+		t.Fatal("expected the takeaway order to stay without a table")
+	}
+
+	for _, closed := range []string{constants.OrderStatusCompleted, constants.OrderStatusCancelled} {
+		closedOrder := createOrder(constants.OrderTypeDineIn, closed)
+		if status, body := move(closedOrder.ID, to.ID); status != fiber.StatusBadRequest || body["code"] != constants.ErrCodeOrderNotOpen {
+			t.Fatalf("expected 400 ORDER_NOT_OPEN moving a %s order, got %d: %v", closed, status, body)
+		}
+		if tableID := tableOf(closedOrder.ID); tableID == nil || *tableID != from.ID {
+			t.Fatalf("expected the %s order to stay at table %d, got %v", closed, from.ID, tableID)
+		}
+	}
+
+	status, body := move(order.ID, to.ID)
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200 moving the order, got %d: %v", status, body)
+	}
+	if data, _ := body["data"].(map[string]interface{}); data["table_number"] != float64(to.TableNumber) {
+		t.Fatalf("expected the response to show table %d, got %v", to.TableNumber, body["data"])
+	}
+	if tableID := tableOf(order.ID); tableID == nil || *tableID != to.ID {
+		t.Fatalf("expected the order at table %d, got %v", to.ID, tableID)
+	}
+
+	// Only the successful move is announced
+	select {
+	case payload := <-client.send:
+		var event OrderEvent
+		if err := json.Unmarshal(payload, &event); err != nil {
+			t.Fatalf("decoding event: %v", err)
+		}
+		if event.Type != "order_updated" || event.Order.ID != order.ID || event.Order.TableNumber != to.TableNumber {
+			t.Fatalf("expected an order_updated event with table %d, got %+v", to.TableNumber, event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected an order_updated event")
+	}
+	if len(client.send) != 0 {
+		t.Fatalf("expected one event, got %d more", len(client.send))
+	}
+}
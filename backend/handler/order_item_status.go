@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// UpdateOrderItemStatus godoc
+// @Summary Mark an order item ready
+// @Description Mark one item of an order ready, or pending again, while the kitchen is preparing the order.
+// @Description Once every item is ready the order moves to ready. Sends an order_item_updated event, and order_updated when the order moves.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Order ID"
+// @Param item_id path string true "Order item ID"
+// @Param status body OrderItemStatusUpdate true "Item status"
+// @Success 200 {object} Envelope[OrderResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input or unknown status"
+// @Failure 404 {object} ErrorEnvelope "Restaurant, order or order item not found"
+// @Failure 409 {object} ErrorEnvelope "The order is no longer being prepared"
+// @Failure 500 {object} ErrorEnvelope "Error updating order item"
+// @Router /api/restaurant/{restaurant_id}/order/{id}/items/{item_id} [patch]
+func UpdateOrderItemStatus(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+	orderID := c.Params("id")
+	itemID := c.Params("item_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request OrderItemStatusUpdate
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if request.Status != constants.OrderItemStatusPending && request.Status != constants.OrderItemStatusReady {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid status")
+	}
+
+	var order models.Order
+	var item models.OrderItem
+	orderReady := false
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		// Lock the order so two items marked ready at once can't both miss that the order is done
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "orders"}}).
+			Where("orders.id = ? AND orders.restaurant_id = ?", orderID, restaurant.ID).
+			First(&order).Error; err != nil {
+			return newAPIError(fiber.StatusNotFound, constants.ErrCodeOrderNotFound, "Order not found")
+		}
+		if err := tx.Where("id = ? AND order_id = ?", itemID, order.ID).First(&item).Error; err != nil {
+			return newAPIError(fiber.StatusNotFound, constants.ErrCodeOrderItemNotFound, "Order item not found")
+		}
+		if !slices.Contains(constants.KitchenOrderStatuses, order.Status) {
+			return newAPIError(fiber.StatusConflict, constants.ErrCodeInvalidStatusTransition,
+				fmt.Sprintf("Items of a %s order can't be changed", order.Status))
+		}
+
+		if err := tx.Model(&item).Update("status", request.Status).Error; err != nil {
+			return err
+		}
+		if request.Status != constants.OrderItemStatusReady {
+			return nil
+		}
+
+		// The order is ready with its last item
+		var waiting int64
+		if err := tx.Model(&models.OrderItem{}).
+			Where("order_id = ? AND status <> ?", order.ID, constants.OrderItemStatusReady).
+			Count(&waiting).Error; err != nil {
+			return err
+		}
+		if waiting > 0 {
+			return nil
+		}
+		orderReady = true
+		return tx.Model(&order).Update("status", constants.OrderStatusReady).Error
+	}); err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error updating order item")
+	}
+
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntityOrder, order.ID, fmt.Sprintf("Marked %s %s", item.ItemName, request.Status))
+
+	// Items carry their name and price, so the menu items aren't needed for the response
+	if err := db(c).Preload("Table").Preload("OrderItems").First(&order, order.ID).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error loading order")
+	}
+	orderResponse := buildOrderResponse(order, restaurant)
+	for _, changed := range orderResponse.OrderItems {
+		if changed.ID == item.ID {
+			globalOrderHub.publishItem(orderResponse, changed)
+		}
+	}
+	if orderReady {
+		globalOrderHub.publish("order_updated", orderResponse)
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    orderResponse,
+		"error":   nil,
+	})
+}
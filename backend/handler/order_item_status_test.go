@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestOrderIsReadyOnceEveryItemIs(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_item_ready", Password: "x", Email: "itemready@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Coursing Restaurant"}
+	database.DB.Create(&restaurant)
+	soup := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 600}
+	steak := models.MenuItem{RestaurantID: restaurant.ID, Name: "Steak", Price: 2200}
+	database.DB.Create(&soup)
+	database.DB.Create(&steak)
+	order := models.Order{RestaurantID: restaurant.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusPreparing, OrderItems: []models.OrderItem{
+		{MenuItemID: soup.ID, ItemName: "Soup", Quantity: 1, Status: constants.OrderItemStatusPending},
+		{MenuItemID: steak.ID, ItemName: "Steak", Quantity: 1, Status: constants.OrderItemStatusPending},
+	}}
+	database.DB.Create(&order)
+	soupLine, steakLine := order.OrderItems[0], order.OrderItems[1]
+
+	// Listen for the restaurant's events like a dashboard would
+	client := &wsClient{send: make(chan []byte, 16), restaurantIDs: map[uint]struct{}{restaurant.ID: {}}}
+	if !globalOrderHub.add(client) {
+		t.Fatal("expected the hub to accept the client")
+	}
+	defer globalOrderHub.remove(client)
+	nextEvent := func() OrderEvent {
+		select {
+		case payload := <-client.send:
+			var event OrderEvent
+			if err := json.Unmarshal(payload, &event); err != nil {
+				t.Fatalf("decoding event: %v", err)
+			}
+			return event
+		case <-time.After(time.Second):
+			t.Fatal("expected an order event")
+			return OrderEvent{}
+		}
+	}
+
+	app := fiber.New()
+	app.Patch("/restaurant/:restaurant_id/order/:id/items/:item_id", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return UpdateOrderItemStatus(c)
+	})
+	markItem := func(itemID uint, status string) (int, map[string]interface{}) {
+		payload := fmt.Sprintf(`{"status": %q}`, status)
+		req := httptest.NewRequest("PATCH", fmt.Sprintf("/restaurant/%d/order/%d/items/%d", restaurant.ID, order.ID, itemID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+	orderStatus := func() string {
+		var current models.Order
+		database.DB.First(&current, order.ID)
+		return current.Status
+	}
+
+	if status, body := markItem(soupLine.ID, "done"); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an unknown status, got %d: %v", status, body)
+	}
+	if status, body := markItem(soupLine.ID+steakLine.ID+100, constants.OrderItemStatusReady); status != fiber.StatusNotFound || body["code"] != constants.ErrCodeOrderItemNotFound {
+		t.Fatalf("expected 404 ORDER_ITEM_NOT_FOUND, got %d: %v", status, body)
+	}
+
+	// The soup goes out first; the order waits for the steak
+	status, body := markItem(soupLine.ID, constants.OrderItemStatusReady)
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", status, body)
+	}
+	// The response has the shape of the order events
+	data, _ := body["data"].(map[string]interface{})
+	items, _ := data["order_items"].([]interface{})
+	if data["restaurant_name"] != restaurant.Name || len(items) != 2 {
+		t.Fatalf("expected the order as in order events, got %v", body["data"])
+	}
+	if current := orderStatus(); current != constants.OrderStatusPreparing {
+		t.Fatalf("expected the order to stay preparing, got %s", current)
+	}
+	event := nextEvent()
+	if event.Type != "order_item_updated" || event.Item == nil || event.Item.ID != soupLine.ID || event.Item.Status != constants.OrderItemStatusReady {
+		t.Fatalf("expected an order_item_updated event for the soup, got %+v", event)
+	}
+
+	if status, body := markItem(steakLine.ID, constants.OrderItemStatusReady); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", status, body)
+	}
+	if current := orderStatus(); current != constants.OrderStatusReady {
+		t.Fatalf("expected the order to be ready with its last item, got %s", current)
+	}
+	if event := nextEvent(); event.Type != "order_item_updated" || event.Item.ID != steakLine.ID {
+		t.Fatalf("expected an order_item_updated event for the steak, got %+v", event)
+	}
+	if event := nextEvent(); event.Type != "order_updated" || event.Item != nil {
+		t.Fatalf("expected an order_updated event for the ready order, got %+v", event)
+	}
+
+	// Once the order has left the kitchen its items are settled
+	if status, body := markItem(soupLine.ID, constants.OrderItemStatusPending); status != fiber.StatusConflict {
+		t.Fatalf("expected 409 for an order no longer being prepared, got %d: %v", status, body)
+	}
+}
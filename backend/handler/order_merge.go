@@ -0,0 +1,315 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MergeOrders godoc
+// @Summary Merge open orders
+// @Description Group several open orders (e.g. from pushed-together tables) so they can be paid together. Each order keeps its items and history; the group's total is the combined total of its orders that aren't cancelled.
+// @Tags Order
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param orders body MergeOrdersRequest true "Orders to merge"
+// @Success 201 {object} Envelope[OrderGroupResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input, or an order is closed or already merged"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or order not found"
+// @Failure 500 {object} ErrorEnvelope "Error merging orders"
+// @Router /api/restaurant/{restaurant_id}/orders/merge [post]
+func MergeOrders(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request MergeOrdersRequest
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	slices.Sort(request.OrderIDs)
+	orderIDs := slices.Compact(request.OrderIDs)
+	if len(orderIDs) < 2 {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "At least two orders are required to merge")
+	}
+
+	var group models.OrderGroup
+	var response OrderGroupResponse
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		// Lock the orders so they can't be paid, cancelled or merged elsewhere meanwhile
+		var orders []models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "orders"}}).
+			Where("orders.id IN ? AND orders.restaurant_id = ?", orderIDs, restaurant.ID).
+			Order("orders.id").
+			Find(&orders).Error; err != nil {
+			return err
+		}
+		if len(orders) != len(orderIDs) {
+			return newAPIError(fiber.StatusNotFound, constants.ErrCodeOrderNotFound, "Order not found")
+		}
+
+		group = models.OrderGroup{RestaurantID: restaurant.ID}
+		for _, order := range orders {
+			if !slices.Contains(constants.ActiveOrderStatuses, order.Status) {
+				return newAPIError(fiber.StatusBadRequest, constants.ErrCodeOrderNotOpen, fmt.Sprintf("Order %d is no longer open", order.ID))
+			}
+			if order.GroupID != nil {
+				return newAPIError(fiber.StatusBadRequest, constants.ErrCodeOrderAlreadyMerged, fmt.Sprintf("Order %d is already merged", order.ID))
+			}
+		}
+
+		if err := tx.Create(&group).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Order{}).Where("id IN ?", orderIDs).Update("group_id", group.ID).Error; err != nil {
+			return err
+		}
+
+		group, err = loadOrderGroup(tx, restaurant.ID, group.ID)
+		if err != nil {
+			return err
+		}
+		response, err = buildOrderGroupResponse(tx, group)
+		return err
+	}); err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error merging orders")
+	}
+
+	for _, order := range group.Orders {
+		globalOrderHub.publish("order_updated", buildOrderResponse(order, restaurant))
+	}
+
+	recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityOrderGroup, group.ID, fmt.Sprintf("Merged %d orders", len(group.Orders)))
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+		"error":   nil,
+	})
+}
+
+// GetOrderGroup godoc
+// @Summary Get an order group
+// @Description Get merged orders with their combined total, what has been paid towards it and what is left to pay
+// @Tags Order
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Order group ID"
+// @Success 200 {object} Envelope[OrderGroupResponse]
+// @Failure 404 {object} ErrorEnvelope "Restaurant or order group not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving order group"
+// @Router /api/restaurant/{restaurant_id}/orders/group/{id} [get]
+func GetOrderGroup(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	group, err := loadOrderGroup(db(c), restaurant.ID, parseUint(c.Params("id")))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeOrderGroupNotFound, "Order group not found")
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving order group")
+	}
+	response, err := buildOrderGroupResponse(db(c), group)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving order group")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+		"error":   nil,
+	})
+}
+
+// PayOrderGroup godoc
+// @Summary Record a payment for an order group
+// @Description Record a payment towards merged orders. The amount is spread over the group's orders with a balance left, oldest order first, as one payment per order; each order is marked completed once it is fully paid. Overpayment is rejected.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Order group ID"
+// @Param payment body SplitPaymentRequest true "Payment amount and method"
+// @Success 201 {object} Envelope[GroupPaymentResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input, unknown fields listed in data.unknown_fields, or payment exceeds the remaining balance"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or order group not found"
+// @Failure 500 {object} ErrorEnvelope "Error recording payment"
+// @Router /api/restaurant/{restaurant_id}/orders/group/{id}/payments [post]
+func PayOrderGroup(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+	groupID := parseUint(c.Params("id"))
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request SplitPaymentRequest
+	if err := utils.BodyParserStrict(c, &request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if request.Amount <= 0 {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Payment amount must be positive")
+	}
+	if !slices.Contains(constants.PaymentMethods, request.PaymentMethod) {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid payment method")
+	}
+
+	var completed []models.Order
+	var response GroupPaymentResponse
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		var group models.OrderGroup
+		if err := tx.Where("id = ? AND restaurant_id = ?", groupID, restaurant.ID).First(&group).Error; err != nil {
+			return newAPIError(fiber.StatusNotFound, constants.ErrCodeOrderGroupNotFound, "Order group not found")
+		}
+
+		// Lock the group's orders so concurrent payments can't both fit into the same balance
+		var orders []models.Order
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "orders"}}).
+			Where("orders.group_id = ? AND orders.status <> ?", group.ID, constants.OrderStatusCancelled).
+			Order("orders.id").
+			Find(&orders).Error; err != nil {
+			return err
+		}
+
+		balances := make([]utils.Money, len(orders))
+		var remaining utils.Money
+		for i, order := range orders {
+			paid, err := orderAmountPaid(tx, order.ID)
+			if err != nil {
+				return err
+			}
+			balances[i] = max(order.TotalAmount-paid, 0)
+			remaining += balances[i]
+		}
+		if request.Amount > remaining {
+			return newAPIError(fiber.StatusBadRequest, constants.ErrCodePaymentExceedsBalance, "Payment exceeds the remaining balance of "+remaining.String())
+		}
+
+		response = GroupPaymentResponse{GroupID: group.ID, PaymentIDs: []uint{}}
+		left := request.Amount
+		for i, order := range orders {
+			if left == 0 {
+				break
+			}
+			if balances[i] == 0 {
+				continue
+			}
+			payment := models.Payment{
+				OrderID:       order.ID,
+				PaymentMethod: request.PaymentMethod,
+				PaymentStatus: constants.PaymentStatusCompleted,
+				Amount:        min(left, balances[i]),
+			}
+			if err := tx.Create(&payment).Error; err != nil {
+				return err
+			}
+			response.PaymentIDs = append(response.PaymentIDs, payment.ID)
+			left -= payment.Amount
+
+			// Only a fully covered bill completes the order
+			if payment.Amount == balances[i] && order.Status != constants.OrderStatusCompleted {
+				if err := tx.Model(&order).Update("status", constants.OrderStatusCompleted).Error; err != nil {
+					return err
+				}
+				if err := tx.Preload("Table").Preload("OrderItems").First(&order, order.ID).Error; err != nil {
+					return err
+				}
+				completed = append(completed, order)
+			}
+		}
+
+		group, err := loadOrderGroup(tx, restaurant.ID, group.ID)
+		if err != nil {
+			return err
+		}
+		totals, err := buildOrderGroupResponse(tx, group)
+		if err != nil {
+			return err
+		}
+		response.TotalAmount = totals.TotalAmount
+		response.AmountPaid = totals.AmountPaid
+		response.Remaining = totals.Remaining
+		response.GroupComplete = totals.Remaining == 0
+		return nil
+	}); err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error recording payment")
+	}
+
+	for _, order := range completed {
+		globalOrderHub.publish("order_updated", buildOrderResponse(order, restaurant))
+	}
+
+	recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityPayment, response.PaymentIDs[0], fmt.Sprintf("Recorded %s payment of %s for order group %d", request.PaymentMethod, request.Amount, response.GroupID))
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+		"error":   nil,
+	})
+}
+
+// loadOrderGroup loads a restaurant's order group with its orders, oldest first
+func loadOrderGroup(tx *gorm.DB, restaurantID, groupID uint) (models.OrderGroup, error) {
+	var group models.OrderGroup
+	err := tx.Where("id = ? AND restaurant_id = ?", groupID, restaurantID).
+		Preload("Orders", func(db *gorm.DB) *gorm.DB { return db.Order("orders.id") }).
+		Preload("Orders.Table").
+		Preload("Orders.OrderItems").
+		First(&group).Error
+	return group, err
+}
+
+// buildOrderGroupResponse computes the group's totals from its orders; cancelled orders don't count
+func buildOrderGroupResponse(tx *gorm.DB, group models.OrderGroup) (OrderGroupResponse, error) {
+	response := OrderGroupResponse{
+		ID:           group.ID,
+		RestaurantID: group.RestaurantID,
+		Orders:       make([]Order, 0, len(group.Orders)),
+	}
+	for _, order := range group.Orders {
+		response.Orders = append(response.Orders, toHandlerOrder(order))
+		if order.Status == constants.OrderStatusCancelled {
+			continue
+		}
+		paid, err := orderAmountPaid(tx, order.ID)
+		if err != nil {
+			return OrderGroupResponse{}, err
+		}
+		response.TotalAmount += order.TotalAmount
+		response.AmountPaid += paid
+	}
+	response.Remaining = max(response.TotalAmount-response.AmountPaid, 0)
+	return response, nil
+}
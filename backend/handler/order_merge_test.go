@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"order-system/utils"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// orderGroupTestApp serves the order group endpoints as user
+func orderGroupTestApp(user models.User) *fiber.App {
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return c.Next()
+	})
+	app.Post("/restaurant/:restaurant_id/orders/merge", MergeOrders)
+	app.Get("/restaurant/:restaurant_id/orders/group/:id", GetOrderGroup)
+	app.Post("/restaurant/:restaurant_id/orders/group/:id/payments", PayOrderGroup)
+	return app
+}
+
+func TestMergeOrdersOnlyGroupsOpenOrdersOfTheRestaurant(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_merge", Password: "x", Email: "merge@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Merge Restaurant"}
+	database.DB.Create(&restaurant)
+	other := models.Restaurant{UserID: user.ID, Name: "Other Merge Restaurant"}
+	database.DB.Create(&other)
+	createOrder := func(restaurantID uint, status string, total utils.Money) models.Order {
+		order := models.Order{RestaurantID: restaurantID, OrderType: constants.OrderTypeTakeaway, Status: status, TotalAmount: total}
+		if err := database.DB.Create(&order).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+		return order
+	}
+	first := createOrder(restaurant.ID, constants.OrderStatusPending, 1200)
+	second := createOrder(restaurant.ID, constants.OrderStatusPreparing, 800)
+	third := createOrder(restaurant.ID, constants.OrderStatusPending, 500)
+	completed := createOrder(restaurant.ID, constants.OrderStatusCompleted, 900)
+	elsewhere := createOrder(other.ID, constants.OrderStatusPending, 700)
+
+	app := orderGroupTestApp(user)
+	merge := func(ids ...uint) (int, map[string]interface{}) {
+		payload, _ := json.Marshal(map[string][]uint{"order_ids": ids})
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurant/%d/orders/merge", restaurant.ID), strings.NewReader(string(payload)))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+
+	if status, _ := merge(first.ID, first.ID); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 merging a single order, got %d", status)
+	}
+	if status, body := merge(first.ID, completed.ID); status != fiber.StatusBadRequest || body["code"] != constants.ErrCodeOrderNotOpen {
+		t.Fatalf("expected 400 ORDER_NOT_OPEN merging a completed order, got %d: %v", status, body)
+	}
+	if status, body := merge(first.ID, elsewhere.ID); status != fiber.StatusNotFound || body["code"] != constants.ErrCodeOrderNotFound {
+		t.Fatalf("expected 404 merging another restaurant's order, got %d: %v", status, body)
+	}
+
+	status, body := merge(first.ID, second.ID)
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201 merging open orders, got %d: %v", status, body)
+	}
+	group := body["data"].(map[string]interface{})
+	if group["total_amount"] != "20.00" || group["remaining"] != "20.00" || len(group["orders"].([]interface{})) != 2 {
+		t.Fatalf("expected two orders with a combined total of 20.00, got %v", group)
+	}
+
+	if status, body := merge(second.ID, third.ID); status != fiber.StatusBadRequest || body["code"] != constants.ErrCodeOrderAlreadyMerged {
+		t.Fatalf("expected 400 ORDER_ALREADY_MERGED, got %d: %v", status, body)
+	}
+	var unmerged models.Order
+	database.DB.First(&unmerged, third.ID)
+	if unmerged.GroupID != nil {
+		t.Fatal("expected a rejected merge to leave the orders ungrouped")
+	}
+
+	// The total follows the orders: a cancelled order no longer counts
+	database.DB.Model(&models.Order{}).Where("id = ?", second.ID).Update("status", constants.OrderStatusCancelled)
+	resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/orders/group/%v", restaurant.ID, group["id"]), nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	var fetched struct {
+		Data OrderGroupResponse `json:"data"`
+	}
+	json.NewDecoder(resp.Body).Decode(&fetched)
+	if fetched.Data.TotalAmount != 1200 || len(fetched.Data.Orders) != 2 {
+		t.Fatalf("expected a total of 12.00 over both orders after the cancellation, got %s over %d", fetched.Data.TotalAmount, len(fetched.Data.Orders))
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/orders/group/%v", other.ID, group["id"]), nil), -1)
+	if err != nil || resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("expected 404 for the group under another restaurant, got %d (%v)", resp.StatusCode, err)
+	}
+}
+
+func TestPayOrderGroupSpreadsPaymentsOverItsOrders(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_group_pay", Password: "x", Email: "grouppay@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Group Pay Restaurant"}
+	database.DB.Create(&restaurant)
+	group := models.OrderGroup{RestaurantID: restaurant.ID}
+	database.DB.Create(&group)
+	first := models.Order{RestaurantID: restaurant.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusPending, TotalAmount: 1200, GroupID: &group.ID}
+	second := models.Order{RestaurantID: restaurant.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusPending, TotalAmount: 800, GroupID: &group.ID}
+	database.DB.Create(&first)
+	database.DB.Create(&second)
+
+	app := orderGroupTestApp(user)
+	pay := func(amount string) (int, GroupPaymentResponse) {
+		payload := fmt.Sprintf(`{"amount": %q, "payment_method": "cash"}`, amount)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurant/%d/orders/group/%d/payments", restaurant.ID, group.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data GroupPaymentResponse `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Data
+	}
+	orderStatus := func(id uint) string {
+		var order models.Order
+		database.DB.First(&order, id)
+		return order.Status
+	}
+
+	// The oldest order is paid off first and completed; the rest goes to the next
+	status, payment := pay("15.00")
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", status)
+	}
+	if len(payment.PaymentIDs) != 2 || payment.AmountPaid != 1500 || payment.Remaining != 500 || payment.GroupComplete {
+		t.Fatalf("expected two payments leaving 5.00 of 20.00, got %+v", payment)
+	}
+	if orderStatus(first.ID) != constants.OrderStatusCompleted || orderStatus(second.ID) != constants.OrderStatusPending {
+		t.Fatalf("expected only the fully paid order to be completed, got %s and %s", orderStatus(first.ID), orderStatus(second.ID))
+	}
+
+	var before int64
+	database.DB.Model(&models.Payment{}).Count(&before)
+	if status, _ := pay("6.00"); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 paying more than the remaining balance, got %d", status)
+	}
+	var after int64
+	database.DB.Model(&models.Payment{}).Count(&after)
+	if after != before {
+		t.Fatalf("expected a rejected overpayment not to record a payment, got %d more", after-before)
+	}
+
+	status, payment = pay("5.00")
+	if status != fiber.StatusCreated || !payment.GroupComplete || payment.Remaining != 0 {
+		t.Fatalf("expected the last payment to settle the group, got %d: %+v", status, payment)
+	}
+	if orderStatus(second.ID) != constants.OrderStatusCompleted {
+		t.Fatalf("expected the second order to be completed, got %s", orderStatus(second.ID))
+	}
+}
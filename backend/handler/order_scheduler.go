@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"log"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"time"
+)
+
+// StartScheduledOrderReleaser periodically moves scheduled orders to pending once they are due
+// within leadTime, so the kitchen sees them in time to have them ready
+func StartScheduledOrderReleaser(interval, leadTime time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			releaseScheduledOrders(leadTime)
+		}
+	}()
+}
+
+// releaseScheduledOrders moves the scheduled orders due within leadTime to pending and notifies subscribers
+func releaseScheduledOrders(leadTime time.Duration) {
+	var dueOrders []models.Order
+	if err := database.DB.Where("status = ? AND scheduled_for <= ?", constants.OrderStatusScheduled, time.Now().Add(leadTime)).
+		Find(&dueOrders).Error; err != nil {
+		log.Println("failed to load due scheduled orders:", err)
+		return
+	}
+
+	for _, due := range dueOrders {
+		// Only release orders still scheduled, so one cancelled or started by staff meanwhile is left alone
+		result := database.DB.Model(&models.Order{}).
+			Where("id = ? AND status = ?", due.ID, constants.OrderStatusScheduled).
+			Update("status", constants.OrderStatusPending)
+		if result.Error != nil {
+			log.Printf("failed to release scheduled order %d: %v", due.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		var order models.Order
+		if err := database.DB.Preload("Table").Preload("OrderItems").First(&order, due.ID).Error; err != nil {
+			log.Printf("failed to load released order %d: %v", due.ID, err)
+			continue
+		}
+		var restaurant models.Restaurant
+		if err := database.DB.First(&restaurant, order.RestaurantID).Error; err != nil {
+			log.Printf("failed to load restaurant for released order %d: %v", order.ID, err)
+			continue
+		}
+
+		globalOrderHub.publish("order_updated", buildOrderResponse(order, &restaurant))
+	}
+}
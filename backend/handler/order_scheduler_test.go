@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestScheduledOrdersWaitUntilTheyAreDue(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_scheduled", Password: "x", Email: "scheduled@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Scheduled Restaurant"}
+	database.DB.Create(&restaurant)
+	cake := models.MenuItem{RestaurantID: restaurant.ID, Name: "Cake", Price: 900, Quantity: 5}
+	database.DB.Create(&cake)
+
+	app := fiber.New()
+	app.Post("/restaurants/:restaurant_id/order", CreatePublicOrder)
+	app.Get("/restaurant/:restaurant_id/kitchen", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetKitchenOrders(c)
+	})
+	placeOrder := func(scheduledFor time.Time) (int, Order) {
+		payload := fmt.Sprintf(`{"order_type": "takeaway", "scheduled_for": %q, "order_items": [{"menu_item_id": %d}]}`, scheduledFor.Format(time.RFC3339), cake.ID)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurants/%d/order", restaurant.ID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data Order `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Data
+	}
+	kitchenOrderCount := func() int {
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/kitchen", restaurant.ID), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data []KitchenGroup `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		count := 0
+		for _, group := range body.Data {
+			count += len(group.Orders)
+		}
+		return count
+	}
+
+	for _, invalid := range []time.Time{time.Now().Add(-time.Minute), time.Now().Add(maxScheduleAhead + time.Hour)} {
+		if status, _ := placeOrder(invalid); status != fiber.StatusBadRequest {
+			t.Fatalf("expected 400 scheduling for %s, got %d", invalid, status)
+		}
+	}
+
+	wantedAt := time.Now().Add(3 * time.Hour).Truncate(time.Second)
+	status, order := placeOrder(wantedAt)
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for a pre-order, got %d", status)
+	}
+	if order.Status != constants.OrderStatusScheduled || order.ScheduledFor == nil || !order.ScheduledFor.Equal(wantedAt) {
+		t.Fatalf("expected a scheduled order for %s, got %+v", wantedAt, order)
+	}
+	if count := kitchenOrderCount(); count != 0 {
+		t.Fatalf("expected the kitchen not to see the pre-order yet, got %d orders", count)
+	}
+
+	// Not due within the lead time yet
+	releaseScheduledOrders(time.Hour)
+	if count := kitchenOrderCount(); count != 0 {
+		t.Fatalf("expected the pre-order to stay scheduled, got %d kitchen orders", count)
+	}
+
+	releaseScheduledOrders(4 * time.Hour)
+	var released models.Order
+	database.DB.First(&released, order.ID)
+	if released.Status != constants.OrderStatusPending {
+		t.Fatalf("expected the due pre-order to be pending, got %s", released.Status)
+	}
+	if count := kitchenOrderCount(); count != 1 {
+		t.Fatalf("expected the kitchen to see the released pre-order, got %d orders", count)
+	}
+
+	// Placed long ago but wanted later, so it isn't stale
+	database.DB.Model(&released).Update("created_at", time.Now().Add(-48*time.Hour))
+	cancelStaleOrders(2 * time.Hour)
+	database.DB.First(&released, order.ID)
+	if released.Status != constants.OrderStatusPending {
+		t.Fatalf("expected the released pre-order not to be cancelled as stale, got %s", released.Status)
+	}
+}
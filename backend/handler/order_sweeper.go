@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"log"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StartStaleOrderSweeper periodically cancels orders that have been pending for longer than maxAge
+func StartStaleOrderSweeper(interval, maxAge time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			cancelStaleOrders(maxAge)
+		}
+	}()
+}
+
+// cancelStaleOrders cancels abandoned pending orders, restocks their items and notifies subscribers.
+// Pre-orders count from the time they were scheduled for rather than when they were placed.
+func cancelStaleOrders(maxAge time.Duration) {
+	cutoff := time.Now().Add(-maxAge)
+
+	var staleOrders []models.Order
+	if err := database.DB.Where("status = ? AND COALESCE(scheduled_for, created_at) < ?", constants.OrderStatusPending, cutoff).Find(&staleOrders).Error; err != nil {
+		log.Println("failed to load stale pending orders:", err)
+		return
+	}
+
+	for _, stale := range staleOrders {
+		var order models.Order
+		cancelled := false
+		err := database.DB.Transaction(func(tx *gorm.DB) error {
+			// Re-check under lock so an order picked up by staff in the meantime is left alone
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Preload("Table").
+				Preload("OrderItems").
+				First(&order, stale.ID).Error; err != nil {
+				return err
+			}
+			if order.Status != constants.OrderStatusPending {
+				return nil
+			}
+
+			for _, item := range order.OrderItems {
+				if err := tx.Model(&models.MenuItem{}).
+					Where("id = ?", item.MenuItemID).
+					Updates(map[string]interface{}{
+						"quantity": gorm.Expr("quantity + ?", item.Quantity),
+						"version":  gorm.Expr("version + 1"),
+					}).Error; err != nil {
+					return err
+				}
+
+				var menuItem models.MenuItem
+				if err := tx.Unscoped().First(&menuItem, item.MenuItemID).Error; err != nil {
+					return err
+				}
+				if err := recordStockMovement(tx, &menuItem, constants.StockMovementOrderCancelled, item.Quantity, &order.ID, nil, "Stale order cancelled"); err != nil {
+					return err
+				}
+			}
+
+			if err := tx.Model(&order).Update("status", constants.OrderStatusCancelled).Error; err != nil {
+				return err
+			}
+			cancelled = true
+			return nil
+		})
+		if err != nil {
+			log.Printf("failed to cancel stale order %d: %v", stale.ID, err)
+			continue
+		}
+		if !cancelled {
+			continue
+		}
+		globalMenuCache.invalidate(order.RestaurantID)
+
+		var restaurant models.Restaurant
+		if err := database.DB.First(&restaurant, order.RestaurantID).Error; err != nil {
+			log.Printf("failed to load restaurant for stale order %d: %v", order.ID, err)
+			continue
+		}
+
+		globalOrderHub.publish("order_updated", buildOrderResponse(order, &restaurant))
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+	"time"
+)
+
+func TestCancelStaleOrdersSweepsOnlyAbandonedOrders(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_sweeper", Password: "x", Email: "sweeper@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Sweeper Restaurant"}
+	database.DB.Create(&restaurant)
+	soup := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 400, Quantity: 5}
+	bread := models.MenuItem{RestaurantID: restaurant.ID, Name: "Bread", Price: 150, Quantity: 5}
+	database.DB.Create(&soup)
+	database.DB.Create(&bread)
+
+	longAgo := time.Now().Add(-48 * time.Hour)
+	later := time.Now().Add(time.Hour)
+	createOrder := func(status string, createdAt time.Time, scheduledFor *time.Time) models.Order {
+		order := models.Order{
+			RestaurantID: restaurant.ID,
+			OrderType:    constants.OrderTypeTakeaway,
+			Status:       status,
+			ScheduledFor: scheduledFor,
+			CreatedAt:    createdAt,
+			OrderItems: []models.OrderItem{
+				{MenuItemID: soup.ID, ItemName: "Soup", UnitPrice: 400, Quantity: 2},
+				{MenuItemID: bread.ID, ItemName: "Bread", UnitPrice: 150, Quantity: 1},
+			},
+		}
+		if err := database.DB.Create(&order).Error; err != nil {
+			t.Fatalf("creating order: %v", err)
+		}
+		return order
+	}
+	stale := createOrder(constants.OrderStatusPending, longAgo, nil)
+	kept := map[string]models.Order{
+		"recent pending":                  createOrder(constants.OrderStatusPending, time.Now(), nil),
+		"old preparing":                   createOrder(constants.OrderStatusPreparing, longAgo, nil),
+		"released pre-order wanted later": createOrder(constants.OrderStatusPending, longAgo, &later),
+		"scheduled pre-order":             createOrder(constants.OrderStatusScheduled, longAgo, &later),
+	}
+
+	cancelStaleOrders(2 * time.Hour)
+
+	var swept models.Order
+	database.DB.First(&swept, stale.ID)
+	if swept.Status != constants.OrderStatusCancelled {
+		t.Fatalf("expected the stale pending order to be cancelled, got %s", swept.Status)
+	}
+	for name, order := range kept {
+		var current models.Order
+		database.DB.First(&current, order.ID)
+		if current.Status != order.Status {
+			t.Fatalf("expected the %s order to stay %s, got %s", name, order.Status, current.Status)
+		}
+	}
+
+	// Only the cancelled order's items go back into stock, each with a movement
+	for _, want := range []struct {
+		item     models.MenuItem
+		quantity int
+		delta    int
+	}{{soup, 7, 2}, {bread, 6, 1}} {
+		var item models.MenuItem
+		database.DB.First(&item, want.item.ID)
+		if item.Quantity != want.quantity {
+			t.Fatalf("expected %s to be restocked to %d, got %d", want.item.Name, want.quantity, item.Quantity)
+		}
+		var movements []models.StockMovement
+		database.DB.Where("menu_item_id = ?", want.item.ID).Find(&movements)
+		if len(movements) != 1 {
+			t.Fatalf("expected one stock movement for %s, got %d", want.item.Name, len(movements))
+		}
+		movement := movements[0]
+		if movement.Type != constants.StockMovementOrderCancelled || movement.Delta != want.delta || movement.QuantityAfter != want.quantity ||
+			movement.OrderID == nil || *movement.OrderID != stale.ID {
+			t.Fatalf("expected a cancellation movement of +%d for order %d, got %+v", want.delta, stale.ID, movement)
+		}
+	}
+
+	// A second sweep finds nothing left to do
+	cancelStaleOrders(2 * time.Hour)
+	var count int64
+	database.DB.Model(&models.StockMovement{}).Count(&count)
+	if count != 2 {
+		t.Fatalf("expected a repeated sweep not to restock again, got %d movements", count)
+	}
+}
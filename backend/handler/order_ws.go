@@ -7,6 +7,8 @@ import (
 	"order-system/models"
 	"order-system/utils"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/gofiber/websocket/v2"
 )
@@ -15,19 +17,54 @@ type wsClient struct {
 	conn          *websocket.Conn
 	send          chan []byte
 	restaurantIDs map[uint]struct{}
+	username      string
+	tooSlow       atomic.Bool // set by the hub before closing send when the client can't keep up
+	shuttingDown  atomic.Bool // set by the hub before closing send when the server is stopping
+	laggingSince  time.Time   // when send was first found full; zero while the client keeps up. Owned by the hub
+	skipped       int         // events skipped since laggingSince. Owned by the hub
 }
 
+// orderEventVersion is the version of the OrderEvent schema. Bump it when a change would break
+// clients, e.g. renaming or removing a field; adding fields or event types doesn't need it.
+const orderEventVersion = 1
+
+// OrderEvent is the message sent to dashboards over the order WebSocket
 type OrderEvent struct {
-	Type  string        `json:"type"`
-	Order OrderResponse `json:"order"`
+	Version   int           `json:"version"`   // schema version, see orderEventVersion
+	Type      string        `json:"type"`      // order_created, order_updated, order_item_updated or order_deleted
+	Timestamp time.Time     `json:"timestamp"` // when the event was published, in UTC
+	Order     OrderResponse `json:"order"`
+	Item      *OrderItem    `json:"item,omitempty"` // the changed item, for order_item_updated
 }
 
+// orderHubBroadcastBuffer is sized to absorb bursts of order activity without blocking publishers
+const orderHubBroadcastBuffer = 256
+
+// defaultMaxSocketsPerUser is used when WS_MAX_CONNECTIONS_PER_USER is not set
+const defaultMaxSocketsPerUser = 10
+
+// clientSendBuffer is how many events a client may fall behind by. The hub never waits on a
+// client, so one stalled dashboard can't hold up the others; events that find the buffer full
+// are skipped for that client.
+const clientSendBuffer = 32
+
+// DefaultSlowClientTimeout is how long a client's buffer may stay full before the client is
+// disconnected, when WS_SLOW_CLIENT_TIMEOUT is not set
+const DefaultSlowClientTimeout = 10 * time.Second
+
 type orderHub struct {
-	clients    map[*wsClient]struct{}
-	broadcast  chan OrderEvent
-	register   chan *wsClient
-	unregister chan *wsClient
-	mu         sync.Mutex
+	clients     map[*wsClient]struct{}
+	broadcast   chan OrderEvent
+	register    chan *wsClient
+	unregister  chan *wsClient
+	mu          sync.Mutex
+	connections map[string]int // open sockets per user, guarded by mu
+	dropped     atomic.Uint64  // events dropped because the broadcast queue was full
+	quit        chan struct{}  // closed to ask run to stop
+	done        chan struct{}  // closed once run has stopped
+	writers     sync.WaitGroup // running write pumps, waited on during shutdown
+	closeOnce   sync.Once
+	slowTimeout atomic.Int64 // how long a client may lag before it is disconnected, as a time.Duration
 }
 
 var globalOrderHub = newOrderHub()
@@ -37,61 +74,212 @@ func init() {
 }
 
 func newOrderHub() *orderHub {
-	return &orderHub{
-		clients:    map[*wsClient]struct{}{},
-		broadcast:  make(chan OrderEvent, 32),
-		register:   make(chan *wsClient),
-		unregister: make(chan *wsClient),
+	hub := &orderHub{
+		clients:     map[*wsClient]struct{}{},
+		broadcast:   make(chan OrderEvent, orderHubBroadcastBuffer),
+		register:    make(chan *wsClient),
+		unregister:  make(chan *wsClient),
+		connections: map[string]int{},
+		quit:        make(chan struct{}),
+		done:        make(chan struct{}),
 	}
+	hub.slowTimeout.Store(int64(DefaultSlowClientTimeout))
+	return hub
+}
+
+// SetSlowClientTimeout sets how long a dashboard may stay a full buffer behind before it is disconnected
+func SetSlowClientTimeout(timeout time.Duration) {
+	globalOrderHub.slowTimeout.Store(int64(timeout))
+}
+
+// acquire reserves a connection slot for the user, returning false once the limit is reached
+func (h *orderHub) acquire(username string, limit int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.connections[username] >= limit {
+		return false
+	}
+	h.connections[username]++
+	return true
+}
+
+// release frees a connection slot previously reserved with acquire
+func (h *orderHub) release(username string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.connections[username] <= 1 {
+		delete(h.connections, username)
+		return
+	}
+	h.connections[username]--
 }
 
 func (h *orderHub) run() {
+	defer close(h.done)
+
 	for {
 		select {
+		case <-h.quit:
+			h.drain()
+			return
 		case client := <-h.register:
 			h.clients[client] = struct{}{}
+			h.writers.Add(1) // released when the client's write pump returns
 		case client := <-h.unregister:
 			if _, ok := h.clients[client]; ok {
 				delete(h.clients, client)
 				close(client.send)
 			}
 		case event := <-h.broadcast:
-			payload, err := json.Marshal(event)
-			if err != nil {
-				log.Println("failed to marshal order event:", err)
-				continue
-			}
-			for client := range h.clients {
-				if len(client.restaurantIDs) == 0 {
-					continue
-				}
-				if _, ok := client.restaurantIDs[event.Order.RestaurantID]; !ok {
-					continue
-				}
-				select {
-				case client.send <- payload:
-				default:
-					delete(h.clients, client)
-					close(client.send)
-				}
+			h.deliver(event)
+		}
+	}
+}
+
+// deliver sends an event to every client subscribed to the order's restaurant
+func (h *orderHub) deliver(event OrderEvent) {
+	// Events queued without going through enqueue are in the current schema
+	if event.Version == 0 {
+		event.Version = orderEventVersion
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		log.Println("failed to marshal order event:", err)
+		return
+	}
+	now := time.Now()
+	for client := range h.clients {
+		if len(client.restaurantIDs) == 0 {
+			continue
+		}
+		if _, ok := client.restaurantIDs[event.Order.RestaurantID]; !ok {
+			continue
+		}
+		select {
+		case client.send <- payload:
+			if !client.laggingSince.IsZero() {
+				log.Printf("WebSocket client for user %s caught up after %s, %d events skipped", client.username, now.Sub(client.laggingSince), client.skipped)
+				client.laggingSince = time.Time{}
+				client.skipped = 0
 			}
+		default:
+			h.lagging(client, now)
 		}
 	}
 }
 
+// lagging skips an event a client has no room for. A client gets slowTimeout to drain its
+// buffer, and is only disconnected if the buffer is still full on an event after that.
+func (h *orderHub) lagging(client *wsClient, now time.Time) {
+	if client.laggingSince.IsZero() {
+		client.laggingSince = now
+		log.Printf("WebSocket client for user %s is %d events behind, skipping events", client.username, cap(client.send))
+	}
+	client.skipped++
+
+	if lag := now.Sub(client.laggingSince); lag > time.Duration(h.slowTimeout.Load()) {
+		log.Printf("WebSocket client for user %s is too slow (buffer full for %s, %d events skipped), disconnecting", client.username, lag, client.skipped)
+		client.tooSlow.Store(true)
+		delete(h.clients, client)
+		close(client.send)
+	}
+}
+
+// drain delivers events still queued at shutdown, then closes every client's send channel
+// so write pumps flush what they have and send a going-away close frame
+func (h *orderHub) drain() {
+	for len(h.broadcast) > 0 {
+		h.deliver(<-h.broadcast)
+	}
+
+	for client := range h.clients {
+		client.shuttingDown.Store(true)
+		delete(h.clients, client)
+		close(client.send)
+	}
+}
+
+// add registers a client, returning false if the hub has already stopped
+func (h *orderHub) add(client *wsClient) bool {
+	select {
+	case h.register <- client:
+		return true
+	case <-h.done:
+		return false
+	}
+}
+
+// remove unregisters a client; after shutdown the hub has already released it
+func (h *orderHub) remove(client *wsClient) {
+	select {
+	case h.unregister <- client:
+	case <-h.done:
+	}
+}
+
+// shutdown stops the hub and waits up to timeout for clients to be sent their remaining events
+func (h *orderHub) shutdown(timeout time.Duration) bool {
+	h.closeOnce.Do(func() { close(h.quit) })
+
+	flushed := make(chan struct{})
+	go func() {
+		<-h.done
+		h.writers.Wait()
+		close(flushed)
+	}()
+
+	select {
+	case <-flushed:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// publish queues an event for broadcast without blocking the caller.
+// If the broadcast queue is full the event is dropped and logged.
 func (h *orderHub) publish(eventType string, order OrderResponse) {
-	h.broadcast <- OrderEvent{
-		Type:  eventType,
-		Order: order,
+	h.enqueue(OrderEvent{Type: eventType, Order: order})
+}
+
+// publishItem sends an order_item_updated event for one item of the order
+func (h *orderHub) publishItem(order OrderResponse, item OrderItem) {
+	h.enqueue(OrderEvent{Type: "order_item_updated", Order: order, Item: &item})
+}
+
+// enqueue stamps an event and queues it for broadcast, dropping it if the queue is full
+func (h *orderHub) enqueue(event OrderEvent) {
+	event.Version = orderEventVersion
+	event.Timestamp = time.Now().UTC()
+	select {
+	case h.broadcast <- event:
+	default:
+		dropped := h.dropped.Add(1)
+		log.Printf("order hub broadcast queue full, dropping %s event for order %d (%d dropped total)", event.Type, event.Order.ID, dropped)
 	}
 }
 
+// ShutdownOrderHub stops broadcasting order events, flushes queued events to connected
+// dashboards and closes their sockets, waiting at most timeout
+func ShutdownOrderHub(timeout time.Duration) {
+	if !globalOrderHub.shutdown(timeout) {
+		log.Printf("order hub did not drain within %s, closing remaining WebSocket connections", timeout)
+	}
+}
+
+// DroppedOrderEvents returns how many order events were dropped because the broadcast queue was full
+func DroppedOrderEvents() uint64 {
+	return globalOrderHub.dropped.Load()
+}
+
 func HandleOrderSocket(c *websocket.Conn) {
 	token := c.Query("token")
-	log.Printf("WebSocket connection attempt with token: %s", token)
+	log.Printf("WebSocket connection attempt with token: %s", utils.RedactToken(token))
 
-	// Validate the token using the new utility
-	claims, err := utils.ValidateAccessToken(token)
+	// Only accept the short-lived tokens from /api/user/websocket-token
+	claims, err := utils.ValidateWebSocketToken(token)
 	if err != nil {
 		log.Printf("WebSocket authentication failed: %v", err)
 		c.WriteMessage(websocket.TextMessage, []byte("invalid token"))
@@ -109,6 +297,16 @@ func HandleOrderSocket(c *websocket.Conn) {
 	}
 	log.Printf("WebSocket authenticated user: %s", username)
 
+	// Cap open dashboards per user so many tabs can't pile up hub clients
+	limit := getEnvIntOrDefault("WS_MAX_CONNECTIONS_PER_USER", defaultMaxSocketsPerUser)
+	if !globalOrderHub.acquire(username, limit) {
+		log.Printf("WebSocket connection limit (%d) reached for user %s", limit, username)
+		c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "too many connections"))
+		c.Close()
+		return
+	}
+	defer globalOrderHub.release(username)
+
 	restaurantIDs, err := fetchRestaurantIDs(username)
 	if err != nil {
 		log.Printf("WebSocket unable to load restaurant subscriptions for user %s: %v", username, err)
@@ -117,27 +315,35 @@ func HandleOrderSocket(c *websocket.Conn) {
 		return
 	}
 
-	client := newWSClient(c, restaurantIDs)
-	globalOrderHub.register <- client
+	client := newWSClient(c, username, restaurantIDs)
+	if !globalOrderHub.add(client) {
+		c.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		c.Close()
+		return
+	}
 
 	defer func() {
-		globalOrderHub.unregister <- client
+		globalOrderHub.remove(client)
 		c.Close()
 	}()
 
-	go client.writePump()
+	go func() {
+		defer globalOrderHub.writers.Done()
+		client.writePump()
+	}()
 	client.readPump()
 }
 
-func newWSClient(conn *websocket.Conn, restaurantIDs []uint) *wsClient {
+func newWSClient(conn *websocket.Conn, username string, restaurantIDs []uint) *wsClient {
 	idSet := make(map[uint]struct{}, len(restaurantIDs))
 	for _, id := range restaurantIDs {
 		idSet[id] = struct{}{}
 	}
 	return &wsClient{
 		conn:          conn,
-		send:          make(chan []byte, 32),
+		send:          make(chan []byte, clientSendBuffer),
 		restaurantIDs: idSet,
+		username:      username,
 	}
 }
 
@@ -165,7 +371,20 @@ func (c *wsClient) readPump() {
 func (c *wsClient) writePump() {
 	for message := range c.send {
 		if err := c.conn.WriteMessage(websocket.TextMessage, message); err != nil {
-			break
+			return
 		}
 	}
+
+	// The hub dropped this client for sustained backpressure; tell it why instead of going silent
+	if c.tooSlow.Load() {
+		c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow"))
+		c.conn.Close()
+		return
+	}
+
+	// The server is stopping; clients should reconnect to another instance
+	if c.shuttingDown.Load() {
+		c.conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		c.conn.Close()
+	}
 }
@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPublishDoesNotBlockWhenQueueIsFull(t *testing.T) {
+	// Hub without a running loop, so nothing drains the broadcast queue
+	hub := newOrderHub()
+
+	for i := 0; i < orderHubBroadcastBuffer; i++ {
+		hub.publish("order_created", OrderResponse{})
+	}
+	if dropped := hub.dropped.Load(); dropped != 0 {
+		t.Fatalf("expected no dropped events while queue has room, got %d", dropped)
+	}
+
+	// The queue is full now; these must return immediately and be counted
+	hub.publish("order_created", OrderResponse{})
+	hub.publish("order_updated", OrderResponse{})
+
+	if dropped := hub.dropped.Load(); dropped != 2 {
+		t.Fatalf("expected 2 dropped events, got %d", dropped)
+	}
+}
+
+func TestHubConnectionLimitPerUser(t *testing.T) {
+	hub := newOrderHub()
+
+	if !hub.acquire("owner", 2) || !hub.acquire("owner", 2) {
+		t.Fatal("expected connections within the limit to be accepted")
+	}
+	if hub.acquire("owner", 2) {
+		t.Fatal("expected connection beyond the limit to be rejected")
+	}
+	if !hub.acquire("other_owner", 2) {
+		t.Fatal("expected limit to be tracked per user")
+	}
+
+	hub.release("owner")
+	if !hub.acquire("owner", 2) {
+		t.Fatal("expected a released slot to be reusable")
+	}
+}
+
+func TestDeliverDisconnectsClientsThatStayBehind(t *testing.T) {
+	hub := newOrderHub()
+	hub.slowTimeout.Store(int64(20 * time.Millisecond))
+	slow := &wsClient{send: make(chan []byte, 1), restaurantIDs: map[uint]struct{}{1: {}}}
+	fast := &wsClient{send: make(chan []byte, 3), restaurantIDs: map[uint]struct{}{1: {}}}
+	hub.clients[slow] = struct{}{}
+	hub.clients[fast] = struct{}{}
+
+	// The second event finds the slow client's buffer full; deliver must not wait on it
+	start := time.Now()
+	hub.deliver(OrderEvent{Type: "order_created", Order: OrderResponse{RestaurantID: 1}})
+	hub.deliver(OrderEvent{Type: "order_updated", Order: OrderResponse{RestaurantID: 1}})
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Fatalf("expected deliver not to block on a slow client, took %s", elapsed)
+	}
+	if _, ok := hub.clients[slow]; !ok || slow.laggingSince.IsZero() || slow.skipped != 1 {
+		t.Fatalf("expected the slow client to be kept, lagging with 1 skipped event, got %d skipped", slow.skipped)
+	}
+
+	// Still full after the timeout
+	time.Sleep(30 * time.Millisecond)
+	hub.deliver(OrderEvent{Type: "order_updated", Order: OrderResponse{RestaurantID: 1}})
+
+	if _, ok := hub.clients[slow]; ok || !slow.tooSlow.Load() {
+		t.Fatal("expected the slow client to be dropped and marked too slow")
+	}
+	<-slow.send
+	if _, open := <-slow.send; open {
+		t.Fatal("expected the slow client's send channel to be closed")
+	}
+	if _, ok := hub.clients[fast]; !ok || len(fast.send) != 3 {
+		t.Fatalf("expected the other client to get all events, got %d", len(fast.send))
+	}
+}
+
+func TestDeliverKeepsClientsThatCatchUp(t *testing.T) {
+	hub := newOrderHub()
+	hub.slowTimeout.Store(int64(20 * time.Millisecond))
+	client := &wsClient{send: make(chan []byte, 1), restaurantIDs: map[uint]struct{}{1: {}}}
+	hub.clients[client] = struct{}{}
+
+	// The client stalls: the second event is skipped
+	hub.deliver(OrderEvent{Type: "order_created", Order: OrderResponse{RestaurantID: 1}})
+	hub.deliver(OrderEvent{Type: "order_updated", Order: OrderResponse{RestaurantID: 1}})
+	if client.laggingSince.IsZero() {
+		t.Fatal("expected the stalled client to be marked as lagging")
+	}
+
+	// It recovers before the timeout, which starts over once the next event gets through
+	<-client.send
+	hub.deliver(OrderEvent{Type: "order_updated", Order: OrderResponse{RestaurantID: 1}})
+	if !client.laggingSince.IsZero() || client.skipped != 0 {
+		t.Fatalf("expected a client with room again to be caught up, got %d skipped", client.skipped)
+	}
+
+	// Falling behind again long after the first stall is a new grace period
+	time.Sleep(30 * time.Millisecond)
+	hub.deliver(OrderEvent{Type: "order_updated", Order: OrderResponse{RestaurantID: 1}})
+	if _, ok := hub.clients[client]; !ok || client.tooSlow.Load() {
+		t.Fatal("expected the recovered client to stay connected")
+	}
+	if len(client.send) != 1 {
+		t.Fatalf("expected the event sent after recovering to be queued, got %d", len(client.send))
+	}
+}
+
+func TestHubShutdownFlushesQueuedEventsAndClosesClients(t *testing.T) {
+	hub := newOrderHub()
+	go hub.run()
+
+	client := &wsClient{send: make(chan []byte, 4), restaurantIDs: map[uint]struct{}{1: {}}}
+	if !hub.add(client) {
+		t.Fatal("expected running hub to accept the client")
+	}
+
+	// Stand-in for the write pump: count delivered messages until the hub closes send
+	received := make(chan int, 1)
+	go func() {
+		defer hub.writers.Done()
+		count := 0
+		for range client.send {
+			count++
+		}
+		received <- count
+	}()
+
+	hub.publish("order_created", OrderResponse{RestaurantID: 1})
+	if !hub.shutdown(time.Second) {
+		t.Fatal("expected hub to drain before the timeout")
+	}
+
+	if count := <-received; count != 1 {
+		t.Fatalf("expected the queued event to be delivered, got %d messages", count)
+	}
+	if !client.shuttingDown.Load() {
+		t.Fatal("expected client to be marked for a going-away close")
+	}
+	if hub.add(&wsClient{send: make(chan []byte, 1)}) {
+		t.Fatal("expected stopped hub to reject new clients")
+	}
+}
+
+func TestOrderEventsCarryVersionAndTimestamp(t *testing.T) {
+	hub := newOrderHub()
+	client := &wsClient{send: make(chan []byte, 2), restaurantIDs: map[uint]struct{}{1: {}}}
+	hub.clients[client] = struct{}{}
+
+	before := time.Now().UTC()
+	hub.publish("order_created", OrderResponse{RestaurantID: 1})
+	hub.deliver(<-hub.broadcast)
+	// Events queued without a version are sent as the current schema
+	hub.deliver(OrderEvent{Type: "order_updated", Order: OrderResponse{RestaurantID: 1}})
+
+	var published, unversioned map[string]interface{}
+	if err := json.Unmarshal(<-client.send, &published); err != nil {
+		t.Fatalf("decoding event: %v", err)
+	}
+	if err := json.Unmarshal(<-client.send, &unversioned); err != nil {
+		t.Fatalf("decoding event: %v", err)
+	}
+
+	if published["version"] != float64(orderEventVersion) || unversioned["version"] != float64(orderEventVersion) {
+		t.Fatalf("expected version %d on both events, got %v and %v", orderEventVersion, published["version"], unversioned["version"])
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, published["timestamp"].(string))
+	if err != nil || timestamp.Before(before.Truncate(time.Second)) {
+		t.Fatalf("expected the publish time as timestamp, got %v (%v)", published["timestamp"], err)
+	}
+}
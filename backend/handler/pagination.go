@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	// defaultPageLimit is used when a list endpoint is called without ?limit
+	defaultPageLimit = 50
+	// maxPageLimit bounds ?limit so a single request can't ask for everything
+	maxPageLimit = 200
+)
+
+// Pagination describes the page returned by a list endpoint alongside its data
+type Pagination struct {
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+	Total  int64 `json:"total"`
+}
+
+// parsePagination reads the limit/offset query parameters, applying defaults and bounds
+func parsePagination(c *fiber.Ctx) (Pagination, error) {
+	page := Pagination{Limit: defaultPageLimit}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit <= 0 {
+			return page, errors.New("limit must be a positive integer")
+		}
+		if limit > maxPageLimit {
+			limit = maxPageLimit
+		}
+		page.Limit = limit
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return page, errors.New("offset must be a non-negative integer")
+		}
+		page.Offset = offset
+	}
+
+	return page, nil
+}
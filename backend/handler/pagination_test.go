@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestParsePagination(t *testing.T) {
+	app := fiber.New()
+	app.Get("/", func(c *fiber.Ctx) error {
+		page, err := parsePagination(c)
+		if err != nil {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		return c.JSON(page)
+	})
+
+	cases := []struct {
+		query  string
+		status int
+		limit  int
+		offset int
+	}{
+		{"", fiber.StatusOK, defaultPageLimit, 0},
+		{"?limit=10&offset=20", fiber.StatusOK, 10, 20},
+		{"?limit=100000", fiber.StatusOK, maxPageLimit, 0},
+		{"?limit=0", fiber.StatusBadRequest, 0, 0},
+		{"?offset=-1", fiber.StatusBadRequest, 0, 0},
+		{"?limit=abc", fiber.StatusBadRequest, 0, 0},
+	}
+	for _, tc := range cases {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/"+tc.query, nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		if resp.StatusCode != tc.status {
+			t.Fatalf("%q: expected status %d, got %d", tc.query, tc.status, resp.StatusCode)
+		}
+		if tc.status != fiber.StatusOK {
+			continue
+		}
+
+		var page Pagination
+		if err := json.NewDecoder(resp.Body).Decode(&page); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if page.Limit != tc.limit || page.Offset != tc.offset {
+			t.Fatalf("%q: expected limit %d offset %d, got %+v", tc.query, tc.limit, tc.offset, page)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SplitOrderPayment godoc
+// @Summary Record a split payment
+// @Description Record one part of a split bill against an order. The order is marked completed once payments cover its total; overpayment and payments on cancelled or completed orders are rejected.
+// @Tags Payment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Order ID"
+// @Param payment body SplitPaymentRequest true "Payment amount and method"
+// @Success 201 {object} Envelope[SplitPaymentResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input, unknown fields listed in data.unknown_fields, order not payable or payment exceeds the remaining balance"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or order not found"
+// @Failure 500 {object} ErrorEnvelope "Error recording payment"
+// @Router /api/restaurant/{restaurant_id}/order/{id}/payments [post]
+func SplitOrderPayment(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+	orderID := c.Params("id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request SplitPaymentRequest
+	if err := utils.BodyParserStrict(c, &request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if request.Amount <= 0 {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Payment amount must be positive")
+	}
+	if !slices.Contains(constants.PaymentMethods, request.PaymentMethod) {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Invalid payment method")
+	}
+
+	var order models.Order
+	var response SplitPaymentResponse
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		// Lock the order so concurrent split payments can't both fit into the same balance
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "orders"}}).
+			Where("orders.id = ? AND orders.restaurant_id = ?", orderID, restaurant.ID).
+			First(&order).Error; err != nil {
+			return newAPIError(fiber.StatusNotFound, constants.ErrCodeOrderNotFound, "Order not found")
+		}
+		if order.Status == constants.OrderStatusCancelled || order.Status == constants.OrderStatusCompleted {
+			return newAPIError(fiber.StatusBadRequest, constants.ErrCodeOrderNotOpen, fmt.Sprintf("The order is %s and cannot be paid", order.Status))
+		}
+
+		paid, err := orderAmountPaid(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		if paid+request.Amount > order.TotalAmount {
+			return newAPIError(fiber.StatusBadRequest, constants.ErrCodePaymentExceedsBalance, "Payment exceeds the remaining balance of "+(order.TotalAmount-paid).String())
+		}
+
+		payment := models.Payment{
+			OrderID:       order.ID,
+			PaymentMethod: request.PaymentMethod,
+			PaymentStatus: constants.PaymentStatusCompleted,
+			Amount:        request.Amount,
+		}
+		if err := tx.Create(&payment).Error; err != nil {
+			return err
+		}
+
+		response = SplitPaymentResponse{
+			PaymentID:     payment.ID,
+			OrderID:       order.ID,
+			TotalAmount:   order.TotalAmount,
+			AmountPaid:    paid + request.Amount,
+			Remaining:     order.TotalAmount - paid - request.Amount,
+			OrderComplete: paid+request.Amount == order.TotalAmount,
+		}
+
+		// Only a fully covered bill completes the order
+		if response.OrderComplete {
+			if err := tx.Model(&order).Update("status", constants.OrderStatusCompleted).Error; err != nil {
+				return err
+			}
+			return tx.Preload("Table").Preload("OrderItems").First(&order, order.ID).Error
+		}
+		return nil
+	}); err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error recording payment")
+	}
+
+	if response.OrderComplete {
+		globalOrderHub.publish("order_updated", buildOrderResponse(order, restaurant))
+	}
+
+	recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityPayment, response.PaymentID, fmt.Sprintf("Recorded %s payment of %s for order %d", request.PaymentMethod, request.Amount, order.ID))
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+		"error":   nil,
+	})
+}
+
+// orderAmountPaid totals the completed payments of an order
+func orderAmountPaid(tx *gorm.DB, orderID uint) (utils.Money, error) {
+	var paid utils.Money
+	err := tx.Model(&models.Payment{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("order_id = ? AND payment_status = ?", orderID, constants.PaymentStatusCompleted).
+		Scan(&paid).Error
+	return paid, err
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSplitOrderPayment(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_split_pay", Password: "x", Email: "splitpay@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Split Pay Restaurant"}
+	database.DB.Create(&restaurant)
+	order := models.Order{RestaurantID: restaurant.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusPreparing, TotalAmount: 3000}
+	database.DB.Create(&order)
+
+	app := fiber.New()
+	app.Post("/restaurant/:restaurant_id/order/:id/payments", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return SplitOrderPayment(c)
+	})
+	pay := func(orderID uint, amount string) (int, map[string]interface{}) {
+		payload := fmt.Sprintf(`{"amount": %q, "payment_method": "cash"}`, amount)
+		req := httptest.NewRequest("POST", fmt.Sprintf("/restaurant/%d/order/%d/payments", restaurant.ID, orderID), strings.NewReader(payload))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body
+	}
+	payments := func(orderID uint) int64 {
+		var count int64
+		database.DB.Model(&models.Payment{}).Where("order_id = ?", orderID).Count(&count)
+		return count
+	}
+	orderStatus := func(orderID uint) string {
+		var current models.Order
+		database.DB.First(&current, orderID)
+		return current.Status
+	}
+
+	// A partial payment leaves the rest of the bill open
+	status, body := pay(order.ID, "12.50")
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for a partial payment, got %d: %v", status, body)
+	}
+	data := body["data"].(map[string]interface{})
+	if data["amount_paid"] != "12.50" || data["remaining"] != "17.50" || data["order_complete"] != false {
+		t.Fatalf("expected 17.50 left after paying 12.50, got %v", data)
+	}
+	if got := orderStatus(order.ID); got != constants.OrderStatusPreparing {
+		t.Fatalf("expected a partly paid order to stay %s, got %s", constants.OrderStatusPreparing, got)
+	}
+
+	// Paying more than what is left is rejected without recording anything
+	status, body = pay(order.ID, "17.51")
+	if status != fiber.StatusBadRequest || body["code"] != constants.ErrCodePaymentExceedsBalance {
+		t.Fatalf("expected 400 PAYMENT_EXCEEDS_BALANCE for an overpayment, got %d: %v", status, body)
+	}
+	if count := payments(order.ID); count != 1 {
+		t.Fatalf("expected the overpayment not to be recorded, got %d payments", count)
+	}
+
+	// The final payment completes the order
+	status, body = pay(order.ID, "17.50")
+	if status != fiber.StatusCreated {
+		t.Fatalf("expected 201 for the final payment, got %d: %v", status, body)
+	}
+	data = body["data"].(map[string]interface{})
+	if data["amount_paid"] != "30.00" || data["remaining"] != "0.00" || data["order_complete"] != true {
+		t.Fatalf("expected the bill to be settled, got %v", data)
+	}
+	if got := orderStatus(order.ID); got != constants.OrderStatusCompleted {
+		t.Fatalf("expected the paid order to be completed, got %s", got)
+	}
+
+	// Closed orders take no more payments
+	cancelled := models.Order{RestaurantID: restaurant.ID, OrderType: constants.OrderTypeTakeaway, Status: constants.OrderStatusCancelled, TotalAmount: 1000}
+	database.DB.Create(&cancelled)
+	for _, closed := range []uint{order.ID, cancelled.ID} {
+		before := payments(closed)
+		status, body := pay(closed, "1.00")
+		if status != fiber.StatusBadRequest || body["code"] != constants.ErrCodeOrderNotOpen {
+			t.Fatalf("expected 400 ORDER_NOT_OPEN paying %s order %d, got %d: %v", orderStatus(closed), closed, status, body)
+		}
+		if count := payments(closed); count != before {
+			t.Fatalf("expected no payment to be recorded for closed order %d", closed)
+		}
+	}
+}
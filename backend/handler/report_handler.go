@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/utils"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// reportDateLayout is the expected format of the from/to query parameters
+const reportDateLayout = "2006-01-02"
+
+// parseReportRange reads the from/to query parameters, defaulting to the last 30 days.
+// The returned end time is exclusive and covers the whole "to" day.
+func parseReportRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	now := time.Now()
+	to := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()).AddDate(0, 0, 1)
+	from := to.AddDate(0, 0, -30)
+
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, raw, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		from = parsed
+	}
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.ParseInLocation(reportDateLayout, raw, now.Location())
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		to = parsed.AddDate(0, 0, 1)
+	}
+
+	return from, to, nil
+}
+
+// percentile returns the p-th percentile (0-100) of sorted values using the nearest-rank method
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(float64(len(sorted))*p/100+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
+
+// FulfillmentTimeReport summarizes how long completed orders took from creation to completion
+type FulfillmentTimeReport struct {
+	From             string  `json:"from" example:"2024-05-01"`
+	To               string  `json:"to" example:"2024-05-31"`
+	SampleSize       int     `json:"sample_size"`
+	AverageSeconds   float64 `json:"average_seconds"`
+	MedianSeconds    float64 `json:"median_seconds"`
+	P90Seconds       float64 `json:"p90_seconds"`
+	CompletionSource string  `json:"completion_source" example:"updated_at"` // timestamp used as the completion time
+}
+
+// GetFulfillmentTimeReport godoc
+// @Summary Get order fulfillment time report
+// @Description Get the average, median and p90 time from order creation to completion over a date range
+// @Tags Report
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive, defaults to today"
+// @Success 200 {object} Envelope[FulfillmentTimeReport]
+// @Failure 400 {object} ErrorEnvelope "Invalid date range"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving orders"
+// @Router /api/restaurant/{restaurant_id}/reports/fulfillment-time [get]
+func GetFulfillmentTimeReport(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	from, to, err := parseReportRange(c)
+	if err != nil || !from.Before(to) {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid date range")
+	}
+
+	// There is no status history yet, so the completion time is the UpdatedAt of completed orders
+	var rows []struct {
+		CreatedAt time.Time
+		UpdatedAt time.Time
+	}
+	if err := readDB(c).Model(&models.Order{}).
+		Select("orders.created_at, orders.updated_at").
+		Where("orders.restaurant_id = ? AND orders.status = ?", restaurant.ID, constants.OrderStatusCompleted).
+		Where("orders.created_at >= ? AND orders.created_at < ?", from, to).
+		Scan(&rows).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving orders")
+	}
+
+	durations := make([]float64, 0, len(rows))
+	var total float64
+	for _, row := range rows {
+		seconds := row.UpdatedAt.Sub(row.CreatedAt).Seconds()
+		if seconds < 0 {
+			seconds = 0
+		}
+		durations = append(durations, seconds)
+		total += seconds
+	}
+	sort.Float64s(durations)
+
+	var average float64
+	if len(durations) > 0 {
+		average = total / float64(len(durations))
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data": FulfillmentTimeReport{
+			From:             from.Format(reportDateLayout),
+			To:               to.AddDate(0, 0, -1).Format(reportDateLayout),
+			SampleSize:       len(durations),
+			AverageSeconds:   average,
+			MedianSeconds:    percentile(durations, 50),
+			P90Seconds:       percentile(durations, 90),
+			CompletionSource: "updated_at",
+		},
+		"error": nil,
+	})
+}
+
+// HourlyBucket holds completed-order revenue and count for one hour of the day
+type HourlyBucket struct {
+	Hour    int         `json:"hour"`
+	Revenue utils.Money `json:"revenue" swaggertype:"string"`
+	Count   int64       `json:"count"`
+}
+
+// GetHourlyRevenueReport godoc
+// @Summary Get revenue by hour of day
+// @Description Get completed-order revenue and count bucketed by hour of day over a date range, always 24 buckets
+// @Tags Report
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param from query string false "Start date (YYYY-MM-DD), defaults to 30 days ago"
+// @Param to query string false "End date (YYYY-MM-DD), inclusive, defaults to today"
+// @Success 200 {object} Envelope[[]HourlyBucket]
+// @Failure 400 {object} ErrorEnvelope "Invalid date range"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving orders"
+// @Router /api/restaurant/{restaurant_id}/reports/hourly [get]
+func GetHourlyRevenueReport(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	from, to, err := parseReportRange(c)
+	if err != nil || !from.Before(to) {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid date range")
+	}
+
+	// Aggregate in the database, only the 24 (or fewer) grouped rows come back
+	var rows []HourlyBucket
+	if err := readDB(c).Model(&models.Order{}).
+		Select(database.HourOf("orders.created_at")+" AS hour, CAST(COALESCE(SUM(orders.total_amount), 0) AS BIGINT) AS revenue, COUNT(*) AS count").
+		Where("orders.restaurant_id = ? AND orders.status = ?", restaurant.ID, constants.OrderStatusCompleted).
+		Where("orders.created_at >= ? AND orders.created_at < ?", from, to).
+		Group("hour").
+		Scan(&rows).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving orders")
+	}
+
+	// Always return 24 zero-filled buckets so the chart has a consistent shape
+	buckets := make([]HourlyBucket, 24)
+	for hour := range buckets {
+		buckets[hour].Hour = hour
+	}
+	for _, row := range rows {
+		if row.Hour >= 0 && row.Hour < 24 {
+			buckets[row.Hour] = row
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    buckets,
+		"error":   nil,
+	})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"order-system/utils"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// reportFixture is a restaurant whose orders feed the reports, plus another restaurant of the same owner
+type reportFixture struct {
+	user       models.User
+	restaurant models.Restaurant
+	other      models.Restaurant
+}
+
+func newReportFixture(t *testing.T, username string) reportFixture {
+	t.Helper()
+	user := models.User{Username: username, Password: "x", Email: username + "@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Report Restaurant"}
+	database.DB.Create(&restaurant)
+	other := models.Restaurant{UserID: user.ID, Name: "Other Report Restaurant"}
+	database.DB.Create(&other)
+	return reportFixture{user: user, restaurant: restaurant, other: other}
+}
+
+// order stores an order placed at createdAt and last changed at updatedAt
+func (f reportFixture) order(t *testing.T, restaurantID uint, status string, total utils.Money, createdAt, updatedAt time.Time) {
+	t.Helper()
+	order := models.Order{
+		RestaurantID: restaurantID,
+		OrderType:    constants.OrderTypeTakeaway,
+		Status:       status,
+		TotalAmount:  total,
+		CreatedAt:    createdAt,
+		UpdatedAt:    updatedAt,
+	}
+	if err := database.DB.Create(&order).Error; err != nil {
+		t.Fatalf("creating order: %v", err)
+	}
+}
+
+// get requests a report of the fixture's restaurant and decodes its data into out
+func (f reportFixture) get(t *testing.T, handler fiber.Handler, report, query string, out interface{}) int {
+	t.Helper()
+	app := fiber.New()
+	app.Get("/restaurant/:restaurant_id/reports/"+report, func(c *fiber.Ctx) error {
+		c.Locals("username", f.user.Username)
+		return handler(c)
+	})
+	resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/reports/%s?%s", f.restaurant.ID, report, query), nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	body := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	json.NewDecoder(resp.Body).Decode(&body)
+	return resp.StatusCode
+}
+
+func TestFulfillmentTimeReport(t *testing.T) {
+	testutil.SetupDB(t)
+	f := newReportFixture(t, "testuser_fulfillment")
+
+	day := time.Date(2024, time.March, 5, 12, 0, 0, 0, time.Local)
+	for _, minutes := range []int{20, 5, 60, 15, 10} {
+		f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 1000, day, day.Add(time.Duration(minutes)*time.Minute))
+	}
+	// Left out: cancelled, unfinished, outside the range, or another restaurant's
+	f.order(t, f.restaurant.ID, constants.OrderStatusCancelled, 1000, day, day.Add(5*time.Hour))
+	f.order(t, f.restaurant.ID, constants.OrderStatusPreparing, 1000, day, day.Add(4*time.Hour))
+	f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 1000, day.AddDate(0, 0, -1), day.AddDate(0, 0, -1).Add(3*time.Hour))
+	f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 1000, day.AddDate(0, 0, 2), day.AddDate(0, 0, 2).Add(3*time.Hour))
+	f.order(t, f.other.ID, constants.OrderStatusCompleted, 1000, day, day.Add(2*time.Hour))
+
+	var report FulfillmentTimeReport
+	if status := f.get(t, GetFulfillmentTimeReport, "fulfillment-time", "from=2024-03-05&to=2024-03-06", &report); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if report.From != "2024-03-05" || report.To != "2024-03-06" || report.SampleSize != 5 {
+		t.Fatalf("expected 5 orders from 2024-03-05 to 2024-03-06, got %+v", report)
+	}
+	// 5, 10, 15, 20 and 60 minutes
+	if report.AverageSeconds != 22*60 || report.MedianSeconds != 15*60 || report.P90Seconds != 60*60 {
+		t.Fatalf("expected average 1320s, median 900s and p90 3600s, got %+v", report)
+	}
+
+	// The "to" day is included
+	if f.get(t, GetFulfillmentTimeReport, "fulfillment-time", "from=2024-03-07&to=2024-03-07", &report); report.SampleSize != 1 || report.AverageSeconds != 3*60*60 {
+		t.Fatalf("expected the one order of 2024-03-07, got %+v", report)
+	}
+	if f.get(t, GetFulfillmentTimeReport, "fulfillment-time", "from=2024-04-01&to=2024-04-30", &report); report.SampleSize != 0 || report.AverageSeconds != 0 || report.P90Seconds != 0 {
+		t.Fatalf("expected an empty report for a range without orders, got %+v", report)
+	}
+	if status := f.get(t, GetFulfillmentTimeReport, "fulfillment-time", "from=2024-03-06&to=2024-03-05", &report); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a range ending before it starts, got %d", status)
+	}
+}
+
+func TestHourlyRevenueReport(t *testing.T) {
+	testutil.SetupDB(t)
+	f := newReportFixture(t, "testuser_hourly")
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, time.Local)
+	}
+	// The database buckets by hour in UTC, which is where SQLite puts stored timestamps
+	bucket := func(t time.Time) int { return t.UTC().Hour() }
+
+	morning, evening, lastDay := at(5, 9, 0), at(5, 18, 30), at(6, 20, 0)
+	f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 1250, morning, morning)
+	f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 2000, morning.Add(20*time.Minute), morning)
+	f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 725, evening, evening)
+	f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 300, lastDay, lastDay)
+	// Left out: cancelled, unfinished, outside the range, or another restaurant's
+	f.order(t, f.restaurant.ID, constants.OrderStatusCancelled, 5000, morning, morning)
+	f.order(t, f.restaurant.ID, constants.OrderStatusPending, 3000, evening, evening)
+	f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 999, at(4, 12, 0), at(4, 12, 0))
+	f.order(t, f.restaurant.ID, constants.OrderStatusCompleted, 999, at(7, 12, 0), at(7, 12, 0))
+	f.order(t, f.other.ID, constants.OrderStatusCompleted, 4000, morning, morning)
+
+	var buckets []HourlyBucket
+	if status := f.get(t, GetHourlyRevenueReport, "hourly", "from=2024-03-05&to=2024-03-06", &buckets); status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if len(buckets) != 24 {
+		t.Fatalf("expected 24 buckets, got %d", len(buckets))
+	}
+	want := map[int]HourlyBucket{
+		bucket(morning): {Revenue: 3250, Count: 2},
+		bucket(evening): {Revenue: 725, Count: 1},
+		bucket(lastDay): {Revenue: 300, Count: 1},
+	}
+	for hour, got := range buckets {
+		expected := want[hour]
+		if got.Hour != hour || got.Revenue != expected.Revenue || got.Count != expected.Count {
+			t.Fatalf("expected hour %d to have %d cents over %d orders, got %+v", hour, int64(expected.Revenue), expected.Count, got)
+		}
+	}
+
+	// A range without completed orders still has every hour
+	buckets = nil
+	f.get(t, GetHourlyRevenueReport, "hourly", "from=2024-04-01&to=2024-04-30", &buckets)
+	if len(buckets) != 24 {
+		t.Fatalf("expected 24 zero-filled buckets, got %d", len(buckets))
+	}
+	for hour, got := range buckets {
+		if got.Hour != hour || got.Revenue != 0 || got.Count != 0 {
+			t.Fatalf("expected an empty bucket for hour %d, got %+v", hour, got)
+		}
+	}
+
+	if status := f.get(t, GetHourlyRevenueReport, "hourly", "from=2024-03-06&to=2024-03-05", &buckets); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a range ending before it starts, got %d", status)
+	}
+}
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// CloneRestaurant godoc
+// @Summary Clone a restaurant
+// @Description Create a new restaurant for the same owner with copies of the settings, menu categories, menu items (stock reset to zero) and tables (with new QR codes). Orders and payments are not copied.
+// @Tags Restaurant
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Restaurant ID to copy"
+// @Param restaurant body RestaurantCloneRequest false "Overrides for the new restaurant"
+// @Success 201 {object} Envelope[RestaurantCloneResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error cloning restaurant"
+// @Router /api/restaurant/{id}/clone [post]
+func CloneRestaurant(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+
+	source, err := verifyRestaurantOwnership(c, username, parseUint(c.Params("id")))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request RestaurantCloneRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&request); err != nil {
+			return utils.SendBodyParseError(c, err)
+		}
+	}
+
+	restaurant := models.Restaurant{
+		UserID:      source.UserID,
+		Name:        source.Name + " (copy)",
+		Address:     source.Address,
+		PhoneNumber: source.PhoneNumber,
+		LogoURL:     source.LogoURL,
+	}
+	if name := strings.TrimSpace(request.Name); name != "" {
+		restaurant.Name = name
+	}
+	if request.Address != "" {
+		restaurant.Address = request.Address
+	}
+	if request.PhoneNumber != "" {
+		restaurant.PhoneNumber = request.PhoneNumber
+	}
+
+	response := RestaurantCloneResponse{}
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&restaurant).Error; err != nil {
+			return err
+		}
+
+		var settings models.RestaurantSettings
+		err := tx.Where("restaurant_id = ?", source.ID).First(&settings).Error
+		if err == nil {
+			copied := settings
+			copied.Model = gorm.Model{}
+			copied.RestaurantID = restaurant.ID
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		var categories []models.MenuCategory
+		if err := tx.Where("restaurant_id = ?", source.ID).Find(&categories).Error; err != nil {
+			return err
+		}
+		categoryIDs := make(map[uint]uint, len(categories))
+		for _, category := range categories {
+			copied := models.MenuCategory{
+				RestaurantID: restaurant.ID,
+				Name:         category.Name,
+				DisplayOrder: category.DisplayOrder,
+			}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+			categoryIDs[category.ID] = copied.ID
+		}
+
+		var menuItems []models.MenuItem
+		if err := tx.Where("restaurant_id = ?", source.ID).Find(&menuItems).Error; err != nil {
+			return err
+		}
+		for _, item := range menuItems {
+			// The new location starts without stock
+			copied := models.MenuItem{
+				RestaurantID:  restaurant.ID,
+				SKU:           item.SKU,
+				Name:          item.Name,
+				Description:   item.Description,
+				Price:         item.Price,
+				Category:      item.Category,
+				ImageURL:      item.ImageURL,
+				Quantity:      0,
+				DietaryTags:   item.DietaryTags,
+				Allergens:     item.Allergens,
+				IsFeatured:    item.IsFeatured,
+				FeaturedOrder: item.FeaturedOrder,
+				Station:       item.Station,
+			}
+			if item.CategoryID != nil {
+				if categoryID, ok := categoryIDs[*item.CategoryID]; ok {
+					copied.CategoryID = &categoryID
+				}
+			}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+		}
+
+		var tables []models.Table
+		if err := tx.Where("restaurant_id = ?", source.ID).Order("table_number").Find(&tables).Error; err != nil {
+			return err
+		}
+		for _, table := range tables {
+			copied := models.Table{
+				RestaurantID: restaurant.ID,
+				TableNumber:  table.TableNumber,
+			}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+
+			// QR codes encode the table ID, so each copy needs its own
+			copied.QRCodeURL = generateTableQRCode(restaurant.ID, copied.ID)
+			if err := tx.Save(&copied).Error; err != nil {
+				return err
+			}
+		}
+
+		response.MenuCategories = len(categories)
+		response.MenuItems = len(menuItems)
+		response.Tables = len(tables)
+		return nil
+	}); err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error cloning restaurant")
+	}
+
+	recordAudit(c, &restaurant, constants.AuditActionCreate, constants.AuditEntityRestaurant, restaurant.ID, fmt.Sprintf("Cloned from restaurant %d", source.ID))
+	response.Restaurant = restaurant
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+		"error":   nil,
+	})
+}
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"order-system/utils"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCloneRestaurantCopiesSetupButNotStockOrIDs(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_clone", Password: "x", Email: "clone@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	source := models.Restaurant{UserID: user.ID, Name: "Harbour Bistro", Address: "12 Harbour Road", PhoneNumber: "+1 555 010 2030"}
+	database.DB.Create(&source)
+	database.DB.Create(&models.RestaurantSettings{RestaurantID: source.ID, Currency: "EUR", LowStockThreshold: 3, OrderRefPrefix: "H", MinOrderAmount: 1500})
+	starters := models.MenuCategory{RestaurantID: source.ID, Name: "Starters", DisplayOrder: 2}
+	database.DB.Create(&starters)
+	sku := "SOUP-1"
+	soup := models.MenuItem{
+		RestaurantID: source.ID,
+		SKU:          &sku,
+		Name:         "Soup",
+		Description:  "Tomato",
+		Price:        450,
+		Category:     starters.Name,
+		CategoryID:   &starters.ID,
+		Quantity:     12,
+		DietaryTags:  utils.StringList{"vegan"},
+		IsFeatured:   true,
+		Station:      "kitchen",
+		Version:      4,
+	}
+	database.DB.Create(&soup)
+	table := models.Table{RestaurantID: source.ID, TableNumber: 4}
+	database.DB.Create(&table)
+	database.DB.Model(&table).Update("qr_code_url", generateTableQRCode(source.ID, table.ID))
+	database.DB.Create(&models.Order{RestaurantID: source.ID, TableID: &table.ID, Status: constants.OrderStatusPending, TotalAmount: 900})
+
+	app := fiber.New()
+	app.Post("/restaurant/:id/clone", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return CloneRestaurant(c)
+	})
+	req := httptest.NewRequest("POST", fmt.Sprintf("/restaurant/%d/clone", source.ID), strings.NewReader(`{"name": "Harbour Bistro Uptown"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusCreated {
+		t.Fatalf("expected 201, got %d", resp.StatusCode)
+	}
+	var body struct {
+		Data RestaurantCloneResponse `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+
+	clone := body.Data.Restaurant
+	if clone.ID == 0 || clone.ID == source.ID || clone.UserID != user.ID {
+		t.Fatalf("expected a new restaurant of the same owner, got %+v", clone)
+	}
+	if clone.Name != "Harbour Bistro Uptown" || clone.Address != source.Address || clone.PhoneNumber != source.PhoneNumber {
+		t.Fatalf("expected the new name and the copied contact details, got %+v", clone)
+	}
+	if body.Data.MenuCategories != 1 || body.Data.MenuItems != 1 || body.Data.Tables != 1 {
+		t.Fatalf("expected 1 category, item and table copied, got %+v", body.Data)
+	}
+
+	var settings models.RestaurantSettings
+	if err := database.DB.Where("restaurant_id = ?", clone.ID).First(&settings).Error; err != nil {
+		t.Fatalf("expected the settings to be copied: %v", err)
+	}
+	if settings.Currency != "EUR" || settings.OrderRefPrefix != "H" || settings.MinOrderAmount != 1500 {
+		t.Fatalf("expected the source settings, got %+v", settings)
+	}
+
+	var category models.MenuCategory
+	if err := database.DB.Where("restaurant_id = ?", clone.ID).First(&category).Error; err != nil {
+		t.Fatalf("expected the category to be copied: %v", err)
+	}
+	if category.ID == starters.ID || category.Name != starters.Name || category.DisplayOrder != starters.DisplayOrder {
+		t.Fatalf("expected a new Starters category at position 2, got %+v", category)
+	}
+
+	var item models.MenuItem
+	if err := database.DB.Where("restaurant_id = ?", clone.ID).First(&item).Error; err != nil {
+		t.Fatalf("expected the menu item to be copied: %v", err)
+	}
+	if item.ID == soup.ID || item.CategoryID == nil || *item.CategoryID != category.ID {
+		t.Fatalf("expected a new item in the new category, got id %d category %v", item.ID, item.CategoryID)
+	}
+	if item.Name != soup.Name || item.Description != soup.Description || item.Price != soup.Price || item.SKU == nil || *item.SKU != sku ||
+		!slices.Equal(item.DietaryTags, soup.DietaryTags) || !item.IsFeatured || item.Station != soup.Station {
+		t.Fatalf("expected the item's details to be copied, got %+v", item)
+	}
+	if item.Quantity != 0 || item.Version != 0 {
+		t.Fatalf("expected the copy to start without stock at version 0, got quantity %d version %d", item.Quantity, item.Version)
+	}
+
+	var copiedTable models.Table
+	if err := database.DB.Where("restaurant_id = ?", clone.ID).First(&copiedTable).Error; err != nil {
+		t.Fatalf("expected the table to be copied: %v", err)
+	}
+	var sourceTable models.Table
+	database.DB.First(&sourceTable, table.ID)
+	if copiedTable.ID == table.ID || copiedTable.TableNumber != 4 || copiedTable.QRCodeURL == "" || copiedTable.QRCodeURL == sourceTable.QRCodeURL {
+		t.Fatalf("expected a new table 4 with its own QR code, got id %d number %d", copiedTable.ID, copiedTable.TableNumber)
+	}
+
+	var orders int64
+	database.DB.Model(&models.Order{}).Where("restaurant_id = ?", clone.ID).Count(&orders)
+	if orders != 0 {
+		t.Fatalf("expected no orders to be copied, got %d", orders)
+	}
+	var original models.MenuItem
+	database.DB.First(&original, soup.ID)
+	if original.Quantity != 12 {
+		t.Fatalf("expected the source stock to be left alone, got %d", original.Quantity)
+	}
+}
@@ -1,8 +1,10 @@
 package handler
 
 import (
-	"order-system/database"
+	"fmt"
+	"order-system/constants"
 	"order-system/models"
+	"order-system/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -15,21 +17,18 @@ import (
 // @Produce json
 // @Security BearerAuth
 // @Param restaurant body Restaurant true "Restaurant data"
-// @Success 201 {object} Restaurant
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "User not found"
-// @Failure 500 {string} string "Error creating restaurant"
+// @Success 201 {object} Envelope[Restaurant]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "User not found"
+// @Failure 409 {object} ErrorEnvelope "Duplicate value"
+// @Failure 500 {object} ErrorEnvelope "Error creating restaurant"
 // @Router /api/restaurant/ [post]
 func CreateRestaurant(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 
 	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "User not found",
-		})
+	if err := db(c).Where("username = ?", username).First(&user).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeUserNotFound, "User not found")
 	}
 
 	var request struct {
@@ -40,11 +39,7 @@ func CreateRestaurant(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
 	}
 
 	restaurant := models.Restaurant{
@@ -55,14 +50,16 @@ func CreateRestaurant(c *fiber.Ctx) error {
 		LogoURL:     request.LogoURL,
 	}
 
-	if err := database.DB.Create(&restaurant).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error creating restaurant",
-		})
+	if err := db(c).Create(&restaurant).Error; err != nil {
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error creating restaurant"
+		}
+		return utils.SendError(c, status, code, message)
 	}
 
+	recordAudit(c, &restaurant, constants.AuditActionCreate, constants.AuditEntityRestaurant, restaurant.ID, fmt.Sprintf("Created restaurant %q", restaurant.Name))
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"success": true,
 		"data":    restaurant,
@@ -72,69 +69,75 @@ func CreateRestaurant(c *fiber.Ctx) error {
 
 // GetRestaurants godoc
 // @Summary Get all restaurants
-// @Description Get all restaurants for the authenticated user
+// @Description Get a page of restaurants for the authenticated user, with the total count in pagination
 // @Tags Restaurant
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} Restaurant
-// @Failure 404 {string} string "User not found"
-// @Failure 500 {string} string "Error retrieving restaurants"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Number of restaurants to skip"
+// @Success 200 {object} PageEnvelope[Restaurant]
+// @Failure 400 {object} ErrorEnvelope "Invalid pagination parameters"
+// @Failure 404 {object} ErrorEnvelope "User not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving restaurants"
 // @Router /api/restaurant/ [get]
 func GetRestaurants(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 
+	page, err := parsePagination(c)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid pagination parameters: "+err.Error())
+	}
+
 	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "User not found",
-		})
+	if err := db(c).Where("username = ?", username).First(&user).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeUserNotFound, "User not found")
+	}
+
+	query := db(c).Model(&models.Restaurant{}).Where("user_id = ?", user.ID)
+	if err := query.Count(&page.Total).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving restaurants")
 	}
 
-	var restaurants []models.Restaurant
-	if err := database.DB.Where("user_id = ?", user.ID).Find(&restaurants).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving restaurants",
-		})
+	restaurants := []models.Restaurant{}
+	if err := query.Order("id").Limit(page.Limit).Offset(page.Offset).Find(&restaurants).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving restaurants")
 	}
 
 	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    restaurants,
-		"error":   nil,
+		"success":    true,
+		"data":       restaurants,
+		"pagination": page,
+		"error":      nil,
 	})
 }
 
 // GetRestaurantByID godoc
 // @Summary Get restaurant by ID
-// @Description Get a restaurant by ID
+// @Description Get a restaurant by ID, including its tables, menu items and settings
 // @Tags Restaurant
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Restaurant ID"
-// @Success 200 {object} Restaurant
-// @Failure 404 {string} string "User or restaurant not found"
-// @Failure 500 {string} string "Error retrieving restaurant"
+// @Success 200 {object} Envelope[Restaurant]
+// @Failure 404 {object} ErrorEnvelope "User or restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving restaurant"
 // @Router /api/restaurant/{id} [get]
 func GetRestaurantByID(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	id := c.Params("id")
 
 	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
+	if err := db(c).Where("username = ?", username).First(&user).Error; err != nil {
 		return c.Status(fiber.StatusNotFound).SendString("User not found")
 	}
 
 	var restaurant models.Restaurant
-	if err := database.DB.Where("id = ? AND user_id = ?", id, user.ID).Preload("Tables").Preload("MenuItems").First(&restaurant).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+	if err := db(c).Where("id = ? AND user_id = ?", id, user.ID).Preload("Tables").Preload("MenuItems").Preload("Settings").First(&restaurant).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+	if restaurant.Settings == nil {
+		settings := defaultRestaurantSettings(restaurant.ID)
+		restaurant.Settings = &settings
 	}
 
 	return c.JSON(fiber.Map{
@@ -153,31 +156,24 @@ func GetRestaurantByID(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param id path string true "Restaurant ID"
 // @Param restaurant body Restaurant true "Restaurant data"
-// @Success 200 {object} Restaurant
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "User or restaurant not found"
-// @Failure 500 {string} string "Error updating restaurant"
+// @Success 200 {object} Envelope[Restaurant]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "User or restaurant not found"
+// @Failure 409 {object} ErrorEnvelope "Duplicate value"
+// @Failure 500 {object} ErrorEnvelope "Error updating restaurant"
 // @Router /api/restaurant/{id} [put]
 func UpdateRestaurant(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	id := c.Params("id")
 
 	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "User not found",
-		})
+	if err := db(c).Where("username = ?", username).First(&user).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeUserNotFound, "User not found")
 	}
 
 	var restaurant models.Restaurant
-	if err := database.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&restaurant).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+	if err := db(c).Where("id = ? AND user_id = ?", id, user.ID).First(&restaurant).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var request struct {
@@ -188,11 +184,7 @@ func UpdateRestaurant(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
 	}
 
 	restaurant.Name = request.Name
@@ -200,14 +192,16 @@ func UpdateRestaurant(c *fiber.Ctx) error {
 	restaurant.PhoneNumber = request.PhoneNumber
 	restaurant.LogoURL = request.LogoURL
 
-	if err := database.DB.Save(&restaurant).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error updating restaurant",
-		})
+	if err := db(c).Save(&restaurant).Error; err != nil {
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error updating restaurant"
+		}
+		return utils.SendError(c, status, code, message)
 	}
 
+	recordAudit(c, &restaurant, constants.AuditActionUpdate, constants.AuditEntityRestaurant, restaurant.ID, fmt.Sprintf("Updated restaurant %q", restaurant.Name))
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"data":    restaurant,
@@ -221,26 +215,20 @@ func UpdateRestaurant(c *fiber.Ctx) error {
 // @Tags Restaurant
 // @Produce json
 // @Param id path string true "Restaurant ID"
-// @Success 200 {object} Restaurant
-// @Failure 404 {string} string "Restaurant not found"
+// @Param If-None-Match header string false "ETag from an earlier response"
+// @Success 200 {object} Envelope[Restaurant]
+// @Success 304 {string} string "Restaurant unchanged since the given ETag"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
 // @Router /api/restaurant/{id} [get]
 func GetPublicRestaurantByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	var restaurant models.Restaurant
-	if err := database.DB.Where("id = ?", id).Preload("Tables").First(&restaurant).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+	if err := readDB(c).Where("id = ?", id).Preload("Tables").First(&restaurant).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
-	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    restaurant,
-		"error":   nil,
-	})
+	return sendWithETag(c, restaurant)
 }
 
 // DeleteRestaurant godoc
@@ -250,40 +238,31 @@ func GetPublicRestaurantByID(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param id path string true "Restaurant ID"
-// @Success 200 {object} string
-// @Failure 404 {string} string "User or restaurant not found"
-// @Failure 500 {string} string "Error deleting restaurant"
+// @Success 200 {object} Envelope[string]
+// @Failure 404 {object} ErrorEnvelope "User or restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error deleting restaurant"
 // @Router /api/restaurant/{id} [delete]
 func DeleteRestaurant(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	id := c.Params("id")
 
 	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "User not found",
-		})
+	if err := db(c).Where("username = ?", username).First(&user).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeUserNotFound, "User not found")
 	}
 
 	var restaurant models.Restaurant
-	if err := database.DB.Where("id = ? AND user_id = ?", id, user.ID).First(&restaurant).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+	if err := db(c).Where("id = ? AND user_id = ?", id, user.ID).First(&restaurant).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
-	if err := database.DB.Delete(&restaurant).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error deleting restaurant",
-		})
+	if err := db(c).Delete(&restaurant).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error deleting restaurant")
 	}
 
+	globalMenuCache.invalidate(restaurant.ID)
+	recordAudit(c, &restaurant, constants.AuditActionDelete, constants.AuditEntityRestaurant, restaurant.ID, fmt.Sprintf("Deleted restaurant %q", restaurant.Name))
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"data":    "Restaurant deleted successfully",
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"regexp"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+var orderRefPrefixPattern = regexp.MustCompile(`^[A-Z]{1,3}$`)
+
+// defaultRestaurantSettings returns the settings used until an owner saves their own
+func defaultRestaurantSettings(restaurantID uint) models.RestaurantSettings {
+	return models.RestaurantSettings{
+		RestaurantID:      restaurantID,
+		Currency:          "USD",
+		LowStockThreshold: 5,
+		OrderRefPrefix:    constants.DefaultOrderRefPrefix,
+	}
+}
+
+// loadRestaurantSettings returns the restaurant's saved settings, or the defaults if none exist yet
+func loadRestaurantSettings(tx *gorm.DB, restaurantID uint) (models.RestaurantSettings, error) {
+	var settings models.RestaurantSettings
+	err := tx.Where("restaurant_id = ?", restaurantID).First(&settings).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return defaultRestaurantSettings(restaurantID), nil
+	}
+	return settings, err
+}
+
+// applySettingsUpdate copies the provided fields onto settings, validating each
+func applySettingsUpdate(settings *models.RestaurantSettings, update RestaurantSettingsUpdate) error {
+	if update.TaxRate != nil {
+		if *update.TaxRate < 0 || *update.TaxRate > 100 {
+			return errors.New("tax_rate must be between 0 and 100")
+		}
+		settings.TaxRate = *update.TaxRate
+	}
+	if update.Currency != nil {
+		currency := strings.ToUpper(strings.TrimSpace(*update.Currency))
+		if !currencyCodePattern.MatchString(currency) {
+			return errors.New("currency must be a 3-letter ISO 4217 code")
+		}
+		settings.Currency = currency
+	}
+	if update.PrepBufferMinutes != nil {
+		if *update.PrepBufferMinutes < 0 {
+			return errors.New("prep_buffer_minutes cannot be negative")
+		}
+		settings.PrepBufferMinutes = *update.PrepBufferMinutes
+	}
+	if update.LowStockThreshold != nil {
+		if *update.LowStockThreshold < 0 {
+			return errors.New("low_stock_threshold cannot be negative")
+		}
+		settings.LowStockThreshold = *update.LowStockThreshold
+	}
+	if update.OperatingHoursEnabled != nil {
+		settings.OperatingHoursEnabled = *update.OperatingHoursEnabled
+	}
+	if update.OrderRefPrefix != nil {
+		prefix := strings.ToUpper(strings.TrimSpace(*update.OrderRefPrefix))
+		if !orderRefPrefixPattern.MatchString(prefix) {
+			return errors.New("order_ref_prefix must be 1 to 3 letters")
+		}
+		settings.OrderRefPrefix = prefix
+	}
+	if update.MinOrderAmount != nil {
+		if *update.MinOrderAmount < 0 {
+			return errors.New("min_order_amount cannot be negative")
+		}
+		settings.MinOrderAmount = *update.MinOrderAmount
+	}
+	if update.MaxActiveOrders != nil {
+		if *update.MaxActiveOrders < 0 {
+			return errors.New("max_active_orders cannot be negative")
+		}
+		settings.MaxActiveOrders = *update.MaxActiveOrders
+	}
+	return nil
+}
+
+// GetRestaurantSettings godoc
+// @Summary Get restaurant settings
+// @Description Get a restaurant's settings, or the defaults if none have been saved
+// @Tags Restaurant
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Restaurant ID"
+// @Success 200 {object} Envelope[RestaurantSettings]
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving settings"
+// @Router /api/restaurant/{id}/settings [get]
+func GetRestaurantSettings(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(c.Params("id")))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	settings, err := loadRestaurantSettings(db(c), restaurant.ID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving settings")
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    buildSettingsResponse(settings),
+		"error":   nil,
+	})
+}
+
+// UpdateRestaurantSettings godoc
+// @Summary Update restaurant settings
+// @Description Update only the provided settings fields, creating the settings row on first use
+// @Tags Restaurant
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Restaurant ID"
+// @Param settings body RestaurantSettingsUpdate true "Settings to change"
+// @Success 200 {object} Envelope[RestaurantSettings]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 409 {object} ErrorEnvelope "Duplicate value"
+// @Failure 500 {object} ErrorEnvelope "Error updating settings"
+// @Router /api/restaurant/{id}/settings [patch]
+func UpdateRestaurantSettings(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(c.Params("id")))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request RestaurantSettingsUpdate
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+
+	var settings models.RestaurantSettings
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		var err error
+		settings, err = loadRestaurantSettings(tx, restaurant.ID)
+		if err != nil {
+			return err
+		}
+		if err := applySettingsUpdate(&settings, request); err != nil {
+			return newAPIError(fiber.StatusBadRequest, constants.ErrCodeInvalidInput, err.Error())
+		}
+		return tx.Save(&settings).Error
+	}); err != nil {
+		if apiErr, ok := err.(*apiError); ok {
+			return utils.SendError(c, apiErr.status, apiErr.code, apiErr.message)
+		}
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error updating settings"
+		}
+		return utils.SendError(c, status, code, message)
+	}
+
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntitySettings, settings.ID, "Updated restaurant settings")
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    buildSettingsResponse(settings),
+		"error":   nil,
+	})
+}
+
+func buildSettingsResponse(settings models.RestaurantSettings) RestaurantSettings {
+	return RestaurantSettings{
+		RestaurantID:          settings.RestaurantID,
+		TaxRate:               settings.TaxRate,
+		Currency:              settings.Currency,
+		PrepBufferMinutes:     settings.PrepBufferMinutes,
+		LowStockThreshold:     settings.LowStockThreshold,
+		OperatingHoursEnabled: settings.OperatingHoursEnabled,
+		OrderRefPrefix:        settings.OrderRefPrefix,
+		MinOrderAmount:        settings.MinOrderAmount,
+		MaxActiveOrders:       settings.MaxActiveOrders,
+	}
+}
@@ -0,0 +1,30 @@
+package handler
+
+import "testing"
+
+func TestApplySettingsUpdate(t *testing.T) {
+	settings := defaultRestaurantSettings(7)
+	taxRate := 8.5
+	currency := " eur "
+	if err := applySettingsUpdate(&settings, RestaurantSettingsUpdate{TaxRate: &taxRate, Currency: &currency}); err != nil {
+		t.Fatalf("applySettingsUpdate returned error: %v", err)
+	}
+	if settings.TaxRate != 8.5 || settings.Currency != "EUR" {
+		t.Fatalf("settings = %+v, want tax 8.5 and currency EUR", settings)
+	}
+	if settings.LowStockThreshold != 5 {
+		t.Fatalf("LowStockThreshold = %d, want untouched default 5", settings.LowStockThreshold)
+	}
+
+	invalid := []RestaurantSettingsUpdate{
+		{TaxRate: func() *float64 { v := 101.0; return &v }()},
+		{Currency: func() *string { v := "EURO"; return &v }()},
+		{PrepBufferMinutes: func() *int { v := -1; return &v }()},
+		{LowStockThreshold: func() *int { v := -3; return &v }()},
+	}
+	for _, update := range invalid {
+		if err := applySettingsUpdate(&settings, update); err == nil {
+			t.Fatalf("expected %+v to be rejected", update)
+		}
+	}
+}
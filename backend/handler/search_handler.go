@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// searchResultLimit caps the results returned per kind, enough for a search box dropdown
+const searchResultLimit = 20
+
+// maxSearchQueryLength keeps search patterns short
+const maxSearchQueryLength = 100
+
+// likeEscaper escapes LIKE wildcards so they match literally, with \ as the escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// SearchRestaurant godoc
+// @Summary Search a restaurant
+// @Description Search a restaurant's menu items by name or description and its tables by number in one call.
+// @Description Matching ignores case; tables match when their number starts with the query.
+// @Tags Restaurant
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param q query string true "Search text, at most 100 characters"
+// @Success 200 {object} Envelope[RestaurantSearchResults]
+// @Failure 400 {object} ErrorEnvelope "Missing or too long query"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error searching"
+// @Router /api/restaurant/{restaurant_id}/search [get]
+func SearchRestaurant(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "q is required")
+	}
+	if len(query) > maxSearchQueryLength {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "q must be at most 100 characters")
+	}
+
+	results := RestaurantSearchResults{
+		Query:     query,
+		MenuItems: []models.MenuItem{},
+		Tables:    []models.Table{},
+	}
+
+	pattern := "%" + likeEscaper.Replace(strings.ToLower(query)) + "%"
+	if err := db(c).Where("restaurant_id = ?", restaurant.ID).
+		Where(`LOWER(name) LIKE ? ESCAPE '\' OR LOWER(description) LIKE ? ESCAPE '\'`, pattern, pattern).
+		Order("name ASC").
+		Limit(searchResultLimit).
+		Find(&results.MenuItems).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error searching")
+	}
+
+	// Only digits can match a table number, so "pasta" skips the tables query
+	if isDigits(query) {
+		if err := db(c).Where("restaurant_id = ?", restaurant.ID).
+			Where("CAST(table_number AS TEXT) LIKE ?", query+"%").
+			Order("table_number ASC").
+			Limit(searchResultLimit).
+			Find(&results.Tables).Error; err != nil {
+			return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error searching")
+		}
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    results,
+		"error":   nil,
+	})
+}
+
+// isDigits reports whether s is made of ASCII digits only
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r > unicode.MaxASCII || !unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"net/url"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestSearchRestaurantFindsMenuItemsAndTables(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_search", Password: "x", Email: "search@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Search Restaurant"}
+	other := models.Restaurant{UserID: user.ID, Name: "Other Restaurant"}
+	database.DB.Create(&restaurant)
+	database.DB.Create(&other)
+	database.DB.Create(&[]models.MenuItem{
+		{RestaurantID: restaurant.ID, Name: "Truffle Pasta", Price: 1800},
+		{RestaurantID: restaurant.ID, Name: "Risotto", Description: "Creamy, with truffle oil", Price: 1600},
+		{RestaurantID: restaurant.ID, Name: "100% Beef Burger", Price: 1400},
+		{RestaurantID: restaurant.ID, Name: "Salad", Price: 900},
+		{RestaurantID: other.ID, Name: "Truffle Fries", Price: 700},
+	})
+	for _, number := range []int{1, 12, 21} {
+		database.DB.Create(&models.Table{RestaurantID: restaurant.ID, TableNumber: number})
+	}
+
+	app := fiber.New()
+	app.Get("/restaurant/:restaurant_id/search", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return SearchRestaurant(c)
+	})
+	search := func(q string) (int, RestaurantSearchResults) {
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/search?q=%s", restaurant.ID, url.QueryEscape(q)), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data RestaurantSearchResults `json:"data"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Data
+	}
+	names := func(items []models.MenuItem) []string {
+		result := []string{}
+		for _, item := range items {
+			result = append(result, item.Name)
+		}
+		return result
+	}
+
+	// Names and descriptions match regardless of case, only within the restaurant
+	status, results := search("TRUFFLE")
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if got := fmt.Sprint(names(results.MenuItems)); got != "[Risotto Truffle Pasta]" {
+		t.Fatalf("expected the pasta and the risotto, got %s", got)
+	}
+	if len(results.Tables) != 0 {
+		t.Fatalf("expected no tables for a word, got %d", len(results.Tables))
+	}
+
+	// Table numbers match by prefix; menu items are searched too
+	_, results = search("1")
+	var numbers []int
+	for _, table := range results.Tables {
+		numbers = append(numbers, table.TableNumber)
+	}
+	if fmt.Sprint(numbers) != "[1 12]" {
+		t.Fatalf("expected tables 1 and 12, got %v", numbers)
+	}
+	if got := fmt.Sprint(names(results.MenuItems)); got != "[100% Beef Burger]" {
+		t.Fatalf("expected the burger, got %s", got)
+	}
+
+	// LIKE wildcards in the query match literally
+	if _, results = search("%"); fmt.Sprint(names(results.MenuItems)) != "[100% Beef Burger]" {
+		t.Fatalf("expected only the item with a percent sign, got %v", names(results.MenuItems))
+	}
+
+	if status, _ := search("  "); status != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for an empty query, got %d", status)
+	}
+}
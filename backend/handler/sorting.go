@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// sortableColumns maps the field names a list endpoint accepts in ?sort to the columns they order by.
+// Only whitelisted columns ever reach the ORDER BY clause.
+type sortableColumns map[string]string
+
+var (
+	menuItemSortColumns   = sortableColumns{"price": "price", "name": "name", "created_at": "created_at"}
+	outOfStockSortColumns = sortableColumns{"category": "category", "name": "name", "updated_at": "updated_at"}
+	orderSortColumns      = sortableColumns{"created_at": "created_at", "total_amount": "total_amount"}
+)
+
+// listSort is the ordering requested with ?sort; the zero value keeps the endpoint's default order
+type listSort struct {
+	Column string
+	Desc   bool
+}
+
+// parseSort reads ?sort=field or ?sort=field:desc, rejecting fields not in columns
+func parseSort(c *fiber.Ctx, columns sortableColumns) (listSort, error) {
+	raw := c.Query("sort")
+	if raw == "" {
+		return listSort{}, nil
+	}
+
+	field, direction, _ := strings.Cut(raw, ":")
+	column, ok := columns[strings.ToLower(strings.TrimSpace(field))]
+	if !ok {
+		return listSort{}, fmt.Errorf("sort must be one of %s", strings.Join(slices.Sorted(maps.Keys(columns)), ", "))
+	}
+
+	switch strings.ToLower(strings.TrimSpace(direction)) {
+	case "", "asc":
+		return listSort{Column: column}, nil
+	case "desc":
+		return listSort{Column: column, Desc: true}, nil
+	default:
+		return listSort{}, fmt.Errorf("sort direction must be asc or desc")
+	}
+}
+
+// apply orders query by the requested column, breaking ties by id so pages are stable
+func (s listSort) apply(query *gorm.DB) *gorm.DB {
+	if s.Column == "" {
+		return query
+	}
+	return query.Order(clause.OrderByColumn{Column: clause.Column{Name: s.Column}, Desc: s.Desc}).Order("id")
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestParseSort(t *testing.T) {
+	app := fiber.New()
+	var got listSort
+	app.Get("/", func(c *fiber.Ctx) error {
+		var err error
+		got, err = parseSort(c, menuItemSortColumns)
+		if err != nil {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	tests := []struct {
+		query string
+		want  listSort
+		ok    bool
+	}{
+		{"", listSort{}, true},
+		{"sort=price", listSort{Column: "price"}, true},
+		{"sort=Name:DESC", listSort{Column: "name", Desc: true}, true},
+		{"sort=created_at:asc", listSort{Column: "created_at"}, true},
+		{"sort=password", listSort{}, false},
+		{"sort=price;DROP%20TABLE%20users", listSort{}, false},
+		{"sort=price:sideways", listSort{}, false},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest("GET", "/?"+tt.query, nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		if ok := resp.StatusCode == fiber.StatusOK; ok != tt.ok {
+			t.Fatalf("%q: expected ok=%t, got status %d", tt.query, tt.ok, resp.StatusCode)
+		}
+		if tt.ok && got != tt.want {
+			t.Fatalf("%q: expected %+v, got %+v", tt.query, tt.want, got)
+		}
+	}
+}
+
+func TestGetPublicMenuItemsSortsByRequestedColumn(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_menusort", Password: "x", Email: "menusort@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Menu Sort Restaurant"}
+	database.DB.Create(&restaurant)
+	for _, item := range []models.MenuItem{
+		{RestaurantID: restaurant.ID, Name: "Pasta", Price: 1250},
+		{RestaurantID: restaurant.ID, Name: "Bread", Price: 300},
+		{RestaurantID: restaurant.ID, Name: "Steak", Price: 2800},
+	} {
+		database.DB.Create(&item)
+	}
+
+	app := fiber.New()
+	app.Get("/restaurants/:restaurant_id/menu", GetPublicMenuItems)
+
+	for query, want := range map[string][]string{
+		"sort=price":      {"Bread", "Pasta", "Steak"},
+		"sort=price:desc": {"Steak", "Pasta", "Bread"},
+		"sort=name":       {"Bread", "Pasta", "Steak"},
+	} {
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurants/%d/menu?%s", restaurant.ID, query), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data []PublicMenuItem `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decoding response: %v", err)
+		}
+		var names []string
+		for _, item := range body.Data {
+			names = append(names, item.Name)
+		}
+		if fmt.Sprint(names) != fmt.Sprint(want) {
+			t.Fatalf("%q: expected %v, got %v", query, want, names)
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurants/%d/menu?sort=quantity", restaurant.ID), nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("expected 400 for a non-whitelisted sort field, got %d", resp.StatusCode)
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// recordStockMovement appends a ledger entry for a change of delta to item, whose Quantity must
+// already hold the balance after the change. Zero changes are not recorded.
+func recordStockMovement(tx *gorm.DB, item *models.MenuItem, movementType string, delta int, orderID, adjustmentID *uint, note string) error {
+	if delta == 0 {
+		return nil
+	}
+	return tx.Create(&models.StockMovement{
+		RestaurantID:  item.RestaurantID,
+		MenuItemID:    item.ID,
+		Type:          movementType,
+		Delta:         delta,
+		QuantityAfter: item.Quantity,
+		OrderID:       orderID,
+		AdjustmentID:  adjustmentID,
+		Note:          note,
+	}).Error
+}
+
+// GetStockHistory godoc
+// @Summary Get a menu item's stock history
+// @Description Get a page of the menu item's stock ledger in chronological order: initial stock, orders, cancelled orders, adjustments and manual edits, each with the running balance
+// @Tags Menu
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param id path string true "Item ID"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Number of entries to skip"
+// @Success 200 {object} PageEnvelope[StockMovementEntry]
+// @Failure 400 {object} ErrorEnvelope "Invalid pagination parameters"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or menu item not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving stock history"
+// @Router /api/restaurant/{restaurant_id}/menu/{id}/stock-history [get]
+func GetStockHistory(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+	itemID := c.Params("id")
+
+	page, err := parsePagination(c)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid pagination parameters: "+err.Error())
+	}
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	// Deleted items keep their history
+	var menuItem models.MenuItem
+	if err := db(c).Unscoped().Where("id = ? AND restaurant_id = ?", itemID, restaurant.ID).First(&menuItem).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeMenuItemNotFound, "Menu item not found")
+	}
+
+	query := db(c).Model(&models.StockMovement{}).Where("menu_item_id = ? AND restaurant_id = ?", menuItem.ID, restaurant.ID)
+	if err := query.Count(&page.Total).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving stock history")
+	}
+
+	var movements []models.StockMovement
+	if err := query.Order("created_at, id").Limit(page.Limit).Offset(page.Offset).Find(&movements).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving stock history")
+	}
+
+	entries := make([]StockMovementEntry, 0, len(movements))
+	for _, movement := range movements {
+		entries = append(entries, StockMovementEntry{
+			ID:           movement.ID,
+			Type:         movement.Type,
+			Delta:        movement.Delta,
+			Balance:      movement.QuantityAfter,
+			OrderID:      movement.OrderID,
+			AdjustmentID: movement.AdjustmentID,
+			Note:         movement.Note,
+			CreatedAt:    movement.CreatedAt,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"success":    true,
+		"data":       entries,
+		"pagination": page,
+		"error":      nil,
+	})
+}
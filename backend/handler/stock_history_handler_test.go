@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/constants"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetStockHistoryListsTheItemsMovementsInOrder(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_stock_history", Password: "x", Email: "stockhistory@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	stranger := models.User{Username: "testuser_stock_stranger", Password: "x", Email: "stockstranger@example.com"}
+	database.DB.Create(&stranger)
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Stock History Restaurant"}
+	database.DB.Create(&restaurant)
+	other := models.Restaurant{UserID: stranger.ID, Name: "Other Stock History Restaurant"}
+	database.DB.Create(&other)
+	soup := models.MenuItem{RestaurantID: restaurant.ID, Name: "Soup", Price: 400, Quantity: 13}
+	pasta := models.MenuItem{RestaurantID: restaurant.ID, Name: "Pasta", Price: 900, Quantity: 4}
+	fish := models.MenuItem{RestaurantID: other.ID, Name: "Fish", Price: 1800, Quantity: 6}
+	for _, item := range []*models.MenuItem{&soup, &pasta, &fish} {
+		database.DB.Create(item)
+	}
+
+	// Stored out of order, so the response order can't come from insertion order
+	start := time.Date(2024, time.March, 5, 9, 0, 0, 0, time.Local)
+	orderID := uint(42)
+	movements := []models.StockMovement{
+		{RestaurantID: restaurant.ID, MenuItemID: soup.ID, Type: constants.StockMovementManual, Delta: 5, QuantityAfter: 13, CreatedAt: start.Add(2 * time.Hour)},
+		{RestaurantID: restaurant.ID, MenuItemID: soup.ID, Type: constants.StockMovementOrder, Delta: -2, QuantityAfter: 8, OrderID: &orderID, CreatedAt: start.Add(time.Hour)},
+		{RestaurantID: restaurant.ID, MenuItemID: pasta.ID, Type: constants.StockMovementOrder, Delta: -1, QuantityAfter: 4, CreatedAt: start.Add(time.Hour)},
+		{RestaurantID: other.ID, MenuItemID: soup.ID, Type: constants.StockMovementManual, Delta: 99, QuantityAfter: 99, CreatedAt: start.Add(30 * time.Minute)},
+		{RestaurantID: other.ID, MenuItemID: fish.ID, Type: constants.StockMovementManual, Delta: 6, QuantityAfter: 6, CreatedAt: start},
+		{RestaurantID: restaurant.ID, MenuItemID: soup.ID, Type: constants.StockMovementManual, Delta: 10, QuantityAfter: 10, CreatedAt: start},
+	}
+	if err := database.DB.Create(&movements).Error; err != nil {
+		t.Fatalf("creating stock movements: %v", err)
+	}
+
+	app := fiber.New()
+	app.Get("/restaurant/:restaurant_id/menu/:id/stock-history", func(c *fiber.Ctx) error {
+		c.Locals("username", user.Username)
+		return GetStockHistory(c)
+	})
+	history := func(itemID uint, query string) (int, []StockMovementEntry, int64) {
+		resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurant/%d/menu/%d/stock-history%s", restaurant.ID, itemID, query), nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		var body struct {
+			Data       []StockMovementEntry `json:"data"`
+			Pagination struct {
+				Total int64 `json:"total"`
+			} `json:"pagination"`
+		}
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Data, body.Pagination.Total
+	}
+
+	status, entries, total := history(soup.ID, "")
+	if status != fiber.StatusOK {
+		t.Fatalf("expected 200, got %d", status)
+	}
+	if total != 3 || len(entries) != 3 {
+		t.Fatalf("expected the soup's 3 movements in this restaurant, got %d of %d: %+v", len(entries), total, entries)
+	}
+	for i, want := range []struct{ delta, balance int }{{10, 10}, {-2, 8}, {5, 13}} {
+		if entries[i].Delta != want.delta || entries[i].Balance != want.balance {
+			t.Fatalf("expected movement %d to be %+d leaving %d, got %+v", i, want.delta, want.balance, entries[i])
+		}
+		if i > 0 && entries[i].CreatedAt.Before(entries[i-1].CreatedAt) {
+			t.Fatalf("expected chronological order, got %s before %s", entries[i-1].CreatedAt, entries[i].CreatedAt)
+		}
+	}
+	if entries[1].OrderID == nil || *entries[1].OrderID != orderID {
+		t.Fatalf("expected the order movement to reference order %d, got %v", orderID, entries[1].OrderID)
+	}
+
+	// Pages keep the same order
+	if _, entries, total := history(soup.ID, "?limit=2&offset=1"); total != 3 || len(entries) != 2 || entries[0].Delta != -2 || entries[1].Delta != 5 {
+		t.Fatalf("expected the second page to hold the last two movements, got %+v of %d", entries, total)
+	}
+
+	// Another restaurant's item isn't found through this restaurant
+	if status, _, _ := history(fish.ID, ""); status != fiber.StatusNotFound {
+		t.Fatalf("expected 404 for another restaurant's item, got %d", status)
+	}
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetStorefront godoc
+// @Summary Get a restaurant's storefront
+// @Description Get everything the customer ordering page needs in one call without authentication: the restaurant with its tables, its public settings, the featured items and the full menu
+// @Tags Restaurant
+// @Produce json
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param If-None-Match header string false "ETag from an earlier response"
+// @Success 200 {object} Envelope[Storefront]
+// @Success 304 {string} string "Storefront unchanged since the given ETag"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving storefront"
+// @Router /api/restaurants/{restaurant_id}/storefront [get]
+func GetStorefront(c *fiber.Ctx) error {
+	restaurantID := c.Params("restaurant_id")
+
+	var restaurant models.Restaurant
+	if err := readDB(c).Where("id = ?", restaurantID).Preload("Tables").First(&restaurant).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	settings, err := loadRestaurantSettings(readDB(c), restaurant.ID)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving storefront")
+	}
+
+	// The unfiltered menu in category order, shared with GET /menu through the menu cache
+	cacheKey := newMenuCacheKey(nil, listSort{})
+	menu, generation, ok := globalMenuCache.get(restaurant.ID, cacheKey)
+	if !ok {
+		// From the primary, like GetPublicMenu, so the cache never holds a lagging replica's menu
+		menu, err = loadPublicMenu(db(c), restaurant.ID, nil, listSort{})
+		if err != nil {
+			return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving storefront")
+		}
+		globalMenuCache.set(restaurant.ID, cacheKey, generation, menu)
+	}
+
+	return sendWithETag(c, Storefront{
+		Restaurant: restaurant,
+		Settings: StorefrontSettings{
+			Currency:              settings.Currency,
+			TaxRate:               settings.TaxRate,
+			OperatingHoursEnabled: settings.OperatingHoursEnabled,
+			MinOrderAmount:        settings.MinOrderAmount,
+		},
+		Featured: featuredItems(menu),
+		Menu:     menu,
+	})
+}
+
+// featuredItems picks the featured items out of a public menu in the order
+// GetFeaturedMenuItems returns them
+func featuredItems(menu []PublicMenuItem) []PublicMenuItem {
+	featured := []PublicMenuItem{}
+	for _, item := range menu {
+		if item.IsFeatured {
+			featured = append(featured, item)
+		}
+	}
+	sort.SliceStable(featured, func(i, j int) bool {
+		if featured[i].FeaturedOrder != featured[j].FeaturedOrder {
+			return featured[i].FeaturedOrder < featured[j].FeaturedOrder
+		}
+		return featured[i].ID < featured[j].ID
+	})
+	if len(featured) > maxFeaturedItems {
+		featured = featured[:maxFeaturedItems]
+	}
+	return featured
+}
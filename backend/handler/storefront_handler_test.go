@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"order-system/database"
+	"order-system/models"
+	"order-system/testutil"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestGetStorefrontReturnsRestaurantSettingsAndMenu(t *testing.T) {
+	testutil.SetupDB(t)
+
+	user := models.User{Username: "testuser_storefront", Password: "x", Email: "storefront@example.com"}
+	if err := database.DB.Create(&user).Error; err != nil {
+		t.Fatalf("creating user: %v", err)
+	}
+	restaurant := models.Restaurant{UserID: user.ID, Name: "Storefront Restaurant"}
+	database.DB.Create(&restaurant)
+	database.DB.Create(&models.Table{RestaurantID: restaurant.ID, TableNumber: 1})
+	database.DB.Create(&models.RestaurantSettings{RestaurantID: restaurant.ID, Currency: "EUR", TaxRate: 7, LowStockThreshold: 5})
+	items := []models.MenuItem{
+		{RestaurantID: restaurant.ID, Name: "Soup", Price: 500, Quantity: 3},
+		{RestaurantID: restaurant.ID, Name: "Cake", Price: 400, IsFeatured: true, FeaturedOrder: 2},
+		{RestaurantID: restaurant.ID, Name: "Pie", Price: 450, IsFeatured: true, FeaturedOrder: 1},
+	}
+	database.DB.Create(&items)
+
+	app := fiber.New()
+	app.Get("/restaurants/:restaurant_id/storefront", GetStorefront)
+
+	resp, err := app.Test(httptest.NewRequest("GET", fmt.Sprintf("/restaurants/%d/storefront", restaurant.ID), nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+	var body struct {
+		Data Storefront `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	storefront := body.Data
+
+	if storefront.Restaurant.Name != restaurant.Name || len(storefront.Restaurant.Tables) != 1 {
+		t.Fatalf("expected the restaurant with its table, got %q with %d tables", storefront.Restaurant.Name, len(storefront.Restaurant.Tables))
+	}
+	if storefront.Settings.Currency != "EUR" || storefront.Settings.TaxRate != 7 {
+		t.Fatalf("expected the saved settings, got %+v", storefront.Settings)
+	}
+	if len(storefront.Menu) != 3 {
+		t.Fatalf("expected 3 menu items, got %d", len(storefront.Menu))
+	}
+	if len(storefront.Featured) != 2 || storefront.Featured[0].Name != "Pie" || storefront.Featured[1].Name != "Cake" {
+		t.Fatalf("expected Pie then Cake as featured items, got %+v", storefront.Featured)
+	}
+
+	resp, err = app.Test(httptest.NewRequest("GET", "/restaurants/999999/storefront", nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("unknown restaurant: expected status 404, got %d", resp.StatusCode)
+	}
+}
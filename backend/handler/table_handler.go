@@ -1,29 +1,109 @@
 package handler
 
 import (
+	"errors"
 	"fmt"
+	"log"
+	"order-system/constants"
 	"order-system/database"
 	"order-system/models"
 	"order-system/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
 )
 
+var (
+	// ErrRestaurantNotFound means the restaurant (or the requesting user) does not exist
+	ErrRestaurantNotFound = errors.New("restaurant not found")
+	// ErrRestaurantForbidden means the restaurant exists but belongs to another user
+	ErrRestaurantForbidden = errors.New("restaurant belongs to another user")
+)
+
+// OwnershipError is returned by verifyRestaurantOwnership. Clients always get a 404 so
+// restaurant IDs can't be enumerated, but Err tells not-found and forbidden apart for logs.
+type OwnershipError struct {
+	Username     string
+	RestaurantID uint
+	Err          error // ErrRestaurantNotFound, ErrRestaurantForbidden or a database error
+}
+
+func (e *OwnershipError) Error() string {
+	return fmt.Sprintf("user %s cannot access restaurant %d: %v", e.Username, e.RestaurantID, e.Err)
+}
+
+func (e *OwnershipError) Unwrap() error {
+	return e.Err
+}
+
 // verifyRestaurantOwnership checks if the restaurant belongs to the user
-func verifyRestaurantOwnership(username string, restaurantID uint) (*models.Restaurant, error) {
+func verifyRestaurantOwnership(c *fiber.Ctx, username string, restaurantID uint) (*models.Restaurant, error) {
+	ownershipError := func(err error) error {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = ErrRestaurantNotFound
+		}
+		return &OwnershipError{Username: username, RestaurantID: restaurantID, Err: err}
+	}
+
 	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
-		return nil, err
+	if err := db(c).Where("username = ?", username).First(&user).Error; err != nil {
+		return nil, ownershipError(err)
 	}
 
 	var restaurant models.Restaurant
-	if err := database.DB.Where("id = ? AND user_id = ?", restaurantID, user.ID).First(&restaurant).Error; err != nil {
-		return nil, err
+	if err := db(c).First(&restaurant, restaurantID).Error; err != nil {
+		return nil, ownershipError(err)
+	}
+	if restaurant.UserID != user.ID {
+		return nil, ownershipError(ErrRestaurantForbidden)
 	}
 
 	return &restaurant, nil
 }
 
+// logOwnershipFailure records why an ownership check failed. Plain not-found is expected
+// traffic; access to another user's restaurant or a database failure is worth a look.
+func logOwnershipFailure(err error) {
+	if errors.Is(err, ErrRestaurantNotFound) {
+		return
+	}
+	log.Println("restaurant ownership check failed:", err)
+}
+
+// generateTableQRCode builds the QR code image for a table's ordering page, falling back to an external URL
+func generateTableQRCode(restaurantID, tableID uint) string {
+	frontendURL := fmt.Sprintf("http://localhost:5173/restaurant/%d/table/%d", restaurantID, tableID)
+
+	qrCode, err := utils.GenerateQRCode(frontendURL)
+	if err != nil {
+		// Log error but don't fail the operation
+		fmt.Println("Error generating QR code:", err)
+		// Set a fallback QR code URL if generation fails
+		return utils.GenerateFallbackQRCode(frontendURL)
+	}
+	return qrCode
+}
+
+// BackfillTableQRCodes generates and stores QR codes for tables saved without one.
+// It runs once at startup so read endpoints never have to generate them.
+func BackfillTableQRCodes() {
+	var tables []models.Table
+	if err := database.DB.Where("qr_code_url = '' OR qr_code_url IS NULL").Find(&tables).Error; err != nil {
+		log.Println("failed to load tables missing QR codes:", err)
+		return
+	}
+
+	for _, table := range tables {
+		qrCodeURL := generateTableQRCode(table.RestaurantID, table.ID)
+		if err := database.DB.Model(&table).Update("qr_code_url", qrCodeURL).Error; err != nil {
+			log.Printf("failed to store QR code for table %d: %v", table.ID, err)
+		}
+	}
+	if len(tables) > 0 {
+		log.Printf("Backfilled QR codes for %d tables", len(tables))
+	}
+}
+
 // CreateTable godoc
 // @Summary Create a new table
 // @Description Create a new table for a restaurant
@@ -33,22 +113,20 @@ func verifyRestaurantOwnership(username string, restaurantID uint) (*models.Rest
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param table body Table true "Table data"
-// @Success 201 {object} Table
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "Restaurant not found"
-// @Failure 500 {string} string "Error creating table"
+// @Success 201 {object} Envelope[Table]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 409 {object} ErrorEnvelope "Duplicate value"
+// @Failure 500 {object} ErrorEnvelope "Error creating table"
 // @Router /api/restaurant/{restaurant_id}/table [post]
 func CreateTable(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var request struct {
@@ -56,11 +134,11 @@ func CreateTable(c *fiber.Ctx) error {
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
+	}
+
+	if request.TableNumber <= 0 {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Table number must be a positive integer")
 	}
 
 	table := models.Table{
@@ -68,32 +146,24 @@ func CreateTable(c *fiber.Ctx) error {
 		TableNumber:  request.TableNumber,
 	}
 
-	if err := database.DB.Create(&table).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error creating table",
-		})
+	if err := db(c).Create(&table).Error; err != nil {
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error creating table"
+		}
+		return utils.SendError(c, status, code, message)
 	}
 
 	// After creating the table, generate the QR code image
-	frontendURL := fmt.Sprintf("http://localhost:5173/restaurant/%d/table/%d", restaurant.ID, table.ID)
-
-	qrCode, err := utils.GenerateQRCode(frontendURL)
-	if err != nil {
-		// Log error but don't fail the operation
-		fmt.Println("Error generating QR code:", err)
-		// Set a fallback QR code URL if generation fails
-		table.QRCodeURL = utils.GenerateFallbackQRCode(frontendURL)
-	} else {
-		table.QRCodeURL = qrCode
-	}
+	table.QRCodeURL = generateTableQRCode(restaurant.ID, table.ID)
 
-	if err := database.DB.Save(&table).Error; err != nil {
+	if err := db(c).Save(&table).Error; err != nil {
 		// Log error but don't fail the operation
 		fmt.Println("Error updating table with QR code URL:", err)
 	}
 
+	recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityTable, table.ID, fmt.Sprintf("Created table %d", table.TableNumber))
+
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"success": true,
 		"data":    table,
@@ -108,30 +178,23 @@ func CreateTable(c *fiber.Ctx) error {
 // @Produce json
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
-// @Success 200 {array} Table
-// @Failure 404 {string} string "Restaurant not found"
-// @Failure 500 {string} string "Error retrieving tables"
+// @Success 200 {object} Envelope[[]Table]
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving tables"
 // @Router /api/restaurant/{restaurant_id}/table [get]
 func GetTables(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var tables []models.Table
-	if err := database.DB.Where("restaurant_id = ?", restaurant.ID).Find(&tables).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving tables",
-		})
+	if err := db(c).Where("restaurant_id = ?", restaurant.ID).Find(&tables).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving tables")
 	}
 
 	return c.JSON(fiber.Map{
@@ -151,69 +214,59 @@ func GetTables(c *fiber.Ctx) error {
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param id path string true "Table ID"
 // @Param table body Table true "Table data"
-// @Success 200 {object} Table
-// @Failure 400 {string} string "Invalid input"
-// @Failure 404 {string} string "Restaurant or table not found"
-// @Failure 500 {string} string "Error updating table"
+// @Success 200 {object} Envelope[Table]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant or table not found"
+// @Failure 409 {object} ErrorEnvelope "Duplicate value"
+// @Failure 500 {object} ErrorEnvelope "Error updating table"
 // @Router /api/restaurant/{restaurant_id}/table/{id} [put]
 func UpdateTable(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 	tableID := c.Params("id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var table models.Table
-	if err := database.DB.Where("id = ? AND restaurant_id = ?", tableID, restaurant.ID).First(&table).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Table not found",
-		})
+	if err := db(c).Where("id = ? AND restaurant_id = ?", tableID, restaurant.ID).First(&table).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeTableNotFound, "Table not found")
 	}
 
 	var request struct {
-		TableNumber int `json:"table_number"`
+		TableNumber  int  `json:"table_number"`
+		RegenerateQR bool `json:"regenerate_qr"` // Force a new QR code, e.g. after the frontend base URL changed
 	}
 
 	if err := c.BodyParser(&request); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Invalid input",
-		})
+		return utils.SendBodyParseError(c, err)
 	}
 
-	table.TableNumber = request.TableNumber
+	if request.TableNumber <= 0 {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Table number must be a positive integer")
+	}
 
-	// Don't allow updating QRCodeURL from the frontend, regenerate it if necessary
-	frontendURL := fmt.Sprintf("http://localhost:5173/restaurant/%d/table/%d", restaurant.ID, table.ID)
+	table.TableNumber = request.TableNumber
 
-	qrCode, err := utils.GenerateQRCode(frontendURL)
-	if err != nil {
-		// Log error but don't fail the operation
-		fmt.Println("Error generating QR code:", err)
-		// Set a fallback QR code URL if generation fails
-		table.QRCodeURL = utils.GenerateFallbackQRCode(frontendURL)
-	} else {
-		table.QRCodeURL = qrCode
+	// Don't allow updating QRCodeURL from the frontend. The encoded URL only depends on the
+	// restaurant and table IDs, so it's generated only when missing or explicitly requested.
+	if table.QRCodeURL == "" || request.RegenerateQR {
+		table.QRCodeURL = generateTableQRCode(restaurant.ID, table.ID)
 	}
 
-	if err := database.DB.Save(&table).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error updating table",
-		})
+	if err := db(c).Save(&table).Error; err != nil {
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error updating table"
+		}
+		return utils.SendError(c, status, code, message)
 	}
 
+	recordAudit(c, restaurant, constants.AuditActionUpdate, constants.AuditEntityTable, table.ID, fmt.Sprintf("Updated table %d", table.TableNumber))
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"data":    table,
@@ -221,6 +274,119 @@ func UpdateTable(c *fiber.Ctx) error {
 	})
 }
 
+// maxBatchTables caps how many tables a single batch request may create
+const maxBatchTables = 200
+
+// CreateTablesBatch godoc
+// @Summary Create tables in batch
+// @Description Create several tables at once from a start/count range or an explicit list of numbers, skipping numbers that already exist
+// @Tags Table
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param restaurant_id path string true "Restaurant ID"
+// @Param tables body BatchTableRequest true "Table numbers to create"
+// @Success 201 {object} Envelope[BatchTableResponse]
+// @Failure 400 {object} ErrorEnvelope "Invalid input"
+// @Failure 404 {object} ErrorEnvelope "Restaurant not found"
+// @Failure 409 {object} ErrorEnvelope "Duplicate value"
+// @Failure 500 {object} ErrorEnvelope "Error creating tables"
+// @Router /api/restaurant/{restaurant_id}/tables/batch [post]
+func CreateTablesBatch(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+	restaurantID := c.Params("restaurant_id")
+
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
+	if err != nil {
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
+	}
+
+	var request BatchTableRequest
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+
+	// Either an explicit list of numbers or a start/count range
+	numbers := request.Numbers
+	if len(numbers) == 0 {
+		if request.Count <= 0 || request.Count > maxBatchTables {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, fmt.Sprintf("Count must be between 1 and %d", maxBatchTables))
+		}
+		for i := 0; i < request.Count; i++ {
+			numbers = append(numbers, request.Start+i)
+		}
+	}
+
+	if len(numbers) > maxBatchTables {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, fmt.Sprintf("Cannot create more than %d tables at once", maxBatchTables))
+	}
+	for _, number := range numbers {
+		if number <= 0 {
+			return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Table number must be a positive integer")
+		}
+	}
+
+	response := BatchTableResponse{
+		Created: []models.Table{},
+		Skipped: []int{},
+	}
+
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		var existingNumbers []int
+		if err := tx.Model(&models.Table{}).
+			Where("restaurant_id = ? AND table_number IN ?", restaurant.ID, numbers).
+			Pluck("table_number", &existingNumbers).Error; err != nil {
+			return err
+		}
+
+		seen := make(map[int]struct{}, len(numbers))
+		for _, number := range existingNumbers {
+			seen[number] = struct{}{}
+		}
+
+		for _, number := range numbers {
+			if _, exists := seen[number]; exists {
+				response.Skipped = append(response.Skipped, number)
+				continue
+			}
+			seen[number] = struct{}{}
+
+			table := models.Table{
+				RestaurantID: restaurant.ID,
+				TableNumber:  number,
+			}
+			if err := tx.Create(&table).Error; err != nil {
+				return err
+			}
+
+			table.QRCodeURL = generateTableQRCode(restaurant.ID, table.ID)
+			if err := tx.Save(&table).Error; err != nil {
+				return err
+			}
+
+			response.Created = append(response.Created, table)
+		}
+		return nil
+	}); err != nil {
+		status, code, message := utils.MapDBError(err)
+		if status == fiber.StatusInternalServerError {
+			message = "Error creating tables"
+		}
+		return utils.SendError(c, status, code, message)
+	}
+
+	for _, table := range response.Created {
+		recordAudit(c, restaurant, constants.AuditActionCreate, constants.AuditEntityTable, table.ID, fmt.Sprintf("Created table %d", table.TableNumber))
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"success": true,
+		"data":    response,
+		"error":   nil,
+	})
+}
+
 // DeleteTable godoc
 // @Summary Delete a table
 // @Description Delete a table
@@ -229,41 +395,32 @@ func UpdateTable(c *fiber.Ctx) error {
 // @Security BearerAuth
 // @Param restaurant_id path string true "Restaurant ID"
 // @Param id path string true "Table ID"
-// @Success 200 {object} string
-// @Failure 404 {string} string "Restaurant or table not found"
-// @Failure 500 {string} string "Error deleting table"
+// @Success 200 {object} Envelope[string]
+// @Failure 404 {object} ErrorEnvelope "Restaurant or table not found"
+// @Failure 500 {object} ErrorEnvelope "Error deleting table"
 // @Router /api/restaurant/{restaurant_id}/table/{id} [delete]
 func DeleteTable(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 	restaurantID := c.Params("restaurant_id")
 	tableID := c.Params("id")
 
-	restaurant, err := verifyRestaurantOwnership(username, parseUint(restaurantID))
+	restaurant, err := verifyRestaurantOwnership(c, username, parseUint(restaurantID))
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Restaurant not found",
-		})
+		logOwnershipFailure(err)
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeRestaurantNotFound, "Restaurant not found")
 	}
 
 	var table models.Table
-	if err := database.DB.Where("id = ? AND restaurant_id = ?", tableID, restaurant.ID).First(&table).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Table not found",
-		})
+	if err := db(c).Where("id = ? AND restaurant_id = ?", tableID, restaurant.ID).First(&table).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeTableNotFound, "Table not found")
 	}
 
-	if err := database.DB.Delete(&table).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error deleting table",
-		})
+	if err := db(c).Delete(&table).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error deleting table")
 	}
 
+	recordAudit(c, restaurant, constants.AuditActionDelete, constants.AuditEntityTable, table.ID, fmt.Sprintf("Deleted table %d", table.TableNumber))
+
 	return c.JSON(fiber.Map{
 		"success": true,
 		"data":    "Table deleted successfully",
@@ -273,34 +430,34 @@ func DeleteTable(c *fiber.Ctx) error {
 
 // GetAllUserTables godoc
 // @Summary Get all user tables
-// @Description Get all tables for all restaurants belonging to the user
+// @Description Get a page of tables across all restaurants belonging to the user, with the total count in pagination
 // @Tags Table
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {array} Table
-// @Failure 404 {string} string "User not found"
-// @Failure 500 {string} string "Error retrieving tables"
+// @Param limit query int false "Page size (default 50, max 200)"
+// @Param offset query int false "Number of tables to skip"
+// @Success 200 {object} PageEnvelope[Table]
+// @Failure 400 {object} ErrorEnvelope "Invalid pagination parameters"
+// @Failure 404 {object} ErrorEnvelope "User not found"
+// @Failure 500 {object} ErrorEnvelope "Error retrieving tables"
 // @Router /api/table [get]
 func GetAllUserTables(c *fiber.Ctx) error {
 	username := c.Locals("username").(string)
 
+	page, err := parsePagination(c)
+	if err != nil {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidQuery, "Invalid pagination parameters: "+err.Error())
+	}
+
 	var user models.User
-	if err := database.DB.Where("username = ?", username).First(&user).Error; err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "User not found",
-		})
+	if err := db(c).Where("username = ?", username).First(&user).Error; err != nil {
+		return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeUserNotFound, "User not found")
 	}
 
 	// Get all restaurants for the user
 	var restaurants []models.Restaurant
-	if err := database.DB.Where("user_id = ?", user.ID).Find(&restaurants).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving restaurants",
-		})
+	if err := db(c).Where("user_id = ?", user.ID).Find(&restaurants).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving restaurants")
 	}
 
 	// Extract restaurant IDs
@@ -312,44 +469,33 @@ func GetAllUserTables(c *fiber.Ctx) error {
 	// If user has no restaurants, return empty array
 	if len(restaurantIDs) == 0 {
 		return c.JSON(fiber.Map{
-			"success": true,
-			"data":    []models.Table{},
-			"error":   nil,
+			"success":    true,
+			"data":       []models.Table{},
+			"pagination": page,
+			"error":      nil,
 		})
 	}
 
-	// Get all tables for these restaurants
+	// Get one page of tables for these restaurants
+	query := db(c).Model(&models.Table{}).Where("restaurant_id IN ?", restaurantIDs)
+	if err := query.Count(&page.Total).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving tables")
+	}
+
 	var tables []models.Table
-	if err := database.DB.Where("restaurant_id IN ?", restaurantIDs).Find(&tables).Error; err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"success": false,
-			"data":    nil,
-			"error":   "Error retrieving tables",
-		})
+	if err := query.Order("restaurant_id, table_number").Limit(page.Limit).Offset(page.Offset).Find(&tables).Error; err != nil {
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error retrieving tables")
 	}
 
 	// Enhance table data with restaurant information
-	var tablesWithRestaurantInfo []map[string]interface{}
+	tablesWithRestaurantInfo := []map[string]interface{}{}
 	for _, table := range tables {
-		// Regenerate QR code if the stored URL is empty or not base64 encoded
-		qrCodeURL := table.QRCodeURL
-		if qrCodeURL == "" {
-			frontendURL := fmt.Sprintf("http://localhost:5173/restaurant/%d/table/%d", table.RestaurantID, table.ID)
-			qrCode, err := utils.GenerateQRCode(frontendURL)
-			if err != nil {
-				// Log error and use fallback
-				fmt.Println("Error generating QR code:", err)
-				qrCodeURL = utils.GenerateFallbackQRCode(frontendURL)
-			} else {
-				qrCodeURL = qrCode
-			}
-		}
-
+		// QR codes are generated on create/update and backfilled at startup, never while listing
 		tableMap := map[string]interface{}{
 			"ID":           table.ID,
 			"RestaurantID": table.RestaurantID,
 			"TableNumber":  table.TableNumber,
-			"QRCodeURL":    qrCodeURL,
+			"QRCodeURL":    table.QRCodeURL,
 		}
 
 		// Find the restaurant for this table to add its name
@@ -364,9 +510,10 @@ func GetAllUserTables(c *fiber.Ctx) error {
 	}
 
 	return c.JSON(fiber.Map{
-		"success": true,
-		"data":    tablesWithRestaurantInfo,
-		"error":   nil,
+		"success":    true,
+		"data":       tablesWithRestaurantInfo,
+		"pagination": page,
+		"error":      nil,
 	})
 }
 
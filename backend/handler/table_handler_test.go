@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOwnershipErrorDistinguishesForbiddenFromNotFound(t *testing.T) {
+	var err error = &OwnershipError{Username: "tester", RestaurantID: 7, Err: ErrRestaurantForbidden}
+
+	if !errors.Is(err, ErrRestaurantForbidden) {
+		t.Fatal("expected error to match ErrRestaurantForbidden")
+	}
+	if errors.Is(err, ErrRestaurantNotFound) {
+		t.Fatal("forbidden error must not match ErrRestaurantNotFound")
+	}
+
+	var ownershipErr *OwnershipError
+	if !errors.As(err, &ownershipErr) || ownershipErr.RestaurantID != 7 {
+		t.Fatalf("expected OwnershipError for restaurant 7, got %v", err)
+	}
+	if got := err.Error(); got != "user tester cannot access restaurant 7: restaurant belongs to another user" {
+		t.Fatalf("unexpected message: %s", got)
+	}
+}
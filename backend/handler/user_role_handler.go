@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"order-system/constants"
+	"order-system/models"
+	"order-system/utils"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// errLastAdmin is returned when a role change would leave no admin
+var errLastAdmin = errors.New("cannot demote the last admin")
+
+// UpdateUserRole godoc
+// @Summary Change a user's role
+// @Description Assign the owner, staff or admin role to a user. Only admins may call it, and the last remaining admin can't be demoted.
+// @Tags User
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param role body UpdateUserRoleRequest true "New role"
+// @Success 200 {object} Envelope[User]
+// @Failure 400 {object} ErrorEnvelope "Invalid role"
+// @Failure 403 {object} ErrorEnvelope "Admin role required"
+// @Failure 404 {object} ErrorEnvelope "User not found"
+// @Failure 409 {object} ErrorEnvelope "Cannot demote the last admin"
+// @Failure 500 {object} ErrorEnvelope "Error updating role"
+// @Router /api/user/{id}/role [patch]
+func UpdateUserRole(c *fiber.Ctx) error {
+	username := c.Locals("username").(string)
+
+	var caller models.User
+	if err := db(c).Where("username = ?", username).First(&caller).Error; err != nil || caller.Role != constants.RoleAdmin {
+		return utils.SendError(c, fiber.StatusForbidden, constants.ErrCodeForbidden, "Admin role required")
+	}
+
+	var request UpdateUserRoleRequest
+	if err := c.BodyParser(&request); err != nil {
+		return utils.SendBodyParseError(c, err)
+	}
+	if !slices.Contains(constants.Roles, request.Role) {
+		return utils.SendError(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, "Role must be one of owner, staff or admin")
+	}
+
+	var user models.User
+	var previousRole string
+	if err := db(c).Transaction(func(tx *gorm.DB) error {
+		if err := tx.First(&user, parseUint(c.Params("id"))).Error; err != nil {
+			return err
+		}
+		previousRole = user.Role
+
+		// Lock the admins so two admins demoting each other at once can't both succeed
+		if user.Role == constants.RoleAdmin && request.Role != constants.RoleAdmin {
+			var admins []models.User
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("role = ?", constants.RoleAdmin).
+				Find(&admins).Error; err != nil {
+				return err
+			}
+			if len(admins) <= 1 {
+				return errLastAdmin
+			}
+		}
+
+		return tx.Model(&user).Update("role", request.Role).Error
+	}); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return utils.SendError(c, fiber.StatusNotFound, constants.ErrCodeUserNotFound, "User not found")
+		}
+		if errors.Is(err, errLastAdmin) {
+			return utils.SendError(c, fiber.StatusConflict, constants.ErrCodeLastAdmin, "Cannot demote the last admin")
+		}
+		return utils.SendError(c, fiber.StatusInternalServerError, constants.ErrCodeInternal, "Error updating role")
+	}
+
+	if previousRole != user.Role {
+		recordUserAudit(&caller, constants.AuditActionUpdate, user.ID, fmt.Sprintf("Changed role of %q from %s to %s", user.Username, previousRole, user.Role))
+	}
+
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    User{ID: user.ID, Username: user.Username, Email: user.Email, Role: user.Role},
+		"error":   nil,
+	})
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// listenSettings is where the server accepts connections, loaded from HOST, PORT and the TLS_* variables
+type listenSettings struct {
+	Addr     string
+	CertFile string
+	KeyFile  string
+}
+
+// loadListenSettings reads HOST (default 0.0.0.0) and PORT (default 3000). TLS is enabled when
+// TLS_CERT_FILE and TLS_KEY_FILE are both set; the pair is loaded here so a bad certificate stops
+// startup with a clear error instead of failing on the first handshake.
+func loadListenSettings() (listenSettings, error) {
+	host := os.Getenv("HOST")
+	if host == "" {
+		host = "0.0.0.0"
+	}
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "3000"
+	}
+	settings := listenSettings{
+		Addr:     net.JoinHostPort(host, port),
+		CertFile: os.Getenv("TLS_CERT_FILE"),
+		KeyFile:  os.Getenv("TLS_KEY_FILE"),
+	}
+
+	if (settings.CertFile == "") != (settings.KeyFile == "") {
+		return settings, fmt.Errorf("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+	if settings.TLS() {
+		if _, err := tls.LoadX509KeyPair(settings.CertFile, settings.KeyFile); err != nil {
+			return settings, fmt.Errorf("loading TLS certificate %s and key %s: %w", settings.CertFile, settings.KeyFile, err)
+		}
+	}
+	return settings, nil
+}
+
+// TLS reports whether the server serves HTTPS
+func (s listenSettings) TLS() bool {
+	return s.CertFile != ""
+}
+
+// listen serves app on the configured address until it is shut down
+func (s listenSettings) listen(app *fiber.App) error {
+	if s.TLS() {
+		return app.ListenTLS(s.Addr, s.CertFile, s.KeyFile)
+	}
+	return app.Listen(s.Addr)
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadListenSettingsDefaults(t *testing.T) {
+	t.Setenv("HOST", "")
+	t.Setenv("PORT", "")
+	t.Setenv("TLS_CERT_FILE", "")
+	t.Setenv("TLS_KEY_FILE", "")
+
+	settings, err := loadListenSettings()
+	if err != nil {
+		t.Fatalf("loadListenSettings returned error: %v", err)
+	}
+	if settings.Addr != "0.0.0.0:3000" || settings.TLS() {
+		t.Fatalf("expected plain HTTP on 0.0.0.0:3000, got %+v", settings)
+	}
+
+	t.Setenv("HOST", "::1")
+	t.Setenv("PORT", "8443")
+	if settings, _ := loadListenSettings(); settings.Addr != "[::1]:8443" {
+		t.Fatalf("expected [::1]:8443, got %s", settings.Addr)
+	}
+}
+
+func TestLoadListenSettingsTLS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir)
+
+	t.Setenv("TLS_CERT_FILE", certFile)
+	t.Setenv("TLS_KEY_FILE", "")
+	if _, err := loadListenSettings(); err == nil {
+		t.Fatal("expected an error when only TLS_CERT_FILE is set")
+	}
+
+	t.Setenv("TLS_KEY_FILE", filepath.Join(dir, "missing.key"))
+	if _, err := loadListenSettings(); err == nil {
+		t.Fatal("expected an error for a missing key file")
+	}
+
+	t.Setenv("TLS_KEY_FILE", keyFile)
+	settings, err := loadListenSettings()
+	if err != nil {
+		t.Fatalf("loadListenSettings returned error: %v", err)
+	}
+	if !settings.TLS() {
+		t.Fatal("expected TLS to be enabled")
+	}
+}
+
+// writeTestCertificate writes a self-signed certificate and its key to dir
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	template := x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, &template, &template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("creating certificate: %v", err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("marshaling key: %v", err)
+	}
+
+	certFile := filepath.Join(dir, "server.crt")
+	keyFile := filepath.Join(dir, "server.key")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("writing certificate: %v", err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatalf("writing key: %v", err)
+	}
+	return certFile, keyFile
+}
@@ -1,14 +1,21 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"order-system/database"
-	_ "order-system/docs"
+	"order-system/docs"
+	"order-system/handler"
+	"order-system/utils"
+	"order-system/version"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/swagger"
 	"github.com/joho/godotenv"
@@ -22,58 +29,179 @@ func init() {
 	}
 }
 
+// getEnvMinutes reads a positive number of minutes from the environment, falling back to a default
+func getEnvMinutes(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil || minutes <= 0 {
+		log.Printf("Invalid %s value %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// getEnvSeconds reads a positive number of seconds from the environment, falling back to a default
+func getEnvSeconds(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		log.Printf("Invalid %s value %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// runMigrateCommand handles the arguments after "migrate": "up" (the default) applies pending
+// migrations, "down" reverts the latest one, or the given number of them
+func runMigrateCommand(args []string) error {
+	if len(args) == 0 || args[0] == "up" {
+		return database.Migrate()
+	}
+	if args[0] != "down" || len(args) > 2 {
+		return fmt.Errorf("usage: migrate [up | down [steps]]")
+	}
+	steps := 1
+	if len(args) == 2 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid number of steps %q", args[1])
+		}
+		steps = n
+	}
+	return database.RollbackMigrations(steps)
+}
+
 // @title Order System API
 // @version 1.0
-// @description API for Order System with user authentication and restaurant management
-// @host localhost:3000
+// @description API for Order System with user authentication and restaurant management. Protected endpoints read the access_token cookie set by POST /api/user/login, which the browser sends when these docs are served by the API they describe; Swagger 2.0 can't describe cookie auth, so only the header fallback appears below.
 // @BasePath /
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @description Fallback for clients without cookies: "Bearer " followed by the access_token cookie's value
 
 func main() {
+	build := version.Get()
+	log.Printf("Order System %s (commit %s, built %s)", build.Version, build.Commit, build.BuildTime)
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "3000"
+	// "migrate [up | down [steps]]" applies or reverts schema migrations and exits
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		database.Connect()
+		if err := runMigrateCommand(os.Args[2:]); err != nil {
+			log.Fatal("Migration failed: ", err)
+		}
+		return
 	}
+
 	database.ConnectDB()
-	app := fiber.New()
-
-	// CORS configuration - security: cannot use wildcard with credentials
-	corsOrigins := os.Getenv("CORS_ORIGINS")
-	allowOrigins := []string{}
-	if corsOrigins == "" {
-		// In development, you can set specific origins
-		// For production, always define specific origins
-		allowOrigins = []string{
-			"http://localhost:5173", // Vite default port
-			"http://localhost:3000", // Common React port
-			"http://localhost:3001", // Alternative port
+
+	// "seed" loads the demo data and exits; SEED_DATA=true loads it before serving
+	seedOnly := len(os.Args) > 1 && os.Args[1] == "seed"
+	if seedOnly || os.Getenv("SEED_DATA") == "true" {
+		if err := database.SeedDemoData(); err != nil {
+			log.Fatal("Failed to seed demo data: ", err)
 		}
+		log.Println("Demo data seeded")
+	}
+
+	// Tables created before QR codes were stored get theirs now instead of on every list request
+	handler.BackfillTableQRCodes()
+	if seedOnly {
+		return
+	}
+
+	// Cancel orders left in pending (abandoned) so they don't skew active counts
+	handler.StartStaleOrderSweeper(
+		getEnvMinutes("STALE_ORDER_SWEEP_INTERVAL_MINUTES", 5*time.Minute),
+		getEnvMinutes("STALE_ORDER_MAX_AGE_MINUTES", 2*time.Hour),
+	)
+
+	// Hand pre-orders to the kitchen this long before they are wanted
+	handler.StartScheduledOrderReleaser(time.Minute, getEnvMinutes("SCHEDULED_ORDER_LEAD_MINUTES", 30*time.Minute))
+
+	// Public menus are cached between QR scans; PUBLIC_MENU_CACHE_TTL=0 turns that off
+	if os.Getenv("PUBLIC_MENU_CACHE_TTL") == "0" {
+		handler.SetPublicMenuCacheTTL(0)
 	} else {
-		// Split comma-separated origins
-		origins := strings.Split(corsOrigins, ",")
-		for _, origin := range origins {
-			allowOrigins = append(allowOrigins, strings.TrimSpace(origin))
-		}
+		handler.SetPublicMenuCacheTTL(getEnvDuration("PUBLIC_MENU_CACHE_TTL", handler.DefaultPublicMenuCacheTTL))
+	}
+
+	// Dashboards whose event buffer stays full this long are disconnected
+	handler.SetSlowClientTimeout(getEnvDuration("WS_SLOW_CLIENT_TIMEOUT", handler.DefaultSlowClientTimeout))
+
+	// HOST/PORT and optional TLS; the certificate is checked now rather than on the first connection
+	listen, err := loadListenSettings()
+	if err != nil {
+		log.Fatal("Invalid listen configuration: ", err)
+	}
+
+	// Uploads get the server-wide limit, everything else is held to the smaller JSON limit below
+	app := fiber.New(fiber.Config{
+		BodyLimit:    getEnvBytes("UPLOAD_BODY_LIMIT_BYTES", defaultUploadBodyLimit),
+		ErrorHandler: errorHandler,
+	})
+
+	// CORS configuration - security: wildcard origins are rejected since credentials are enabled
+	corsSettings, err := loadCORSSettings()
+	if err != nil {
+		log.Fatal("Invalid CORS configuration: ", err)
+	}
+	log.Printf("CORS allowed origins: %s (max age %ds)", strings.Join(corsSettings.AllowOrigins, ", "), corsSettings.MaxAge)
+	for method, origins := range corsSettings.MethodOrigins {
+		log.Printf("CORS allowed origins for %s: %s", method, strings.Join(origins, ", "))
 	}
 
 	// Add CORS middleware
-	app.Use(cors.New(cors.Config{
-		AllowOrigins:     strings.Join(allowOrigins, ","),
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
-		AllowMethods:     "GET, POST, PUT, DELETE, OPTIONS, PATCH",
-		AllowCredentials: true, // Enable credentials for WebSocket auth
-		ExposeHeaders:    "Content-Length",
-		MaxAge:           86400, // 24 hours
+	app.Use(newCORSMiddleware(corsSettings))
+
+	// Request logger with cookies, Authorization headers and token query parameters redacted. Registered
+	// before the body limit and timeout so their 413 and 503 responses are logged too
+	app.Use(logger.New(logger.Config{
+		CustomTags: utils.SanitizedLoggerTags(),
 	}))
 
-	app.Use(logger.New())
+	// Reject oversized JSON bodies, e.g. floods against the public order endpoint
+	app.Use(newBodyLimitMiddleware(getEnvBytes("BODY_LIMIT_BYTES", defaultJSONBodyLimit)))
+
+	// Cancel requests, and the queries they started, that run longer than REQUEST_TIMEOUT
+	app.Use(newRequestTimeoutMiddleware(getEnvDuration("REQUEST_TIMEOUT", defaultRequestTimeout)))
 
-	// Swagger route
+	// Swagger route. Without SWAGGER_HOST the UI sends requests to the host serving it, so "Try it out"
+	// reaches this instance and the browser includes its auth cookies
+	docs.SwaggerInfo.Host = os.Getenv("SWAGGER_HOST")
 	app.Get("/swagger/*", swagger.HandlerDefault)
 
 	setupRoutes(app)
-	app.Listen(":" + port)
+
+	if listen.TLS() {
+		log.Printf("Serving HTTPS on %s", listen.Addr)
+	} else {
+		log.Printf("Serving HTTP on %s", listen.Addr)
+	}
+	go func() {
+		if err := listen.listen(app); err != nil {
+			log.Fatal("Server failed: ", err)
+		}
+	}()
+
+	// Wait for SIGINT/SIGTERM, then stop accepting connections and let in-flight work finish
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	timeout := getEnvSeconds("SHUTDOWN_TIMEOUT_SECONDS", 30*time.Second)
+	log.Printf("Shutting down server (timeout %s)", timeout)
+	if err := app.ShutdownWithTimeout(timeout); err != nil {
+		log.Println("Error during server shutdown:", err)
+	}
+
+	// Requests have finished publishing, flush their events to dashboards and close the sockets
+	handler.ShutdownOrderHub(timeout)
+	log.Println("Server stopped")
 }
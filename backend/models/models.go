@@ -1,6 +1,7 @@
 package models
 
 import (
+	"order-system/utils"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,22 +9,49 @@ import (
 
 type User struct {
 	gorm.Model
-	Username    string       `gorm:"size:255;not null;unique"`
+	Username    string       `gorm:"size:255;not null;uniqueIndex:idx_users_username,where:deleted_at IS NULL"` // unique among undeleted users
 	Password    string       `gorm:"size:255;not null"`
 	Role        string       `gorm:"size:50;default:'owner'"` // You can add more roles (e.g., 'admin', 'staff')
 	Restaurants []Restaurant `gorm:"foreignKey:UserID"`
-	Email       string       `gorm:"size:255;not null;unique"`
+	Email       string       `gorm:"size:255;not null;uniqueIndex:idx_users_email,where:deleted_at IS NULL"` // unique among undeleted users
 }
 
 type Restaurant struct {
 	gorm.Model
-	UserID      uint       `gorm:"not null"` // Link restaurant to a user (owner)
-	Name        string     `gorm:"size:255;not null"`
-	Address     string     `gorm:"size:255"`
-	PhoneNumber string     `gorm:"size:50"`
-	LogoURL     string     `gorm:"size:255"`
-	Tables      []Table    `gorm:"foreignKey:RestaurantID"`
-	MenuItems   []MenuItem `gorm:"foreignKey:RestaurantID"`
+	UserID      uint                `gorm:"not null"` // Link restaurant to a user (owner)
+	Name        string              `gorm:"size:255;not null"`
+	Address     string              `gorm:"size:255"`
+	PhoneNumber string              `gorm:"size:50"`
+	LogoURL     string              `gorm:"size:255"`
+	Tables      []Table             `gorm:"foreignKey:RestaurantID"`
+	MenuItems   []MenuItem          `gorm:"foreignKey:RestaurantID"`
+	Settings    *RestaurantSettings `gorm:"foreignKey:RestaurantID"`
+}
+
+// RestaurantSettings holds per-restaurant tunables, one row per restaurant
+type RestaurantSettings struct {
+	gorm.Model
+	RestaurantID          uint        `gorm:"not null;uniqueIndex"`
+	TaxRate               float64     `gorm:"default:0"`                     // percent shown to customers; order totals don't include it
+	Currency              string      `gorm:"size:3;not null;default:'USD'"` // ISO 4217 code
+	PrepBufferMinutes     int         `gorm:"default:0"`                     // extra minutes added to preparation estimates
+	LowStockThreshold     int         `gorm:"not null"`                      // quantity at which items count as low on stock; the handler default of 5 keeps 0 storable
+	OperatingHoursEnabled bool        `gorm:"default:false"`
+	OrderRefPrefix        string      `gorm:"size:3;not null;default:'A'"` // letters before the daily number in order references, e.g. A-017
+	MinOrderAmount        utils.Money `gorm:"not null;default:0"`          // in cents, smallest items subtotal accepted for delivery orders; 0 for none
+	MaxActiveOrders       int         `gorm:"not null;default:0"`          // kitchen capacity: active orders at which customers' orders are turned away; 0 for no limit
+}
+
+// AuditLog records a create, update or delete performed by a user
+type AuditLog struct {
+	ID           uint      `gorm:"primaryKey"`
+	UserID       uint      `gorm:"not null;index"`
+	RestaurantID uint      `gorm:"not null;index:idx_audit_logs_restaurant_created;index:idx_audit_logs_restaurant_entity"`
+	Action       string    `gorm:"size:20;not null"`                                        // see constants.AuditAction*
+	Entity       string    `gorm:"size:50;not null;index:idx_audit_logs_restaurant_entity"` // see constants.AuditEntity*
+	EntityID     uint      `gorm:"not null;index:idx_audit_logs_restaurant_entity"`
+	Details      string    `gorm:"type:text"`
+	CreatedAt    time.Time `gorm:"index:idx_audit_logs_restaurant_created"`
 }
 
 type Table struct {
@@ -36,44 +64,116 @@ type Table struct {
 
 type MenuItem struct {
 	gorm.Model
-	RestaurantID uint        `gorm:"not null"`
-	Name         string      `gorm:"size:255;not null"`
-	Description  string      `gorm:"type:text"`
-	Price        float64     `gorm:"not null"`
-	Category     string      `gorm:"size:50"` // starter, main, dessert, drink
-	ImageURL     string      `gorm:"size:255"`
-	Quantity     int         `gorm:"default:0"` // available quantity of the menu item
-	OrderItems   []OrderItem `gorm:"foreignKey:MenuItemID"`
+	RestaurantID  uint             `gorm:"not null;uniqueIndex:idx_menu_items_restaurant_sku"`
+	SKU           *string          `gorm:"size:100;uniqueIndex:idx_menu_items_restaurant_sku"` // owner's external inventory code
+	Name          string           `gorm:"size:255;not null"`
+	Description   string           `gorm:"type:text"`
+	Price         utils.Money      `gorm:"not null"` // in cents
+	Category      string           `gorm:"size:50"`  // name of the linked MenuCategory, kept for display
+	CategoryID    *uint            `gorm:"index"`
+	ImageURL      string           `gorm:"size:255"`
+	Quantity      int              `gorm:"default:0"`              // available quantity of the menu item
+	DietaryTags   utils.StringList `gorm:"type:text;default:'[]'"` // JSON array from utils.DietaryTags
+	Allergens     utils.StringList `gorm:"type:text;default:'[]'"` // JSON array from utils.Allergens
+	IsFeatured    bool             `gorm:"default:false;index"`
+	FeaturedOrder int              `gorm:"default:0"`          // position among featured items, ascending
+	Station       string           `gorm:"size:50;index"`      // lowercase kitchen station it is prepared at, e.g. grill or bar; empty for none
+	Version       int              `gorm:"not null;default:0"` // bumped on every write, for optimistic locking
+	OrderItems    []OrderItem      `gorm:"foreignKey:MenuItemID"`
+}
+
+// InventoryAdjustment records a stock change made outside of orders, e.g. a restock or spoilage
+type InventoryAdjustment struct {
+	gorm.Model
+	RestaurantID  uint   `gorm:"not null;index"`
+	MenuItemID    uint   `gorm:"not null;index"`
+	UserID        uint   `gorm:"not null"`
+	Delta         int    `gorm:"not null"` // change requested by the owner
+	AppliedDelta  int    `gorm:"not null"` // change actually applied after flooring the quantity at zero
+	QuantityAfter int    `gorm:"not null"`
+	Reason        string `gorm:"size:255;not null"`
+}
+
+// StockMovement is one entry in a menu item's stock ledger
+type StockMovement struct {
+	ID            uint   `gorm:"primaryKey"`
+	RestaurantID  uint   `gorm:"not null;index"`
+	MenuItemID    uint   `gorm:"not null;index:idx_stock_movements_item_created"`
+	Type          string `gorm:"size:20;not null"` // see constants.StockMovement*
+	Delta         int    `gorm:"not null"`
+	QuantityAfter int    `gorm:"not null"` // running balance after this movement
+	OrderID       *uint  `gorm:"index"`
+	AdjustmentID  *uint
+	Note          string    `gorm:"size:255"`
+	CreatedAt     time.Time `gorm:"index:idx_stock_movements_item_created"`
+}
+
+// MenuCategory is a restaurant-defined menu section with its position on the customer menu
+type MenuCategory struct {
+	gorm.Model
+	RestaurantID uint   `gorm:"not null;index"`
+	Name         string `gorm:"size:50;not null"`
+	DisplayOrder int    `gorm:"default:0"`
 }
 
 type Order struct {
 	gorm.Model
-	TableID      uint        `gorm:"not null"`
-	CustomerName string      `gorm:"size:255"`                  // Name of the customer who placed the order
-	Status       string      `gorm:"size:50;default:'pending'"` // pending, preparing, served, completed, cancelled
-	TotalAmount  float64     `gorm:"not null"`
-	CreatedAt    time.Time   `gorm:"autoCreateTime"`
-	UpdatedAt    time.Time   `gorm:"autoUpdateTime"`
-	OrderItems   []OrderItem `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
-	Payments     []Payment   `gorm:"foreignKey:OrderID"`
+	RestaurantID    uint        `gorm:"not null;default:0;index"` // owning restaurant, also for orders without a table
+	TableID         *uint       // nil for takeaway and delivery orders, which aren't served at a table
+	CustomerName    string      `gorm:"size:255"`                           // Name of the customer who placed the order
+	CustomerPhone   string      `gorm:"size:32;not null;default:''"`        // optional, checked with utils.ValidatePhone
+	OrderType       string      `gorm:"size:20;not null;default:'dine_in'"` // see constants.OrderType*
+	DeliveryAddress string      `gorm:"size:500;not null;default:''"`       // set on delivery orders only
+	Status          string      `gorm:"size:50;default:'pending'"`          // see constants.OrderStatus*
+	TotalAmount     utils.Money `gorm:"not null"`                           // in cents: items subtotal - discount + tip
+	Discount        utils.Money `gorm:"not null;default:0"`                 // in cents, order-level discount set by staff
+	Tip             utils.Money `gorm:"not null;default:0"`                 // in cents
+	GroupID         *uint       `gorm:"index"`                              // set when merged with other tables' orders to be paid together
+	OrderRef        string      `gorm:"size:20;not null;default:''"`        // daily reference staff call out, e.g. A-017; empty on orders from before references
+	ScheduledFor    *time.Time  // when a pre-order is wanted; nil for orders wanted now
+	CreatedAt       time.Time   `gorm:"autoCreateTime"`
+	UpdatedAt       time.Time   `gorm:"autoUpdateTime"`
+	Restaurant      *Restaurant `gorm:"foreignKey:RestaurantID" json:"-"`
+	Table           *Table      `gorm:"foreignKey:TableID" json:"-"` // Loaded for the table number; kept out of JSON to avoid shipping QR images
+	OrderItems      []OrderItem `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	Payments        []Payment   `gorm:"foreignKey:OrderID"`
+}
+
+// OrderSequence is the last order number a restaurant handed out on a day, for order references
+type OrderSequence struct {
+	RestaurantID uint   `gorm:"primaryKey;autoIncrement:false"`
+	Day          string `gorm:"primaryKey;size:10"` // YYYY-MM-DD in server local time
+	LastNumber   int    `gorm:"not null"`
+}
+
+// OrderGroup ties together open orders from several tables (e.g. pushed-together tables)
+// so they can be settled as one bill; each order keeps its own items and history. The group's
+// total is always computed from its orders, so it follows changes to them.
+type OrderGroup struct {
+	gorm.Model
+	RestaurantID uint    `gorm:"not null"`
+	Orders       []Order `gorm:"foreignKey:GroupID"`
 }
 
 type OrderItem struct {
 	gorm.Model
-	OrderID             uint      `gorm:"not null"`
-	MenuItemID          uint      `gorm:"not null"`
-	Quantity            int       `gorm:"default:1"`
-	SpecialInstructions string    `gorm:"type:text"`
-	MenuItem            MenuItem  `gorm:"foreignKey:MenuItemID;references:ID"`
+	OrderID             uint        `gorm:"not null"`
+	MenuItemID          uint        `gorm:"not null"`
+	ItemName            string      `gorm:"size:255;not null;default:''"` // menu item name when the order was placed
+	UnitPrice           utils.Money `gorm:"not null;default:0"`           // in cents, menu item price when the order was placed
+	Quantity            int         `gorm:"default:1"`
+	SpecialInstructions string      `gorm:"type:text"`
+	Status              string      `gorm:"size:20;not null;default:'pending'"` // pending until the kitchen marks the item ready
+	MenuItem            MenuItem    `gorm:"foreignKey:MenuItemID;references:ID"`
 }
 
 type Payment struct {
 	gorm.Model
-	OrderID       uint      `gorm:"not null"`
-	PaymentMethod string    `gorm:"size:50"`                   // credit_card, mobile_wallet, paypal, cash
-	PaymentStatus string    `gorm:"size:50;default:'pending'"` // pending, completed, failed
-	Amount        float64   `gorm:"not null"`
-	PaymentDate   time.Time `gorm:"autoCreateTime"`
+	OrderID       uint        `gorm:"not null"`
+	PaymentMethod string      `gorm:"size:50"`                   // credit_card, mobile_wallet, paypal, cash
+	PaymentStatus string      `gorm:"size:50;default:'pending'"` // pending, completed, failed
+	Amount        utils.Money `gorm:"not null"`                  // in cents
+	PaymentDate   time.Time   `gorm:"autoCreateTime"`
 }
 
 // swagger:model LoginRequest
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"order-system/constants"
+	"order-system/utils"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/websocket/v2"
+)
+
+// defaultRequestTimeout bounds how long a request may run when REQUEST_TIMEOUT isn't set
+const defaultRequestTimeout = 30 * time.Second
+
+// getEnvDuration reads a positive Go duration such as "30s" or "2m" from the environment,
+// falling back to a default
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		log.Printf("Invalid %s value %q, using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+// newRequestTimeoutMiddleware gives each request a context that is cancelled after timeout.
+// Database work started with that context (see handler.db) is cancelled with it, and a
+// handler that fails once the deadline has passed is answered with 503 instead of its own error,
+// e.g. the 404 a cancelled lookup turns into. A handler that succeeds anyway keeps its response,
+// so a completed write is never reported as timed out. WebSocket upgrades are long-lived and
+// skip the timeout.
+func newRequestTimeoutMiddleware(timeout time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if websocket.IsWebSocketUpgrade(c) {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && (err != nil || c.Response().StatusCode() >= fiber.StatusBadRequest) {
+			log.Printf("Request %s %s timed out after %s", c.Method(), c.Path(), timeout)
+			return utils.SendError(c, fiber.StatusServiceUnavailable, constants.ErrCodeRequestTimeout, "Request timed out")
+		}
+		return err
+	}
+}
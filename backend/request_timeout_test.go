@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	app := fiber.New()
+	app.Use(newRequestTimeoutMiddleware(50 * time.Millisecond))
+	app.Get("/fast", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	// Like a query cancelled with the request context
+	app.Get("/slow", func(c *fiber.Ctx) error {
+		<-c.UserContext().Done()
+		return c.SendStatus(fiber.StatusInternalServerError)
+	})
+	// A write that completed despite running past the deadline
+	app.Post("/slow-write", func(c *fiber.Ctx) error {
+		time.Sleep(100 * time.Millisecond)
+		return c.SendStatus(fiber.StatusCreated)
+	})
+
+	tests := []struct {
+		method, path string
+		status       int
+	}{
+		{http.MethodGet, "/fast", fiber.StatusOK},
+		{http.MethodGet, "/slow", fiber.StatusServiceUnavailable},
+		{http.MethodPost, "/slow-write", fiber.StatusCreated},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil), -1)
+		if err != nil {
+			t.Fatalf("fiber app test failed: %v", err)
+		}
+		if resp.StatusCode != tt.status {
+			t.Fatalf("%s %s: expected status %d, got %d", tt.method, tt.path, tt.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestGetEnvDuration(t *testing.T) {
+	t.Setenv("TEST_DURATION", "")
+	if got := getEnvDuration("TEST_DURATION", time.Minute); got != time.Minute {
+		t.Fatalf("expected default, got %s", got)
+	}
+	t.Setenv("TEST_DURATION", "45s")
+	if got := getEnvDuration("TEST_DURATION", time.Minute); got != 45*time.Second {
+		t.Fatalf("expected 45s, got %s", got)
+	}
+	for _, invalid := range []string{"45", "-5s", "soon"} {
+		t.Setenv("TEST_DURATION", invalid)
+		if got := getEnvDuration("TEST_DURATION", time.Minute); got != time.Minute {
+			t.Fatalf("%q: expected default, got %s", invalid, got)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"order-system/handler"
 	"order-system/utils"
+	"order-system/version"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -20,8 +21,24 @@ func healthCheck(c *fiber.Ctx) error {
 	})
 }
 
+// versionInfo godoc
+// @Summary Build version
+// @Description Get the version, git commit and build time of the running server
+// @Tags Health
+// @Produce json
+// @Success 200 {object} handler.Envelope[version.Info]
+// @Router /version [get]
+func versionInfo(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"success": true,
+		"data":    version.Get(),
+		"error":   nil,
+	})
+}
+
 func setupRoutes(app *fiber.App) {
 	app.Get("/health", handler.HealthCheck)
+	app.Get("/version", versionInfo)
 	app.Get("/ws/orders", websocket.New(handler.HandleOrderSocket))
 	api := app.Group("/api")
 
@@ -40,11 +57,23 @@ func setupRoutes(app *fiber.App) {
 		handler.RefreshToken)
 	user.Get("/websocket-token", handler.ProtectRoute, handler.GetWebSocketToken)
 	user.Delete("/", handler.DeleteUser)
+	user.Patch("/:id/role", handler.ProtectRoute, handler.UpdateUserRole)
 
 	// Public restaurant endpoints (no authentication required)
 	api.Get("/restaurant/:id", handler.GetPublicRestaurantByID)
-	api.Get("/restaurants/:restaurant_id/menu", handler.GetPublicMenuItems)  // Different route to avoid conflict
+	api.Get("/restaurants/:restaurant_id/menu", handler.GetPublicMenuItems) // Different route to avoid conflict
+	api.Get("/restaurants/:restaurant_id/menu/featured", handler.GetFeaturedMenuItems)
+	api.Get("/restaurants/:restaurant_id/storefront", handler.GetStorefront)
 	api.Post("/restaurants/:restaurant_id/order", handler.CreatePublicOrder) // Different route to avoid conflict
+	api.Post("/restaurants/:restaurant_id/my-orders/code",
+		utils.RateLimitMiddleware(5, time.Minute), // each code is a text message
+		handler.RequestGuestOrderCode)
+	api.Get("/restaurants/:restaurant_id/my-orders",
+		utils.RateLimitMiddleware(10, time.Minute), // against guessing codes across numbers
+		handler.GetGuestOrders)
+	api.Post("/restaurants/:restaurant_id/reorder/:order_id",
+		utils.RateLimitMiddleware(10, time.Minute), // against guessing codes across numbers
+		handler.ReorderGuestOrder)
 
 	// Protected restaurant management endpoints (authentication required)
 	protectedRestaurant := api.Group("/restaurant", handler.ProtectRoute)
@@ -53,10 +82,16 @@ func setupRoutes(app *fiber.App) {
 	protectedRestaurant.Get("/:id", handler.GetRestaurantByID) // Allow authenticated users to get restaurant details too
 	protectedRestaurant.Put("/:id", handler.UpdateRestaurant)
 	protectedRestaurant.Delete("/:id", handler.DeleteRestaurant)
+	protectedRestaurant.Post("/:id/clone", handler.CloneRestaurant)
+	protectedRestaurant.Get("/:id/settings", handler.GetRestaurantSettings)
+	protectedRestaurant.Patch("/:id/settings", handler.UpdateRestaurantSettings)
+	protectedRestaurant.Get("/:id/audit", handler.GetAuditLog)
+	protectedRestaurant.Get("/:restaurant_id/search", handler.SearchRestaurant)
 
 	// Table routes (nested under restaurant - protected)
 	protectedRestaurant.Post("/:restaurant_id/table", handler.CreateTable)
 	protectedRestaurant.Get("/:restaurant_id/table", handler.GetTables)
+	protectedRestaurant.Post("/:restaurant_id/tables/batch", handler.CreateTablesBatch)
 	protectedRestaurant.Put("/:restaurant_id/table/:id", handler.UpdateTable)
 	protectedRestaurant.Delete("/:restaurant_id/table/:id", handler.DeleteTable)
 
@@ -66,14 +101,36 @@ func setupRoutes(app *fiber.App) {
 	// Menu routes (nested under restaurant - protected)
 	protectedRestaurant.Post("/:restaurant_id/menu", handler.CreateMenuItem)
 	protectedRestaurant.Get("/:restaurant_id/menu", handler.GetMenuItems) // Protected access to owner's menu
+	protectedRestaurant.Get("/:restaurant_id/menu/out-of-stock", handler.GetOutOfStockMenuItems)
 	protectedRestaurant.Put("/:restaurant_id/menu/:id", handler.UpdateMenuItem)
+	protectedRestaurant.Put("/:restaurant_id/menu/by-sku/:sku", handler.UpsertMenuItemBySKU)
+	protectedRestaurant.Post("/:restaurant_id/menu/:id/adjust-stock", handler.AdjustMenuItemStock)
+	protectedRestaurant.Get("/:restaurant_id/menu/:id/stock-history", handler.GetStockHistory)
 	protectedRestaurant.Delete("/:restaurant_id/menu/:id", handler.DeleteMenuItem)
+	protectedRestaurant.Delete("/:restaurant_id/menu", handler.DeleteMenuItemsBatch)
+	protectedRestaurant.Post("/:restaurant_id/menu-categories", handler.CreateMenuCategory)
+	protectedRestaurant.Get("/:restaurant_id/menu-categories", handler.GetMenuCategories)
+	protectedRestaurant.Put("/:restaurant_id/menu-categories/:id", handler.UpdateMenuCategory)
+	protectedRestaurant.Delete("/:restaurant_id/menu-categories/:id", handler.DeleteMenuCategory)
 
 	// Order routes (nested under restaurant - protected)
+	protectedRestaurant.Post("/:restaurant_id/order", handler.CreateOrder)
 	protectedRestaurant.Get("/:restaurant_id/order", handler.GetOrders)
+	protectedRestaurant.Get("/:restaurant_id/orders/active-count", handler.GetActiveOrderCount)
+	protectedRestaurant.Post("/:restaurant_id/orders/merge", handler.MergeOrders)
+	protectedRestaurant.Get("/:restaurant_id/orders/group/:id", handler.GetOrderGroup)
+	protectedRestaurant.Post("/:restaurant_id/orders/group/:id/payments", handler.PayOrderGroup)
+	protectedRestaurant.Get("/:restaurant_id/kitchen", handler.GetKitchenOrders)
 	protectedRestaurant.Get("/:restaurant_id/order/:id", handler.GetOrder)
 	protectedRestaurant.Patch("/:restaurant_id/order/:id", handler.UpdateOrderStatus)
+	protectedRestaurant.Patch("/:restaurant_id/order/:id/table", handler.TransferOrderTable)
+	protectedRestaurant.Patch("/:restaurant_id/order/:id/items/:item_id", handler.UpdateOrderItemStatus)
 	protectedRestaurant.Delete("/:restaurant_id/order/:id", handler.DeleteOrder)
+	protectedRestaurant.Post("/:restaurant_id/order/:id/payments", handler.SplitOrderPayment)
+
+	// Report routes (nested under restaurant - protected)
+	protectedRestaurant.Get("/:restaurant_id/reports/fulfillment-time", handler.GetFulfillmentTimeReport)
+	protectedRestaurant.Get("/:restaurant_id/reports/hourly", handler.GetHourlyRevenueReport)
 
 	// All orders route (for all restaurants the user owns)
 	api.Get("/order", handler.ProtectRoute, handler.GetAllUserOrders)
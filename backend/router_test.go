@@ -51,3 +51,28 @@ func TestHealthCheck(t *testing.T) {
 		t.Fatalf("expected error to be nil, got %v", body["error"])
 	}
 }
+
+func TestVersionInfo(t *testing.T) {
+	app := fiber.New()
+	app.Get("/version", versionInfo)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/version", nil), -1)
+	if err != nil {
+		t.Fatalf("fiber app test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data map[string]string `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	for _, key := range []string{"version", "commit", "build_time"} {
+		if body.Data[key] == "" {
+			t.Fatalf("expected %s to be set, got %v", key, body.Data)
+		}
+	}
+}
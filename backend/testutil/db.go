@@ -0,0 +1,190 @@
+// Package testutil provides an isolated database for tests that need one.
+//
+// When TEST_DATABASE_URL (or DATABASE_URL with a Postgres DB_DRIVER) is set, each test binary gets
+// its own Postgres schema there. Otherwise it gets a SQLite database in a temporary file, so the
+// suite runs without a database server. Either way the database is migrated with database.Migrate,
+// isolated from the development tables and from parallel `go test` packages, and its tables are
+// emptied at the start of every test that calls SetupDB.
+package testutil
+
+import (
+	"fmt"
+	"net/url"
+	"order-system/database"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+var (
+	setupOnce sync.Once
+	setupErr  error
+	schema    string
+	adminDB   *gorm.DB
+	sqliteDir string
+)
+
+// postgresURL returns the Postgres server to test against, or "" to use SQLite.
+// TEST_DATABASE_URL lets tests point at a different server than the app.
+func postgresURL() string {
+	godotenv.Load()
+	if dsn := os.Getenv("TEST_DATABASE_URL"); dsn != "" {
+		return dsn
+	}
+	if strings.EqualFold(os.Getenv("DB_DRIVER"), database.DriverSQLite) {
+		return ""
+	}
+	return os.Getenv("DATABASE_URL")
+}
+
+// withSearchPath returns dsn with every connection's search_path set to schema.
+// Both URL (postgres://...) and key=value connection strings are supported.
+func withSearchPath(dsn, schema string) (string, error) {
+	if strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://") {
+		u, err := url.Parse(dsn)
+		if err != nil {
+			return "", err
+		}
+		query := u.Query()
+		query.Set("search_path", schema)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+	return strings.TrimSpace(dsn) + " search_path=" + schema, nil
+}
+
+// SetupDB points database.DB at this test binary's database, creating and migrating it on first use,
+// and empties every table
+func SetupDB(t testing.TB) *gorm.DB {
+	t.Helper()
+
+	setupOnce.Do(func() {
+		if dsn := postgresURL(); dsn != "" {
+			setupErr = createSchema(dsn)
+		} else {
+			setupErr = createSQLite()
+		}
+	})
+	if setupErr != nil {
+		t.Fatalf("setting up test database: %v", setupErr)
+	}
+
+	if err := truncateTables(); err != nil {
+		t.Fatalf("truncating test database: %v", err)
+	}
+	return database.DB
+}
+
+// createSchema creates a schema unique to this process and migrates it
+func createSchema(dsn string) error {
+	var err error
+	adminDB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		return err
+	}
+
+	schema = fmt.Sprintf("test_%d_%d", os.Getpid(), time.Now().UnixNano())
+	if err := adminDB.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		return err
+	}
+
+	schemaDSN, err := withSearchPath(dsn, schema)
+	if err != nil {
+		return err
+	}
+	database.DB, err = gorm.Open(postgres.Open(schemaDSN), &gorm.Config{})
+	if err != nil {
+		return err
+	}
+	return database.Migrate()
+}
+
+// RequirePostgres sets up the database like SetupDB, skipping the test on SQLite.
+// Use it for tests that depend on row locking, which SQLite doesn't have.
+func RequirePostgres(t testing.TB) *gorm.DB {
+	t.Helper()
+	db := SetupDB(t)
+	if database.IsSQLite() {
+		t.Skip("test needs Postgres, set TEST_DATABASE_URL to run it")
+	}
+	return db
+}
+
+// createSQLite creates a SQLite database in a temporary directory and migrates it
+func createSQLite() error {
+	var err error
+	sqliteDir, err = os.MkdirTemp("", "order-system-test-")
+	if err != nil {
+		return err
+	}
+	database.DB, err = database.Open(database.DriverSQLite, filepath.Join(sqliteDir, "test.db"))
+	if err != nil {
+		return err
+	}
+	return database.Migrate()
+}
+
+// truncateTables empties every table in the test database except schema_migrations and resets their IDs
+func truncateTables() error {
+	if database.IsSQLite() {
+		return truncateSQLiteTables()
+	}
+
+	var tables []string
+	if err := database.DB.Raw("SELECT tablename FROM pg_tables WHERE schemaname = ? AND tablename <> 'schema_migrations'", schema).
+		Scan(&tables).Error; err != nil {
+		return err
+	}
+	if len(tables) == 0 {
+		return nil
+	}
+	return database.DB.Exec("TRUNCATE TABLE " + strings.Join(tables, ", ") + " RESTART IDENTITY CASCADE").Error
+}
+
+// truncateSQLiteTables deletes every row in one transaction. Foreign keys are only checked at
+// commit, when all tables are empty, so the delete order doesn't matter.
+func truncateSQLiteTables() error {
+	return database.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("PRAGMA defer_foreign_keys = ON").Error; err != nil {
+			return err
+		}
+		var tables []string
+		if err := tx.Raw("SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE 'sqlite_%' AND name <> 'schema_migrations'").
+			Scan(&tables).Error; err != nil {
+			return err
+		}
+		for _, table := range tables {
+			if err := tx.Exec("DELETE FROM " + table).Error; err != nil {
+				return err
+			}
+		}
+		if tx.Migrator().HasTable("sqlite_sequence") {
+			return tx.Exec("DELETE FROM sqlite_sequence").Error
+		}
+		return nil
+	})
+}
+
+// Main runs the package's tests and then removes the database SetupDB created. Call it from TestMain.
+func Main(m *testing.M) {
+	code := m.Run()
+	if adminDB != nil && schema != "" {
+		if err := adminDB.Exec("DROP SCHEMA IF EXISTS " + schema + " CASCADE").Error; err != nil {
+			fmt.Fprintf(os.Stderr, "dropping test schema %s: %v\n", schema, err)
+		}
+	}
+	if sqliteDir != "" {
+		if sqlDB, err := database.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+		os.RemoveAll(sqliteDir)
+	}
+	os.Exit(code)
+}
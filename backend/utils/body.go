@@ -0,0 +1,222 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"order-system/constants"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// IsDevelopment reports whether APP_ENV is development, where error responses carry more detail
+func IsDevelopment() bool {
+	return os.Getenv("APP_ENV") == "development"
+}
+
+// UnknownFieldsError is returned by BodyParserStrict for JSON fields the target doesn't have
+type UnknownFieldsError struct {
+	Fields []string // dotted paths, e.g. order_items.0.quantaty
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return "unknown fields: " + strings.Join(e.Fields, ", ")
+}
+
+// BodyParserStrict is c.BodyParser that also rejects JSON fields out doesn't have, at any
+// depth, with an *UnknownFieldsError listing them all. Handlers opt in where a misspelt field
+// silently falling back to its default would do harm, such as order creation and payments.
+func BodyParserStrict(c *fiber.Ctx, out interface{}) error {
+	if err := c.BodyParser(out); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(strings.ToLower(c.Get(fiber.HeaderContentType)), fiber.MIMEApplicationJSON) {
+		return nil
+	}
+	var body interface{}
+	if err := json.Unmarshal(c.Body(), &body); err != nil {
+		return err
+	}
+	fields := unknownJSONFields(body, reflect.TypeOf(out), "")
+	if len(fields) > 0 {
+		sort.Strings(fields)
+		return &UnknownFieldsError{Fields: fields}
+	}
+	return nil
+}
+
+// unknownJSONFields walks a decoded JSON value alongside the Go type it was decoded into and
+// returns the paths of object keys that type has no field for
+func unknownJSONFields(value interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Types that decode themselves, such as Money and time.Time, take whatever they accept
+	if t.Implements(jsonUnmarshalerType) || reflect.PointerTo(t).Implements(jsonUnmarshalerType) {
+		return nil
+	}
+
+	var unknown []string
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fields := jsonFields(t)
+		for key, child := range object {
+			fieldPath := joinJSONPath(path, key)
+			field, ok := lookupJSONField(fields, key)
+			if !ok {
+				unknown = append(unknown, fieldPath)
+				continue
+			}
+			unknown = append(unknown, unknownJSONFields(child, field.Type, fieldPath)...)
+		}
+	case reflect.Slice, reflect.Array:
+		elements, ok := value.([]interface{})
+		if !ok {
+			return nil
+		}
+		for i, element := range elements {
+			unknown = append(unknown, unknownJSONFields(element, t.Elem(), joinJSONPath(path, strconv.Itoa(i)))...)
+		}
+	case reflect.Map:
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for key, child := range object {
+			unknown = append(unknown, unknownJSONFields(child, t.Elem(), joinJSONPath(path, key))...)
+		}
+	}
+	return unknown
+}
+
+var jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// jsonFields maps the JSON names of a struct's fields, including those promoted from embedded
+// structs, to the fields, following encoding/json's tag rules
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				for embeddedName, embeddedField := range jsonFields(embedded) {
+					if _, ok := fields[embeddedName]; !ok {
+						fields[embeddedName] = embeddedField
+					}
+				}
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+// lookupJSONField finds the field for a JSON key, which like encoding/json ignores case
+func lookupJSONField(fields map[string]reflect.StructField, key string) (reflect.StructField, bool) {
+	if field, ok := fields[key]; ok {
+		return field, true
+	}
+	for name, field := range fields {
+		if strings.EqualFold(name, key) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func joinJSONPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// SendBodyParseError answers a request whose body c.BodyParser couldn't decode with 400
+// INVALID_INPUT, telling malformed JSON apart from a field of the wrong type. In development
+// the decoder's own message is added as data.detail; unknown fields from BodyParserStrict are
+// always listed in data.unknown_fields.
+func SendBodyParseError(c *fiber.Ctx, err error) error {
+	var data interface{}
+	var unknownErr *UnknownFieldsError
+	if errors.As(err, &unknownErr) {
+		data = fiber.Map{"unknown_fields": unknownErr.Fields}
+	} else if IsDevelopment() {
+		data = fiber.Map{"detail": err.Error()}
+	}
+	return SendErrorWithData(c, fiber.StatusBadRequest, constants.ErrCodeInvalidInput, bodyParseErrorMessage(err), data)
+}
+
+// bodyParseErrorMessage describes a BodyParser error without echoing the request body
+func bodyParseErrorMessage(err error) string {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var unknownErr *UnknownFieldsError
+	switch {
+	case errors.As(err, &unknownErr):
+		return "Unknown fields: " + strings.Join(unknownErr.Fields, ", ")
+	case errors.As(err, &syntaxErr), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return "Request body is not valid JSON"
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return fmt.Sprintf("Request body must be %s", jsonTypeName(typeErr.Type))
+		}
+		return fmt.Sprintf("%s must be %s, not %s", typeErr.Field, jsonTypeName(typeErr.Type), typeErr.Value)
+	case errors.Is(err, fiber.ErrUnprocessableEntity):
+		// BodyParser doesn't know the Content-Type
+		return "Request body must be sent as application/json"
+	default:
+		return "Invalid input"
+	}
+}
+
+// jsonTypeName names the JSON value a Go type is decoded from, with an article
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "a different type"
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a whole number"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Ptr:
+		return jsonTypeName(t.Elem())
+	default:
+		return "a different type"
+	}
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendBodyParseError(t *testing.T) {
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		var request struct {
+			TableID uint `json:"table_id"`
+			Items   []struct {
+				Quantity int `json:"quantity"`
+			} `json:"items"`
+		}
+		if err := c.BodyParser(&request); err != nil {
+			return SendBodyParseError(c, err)
+		}
+		return SendSuccess(c, request)
+	})
+	post := func(contentType, body string) (int, APIResponse) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", contentType)
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		raw, _ := io.ReadAll(resp.Body)
+		var response APIResponse
+		assert.NoError(t, json.Unmarshal(raw, &response))
+		return resp.StatusCode, response
+	}
+
+	t.Setenv("APP_ENV", "production")
+	status, response := post("application/json", `{"table_id": 4`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "INVALID_INPUT", response.Code)
+	assert.Equal(t, "Request body is not valid JSON", response.Error)
+	assert.Nil(t, response.Data, "decoder detail is only shown in development")
+
+	_, response = post("application/json", `{"table_id": "4"}`)
+	assert.Equal(t, "table_id must be a whole number, not string", response.Error)
+
+	_, response = post("application/json", `{"items": [{"quantity": true}]}`)
+	assert.Equal(t, "items.0.quantity must be a whole number, not bool", response.Error)
+
+	_, response = post("text/plain", `table_id=4`)
+	assert.Equal(t, "Request body must be sent as application/json", response.Error)
+
+	t.Setenv("APP_ENV", "development")
+	_, response = post("application/json", `{"table_id": 4`)
+	assert.Equal(t, map[string]interface{}{"detail": "unexpected end of JSON input"}, response.Data)
+}
+
+func TestBodyParserStrictListsUnknownFields(t *testing.T) {
+	type contact struct {
+		Phone string `json:"phone"`
+	}
+	type request struct {
+		contact
+		TableID uint  `json:"table_id"`
+		Tip     Money `json:"tip"`
+		Items   []struct {
+			Quantity int `json:"quantity"`
+		} `json:"items"`
+		Ignored string `json:"-"`
+	}
+	app := fiber.New()
+	app.Post("/", func(c *fiber.Ctx) error {
+		var body request
+		if err := BodyParserStrict(c, &body); err != nil {
+			return SendBodyParseError(c, err)
+		}
+		return SendSuccess(c, body.TableID)
+	})
+	post := func(body string) (int, APIResponse) {
+		req := httptest.NewRequest("POST", "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		assert.NoError(t, err)
+		var response APIResponse
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		return resp.StatusCode, response
+	}
+
+	// Embedded fields, differently cased keys and self-decoding types are all known
+	status, _ := post(`{"phone": "555 0100", "Table_ID": 4, "tip": "1.50", "items": [{"quantity": 2}]}`)
+	assert.Equal(t, fiber.StatusOK, status)
+
+	status, response := post(`{"table_id": 4, "tabel": 5, "items": [{"quantity": 1}, {"quantaty": 2}], "Ignored": "x"}`)
+	assert.Equal(t, fiber.StatusBadRequest, status)
+	assert.Equal(t, "Unknown fields: Ignored, items.1.quantaty, tabel", response.Error)
+	assert.Equal(t, map[string]interface{}{"unknown_fields": []interface{}{"Ignored", "items.1.quantaty", "tabel"}}, response.Data)
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"errors"
+	"order-system/constants"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// pgUniqueViolation is the Postgres SQLSTATE for unique constraint violations
+const pgUniqueViolation = "23505"
+
+// uniqueConstraint describes a unique constraint for client-facing conflict messages
+type uniqueConstraint struct {
+	names   []string // Postgres constraint or index names, including ones older migrations used
+	columns string   // how SQLite names the constraint in "UNIQUE constraint failed: <columns>"
+	code    string
+	message string
+}
+
+var uniqueConstraints = []uniqueConstraint{
+	{[]string{"idx_users_username", "uni_users_username", "users_username_key"}, "users.username", constants.ErrCodeUsernameTaken, "Username already taken"},
+	{[]string{"idx_users_email", "uni_users_email", "users_email_key"}, "users.email", constants.ErrCodeEmailTaken, "Email already registered"},
+	{[]string{"idx_menu_items_restaurant_sku"}, "menu_items.restaurant_id, menu_items.sku", constants.ErrCodeSKUInUse, "SKU already in use"},
+	{[]string{"idx_restaurant_settings_restaurant_id"}, "restaurant_settings.restaurant_id", constants.ErrCodeSettingsExist, "Settings already exist for this restaurant"},
+	{[]string{"idx_menu_categories_restaurant_name"}, "index 'idx_menu_categories_restaurant_name'", constants.ErrCodeCategoryExists, "Category already exists"},
+}
+
+// MapDBError maps a database error to an HTTP status, error code and client-facing message. Unique
+// constraint violations become 409 Conflict with a code and message naming the duplicated field;
+// anything else is 500 Internal Server Error with a generic message that handlers usually replace
+// with their own.
+func MapDBError(err error) (int, string, string) {
+	if constraint, ok := uniqueViolation(err); ok {
+		for _, known := range uniqueConstraints {
+			if constraint == known.columns {
+				return fiber.StatusConflict, known.code, known.message
+			}
+			for _, name := range known.names {
+				if constraint == name {
+					return fiber.StatusConflict, known.code, known.message
+				}
+			}
+		}
+		return fiber.StatusConflict, constants.ErrCodeDuplicateValue, "A record with the same values already exists"
+	}
+	return fiber.StatusInternalServerError, constants.ErrCodeInternal, "Database error"
+}
+
+// uniqueViolation reports whether err is a unique constraint violation and which constraint failed:
+// its name on Postgres, its columns on SQLite, or "" when the driver doesn't say
+func uniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.ConstraintName, pgErr.Code == pgUniqueViolation
+	}
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		if sqliteErr.ExtendedCode != sqlite3.ErrConstraintUnique && sqliteErr.ExtendedCode != sqlite3.ErrConstraintPrimaryKey {
+			return "", false
+		}
+		_, columns, _ := strings.Cut(sqliteErr.Error(), "constraint failed: ")
+		return columns, true
+	}
+
+	return "", errors.Is(err, gorm.ErrDuplicatedKey)
+}
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"order-system/constants"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestMapDBErrorPostgres(t *testing.T) {
+	status, code, message := MapDBError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_users_email"})
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, constants.ErrCodeEmailTaken, code)
+	assert.Equal(t, "Email already registered", message)
+
+	// Wrapped errors and constraint names from before versioned migrations are recognized too
+	status, code, message = MapDBError(fmt.Errorf("saving: %w", &pgconn.PgError{Code: "23505", ConstraintName: "users_username_key"}))
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, constants.ErrCodeUsernameTaken, code)
+	assert.Equal(t, "Username already taken", message)
+
+	status, code, message = MapDBError(&pgconn.PgError{Code: "23505", ConstraintName: "idx_something_new"})
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, constants.ErrCodeDuplicateValue, code)
+	assert.Equal(t, "A record with the same values already exists", message)
+
+	// Other constraint violations aren't conflicts
+	status, _, _ = MapDBError(&pgconn.PgError{Code: "23503", ConstraintName: "fk_users_restaurants"})
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+}
+
+func TestMapDBErrorSQLite(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open("file::memory:"), &gorm.Config{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Exec("CREATE TABLE menu_items (id integer PRIMARY KEY, restaurant_id integer, sku text)").Error)
+	assert.NoError(t, db.Exec("CREATE UNIQUE INDEX idx_menu_items_restaurant_sku ON menu_items (restaurant_id, sku)").Error)
+	assert.NoError(t, db.Exec("INSERT INTO menu_items (id, restaurant_id, sku) VALUES (1, 1, 'A-1')").Error)
+
+	status, code, message := MapDBError(db.Exec("INSERT INTO menu_items (id, restaurant_id, sku) VALUES (2, 1, 'A-1')").Error)
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, constants.ErrCodeSKUInUse, code)
+	assert.Equal(t, "SKU already in use", message)
+
+	status, _, _ = MapDBError(db.Exec("INSERT INTO missing_table (id) VALUES (1)").Error)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+}
+
+func TestMapDBErrorOther(t *testing.T) {
+	status, _, _ := MapDBError(gorm.ErrDuplicatedKey)
+	assert.Equal(t, fiber.StatusConflict, status)
+
+	status, code, message := MapDBError(errors.New("connection refused"))
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, constants.ErrCodeInternal, code)
+	assert.Equal(t, "Database error", message)
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DietaryTags is the vocabulary accepted for MenuItem.DietaryTags
+var DietaryTags = []string{
+	"vegetarian",
+	"vegan",
+	"gluten-free",
+	"dairy-free",
+	"nut-free",
+	"halal",
+	"kosher",
+}
+
+// Allergens is the vocabulary accepted for MenuItem.Allergens, following the 14 major allergens
+var Allergens = []string{
+	"celery",
+	"crustaceans",
+	"eggs",
+	"fish",
+	"gluten",
+	"lupin",
+	"milk",
+	"molluscs",
+	"mustard",
+	"peanuts",
+	"sesame",
+	"soybeans",
+	"sulphites",
+	"tree-nuts",
+}
+
+// StringList is a list of strings stored as a JSON array in a text column
+type StringList []string
+
+// Value encodes the list as a JSON array
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	data, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan decodes a JSON array column into the list
+func (l *StringList) Scan(value interface{}) error {
+	var data []byte
+	switch v := value.(type) {
+	case nil:
+		*l = StringList{}
+		return nil
+	case string:
+		data = []byte(v)
+	case []byte:
+		data = v
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+	if len(data) == 0 {
+		*l = StringList{}
+		return nil
+	}
+	return json.Unmarshal(data, (*[]string)(l))
+}
+
+// Contains reports whether the list holds value
+func (l StringList) Contains(value string) bool {
+	for _, item := range l {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeDietaryTags lowercases, dedupes and sorts tags, rejecting any outside DietaryTags
+func NormalizeDietaryTags(tags []string) (StringList, error) {
+	return normalizeVocabulary(tags, DietaryTags, "dietary tag")
+}
+
+// NormalizeAllergens lowercases, dedupes and sorts allergens, rejecting any outside Allergens
+func NormalizeAllergens(allergens []string) (StringList, error) {
+	return normalizeVocabulary(allergens, Allergens, "allergen")
+}
+
+func normalizeVocabulary(values, vocabulary []string, kind string) (StringList, error) {
+	seen := make(map[string]bool, len(values))
+	normalized := StringList{}
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "" || seen[value] {
+			continue
+		}
+		if !StringList(vocabulary).Contains(value) {
+			return nil, fmt.Errorf("unknown %s %q", kind, value)
+		}
+		seen[value] = true
+		normalized = append(normalized, value)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
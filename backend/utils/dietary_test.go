@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeDietaryTags(t *testing.T) {
+	got, err := NormalizeDietaryTags([]string{" Vegan", "gluten-free", "vegan", ""})
+	if err != nil {
+		t.Fatalf("NormalizeDietaryTags returned error: %v", err)
+	}
+	want := StringList{"gluten-free", "vegan"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("NormalizeDietaryTags = %v, want %v", got, want)
+	}
+
+	if _, err := NormalizeDietaryTags([]string{"paleo"}); err == nil {
+		t.Fatal("expected unknown dietary tag to be rejected")
+	}
+	if _, err := NormalizeAllergens([]string{"vegan"}); err == nil {
+		t.Fatal("expected dietary tag to be rejected as an allergen")
+	}
+}
+
+func TestStringListRoundTrip(t *testing.T) {
+	value, err := StringList{"milk", "eggs"}.Value()
+	if err != nil {
+		t.Fatalf("Value returned error: %v", err)
+	}
+	if value != `["milk","eggs"]` {
+		t.Fatalf("Value = %v", value)
+	}
+
+	var scanned StringList
+	if err := scanned.Scan([]byte(`["milk","eggs"]`)); err != nil {
+		t.Fatalf("Scan returned error: %v", err)
+	}
+	if !reflect.DeepEqual(scanned, StringList{"milk", "eggs"}) {
+		t.Fatalf("Scan = %v", scanned)
+	}
+
+	if err := scanned.Scan(nil); err != nil || len(scanned) != 0 {
+		t.Fatalf("Scan(nil) = %v, %v; want empty list", scanned, err)
+	}
+	if value, _ := StringList(nil).Value(); value != "[]" {
+		t.Fatalf("nil Value = %v, want []", value)
+	}
+}
@@ -0,0 +1,228 @@
+package utils
+
+import (
+	"order-system/constants"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DefaultLanguage is the language handlers write error messages in, served when the client
+// prefers none of the supported languages
+const DefaultLanguage = "en"
+
+// supportedLanguages lists the languages error messages are available in. The default comes
+// first so that a missing or wildcard Accept-Language picks it.
+var supportedLanguages = []string{DefaultLanguage, "es", "fr", "de"}
+
+// errorTranslations holds the translated message of each error code by language. A code covers
+// several failures, so its translation is general where the English message is specific, e.g.
+// every INVALID_INPUT failure reads "Datos de entrada no válidos" in Spanish.
+var errorTranslations = map[string]map[string]string{
+	constants.ErrCodeInvalidInput: {
+		"es": "Datos de entrada no válidos",
+		"fr": "Données saisies invalides",
+		"de": "Ungültige Eingabe",
+	},
+	constants.ErrCodeInvalidQuery: {
+		"es": "Parámetros de consulta no válidos",
+		"fr": "Paramètres de requête invalides",
+		"de": "Ungültige Abfrageparameter",
+	},
+	constants.ErrCodeUnauthorized: {
+		"es": "Autenticación requerida o token no válido",
+		"fr": "Authentification requise ou jeton invalide",
+		"de": "Anmeldung erforderlich oder Token ungültig",
+	},
+	constants.ErrCodeInvalidCredentials: {
+		"es": "Usuario o contraseña incorrectos",
+		"fr": "Nom d'utilisateur ou mot de passe incorrect",
+		"de": "Benutzername oder Passwort ist falsch",
+	},
+	constants.ErrCodeAccountLocked: {
+		"es": "Demasiados intentos fallidos. La cuenta está bloqueada temporalmente.",
+		"fr": "Trop de tentatives échouées. Le compte est temporairement verrouillé.",
+		"de": "Zu viele fehlgeschlagene Anmeldeversuche. Das Konto ist vorübergehend gesperrt.",
+	},
+	constants.ErrCodeInvalidVerificationCode: {
+		"es": "Código de verificación incorrecto o caducado",
+		"fr": "Code de vérification incorrect ou expiré",
+		"de": "Der Bestätigungscode ist falsch oder abgelaufen",
+	},
+	constants.ErrCodeForbidden: {
+		"es": "No tiene permiso para realizar esta acción",
+		"fr": "Vous n'avez pas l'autorisation d'effectuer cette action",
+		"de": "Sie haben keine Berechtigung für diese Aktion",
+	},
+	constants.ErrCodeRateLimited: {
+		"es": "Demasiadas solicitudes. Inténtelo de nuevo más tarde.",
+		"fr": "Trop de requêtes. Veuillez réessayer plus tard.",
+		"de": "Zu viele Anfragen. Bitte versuchen Sie es später erneut.",
+	},
+	constants.ErrCodeRestaurantNotFound: {
+		"es": "Restaurante no encontrado",
+		"fr": "Restaurant introuvable",
+		"de": "Restaurant nicht gefunden",
+	},
+	constants.ErrCodeTableNotFound: {
+		"es": "Mesa no encontrada",
+		"fr": "Table introuvable",
+		"de": "Tisch nicht gefunden",
+	},
+	constants.ErrCodeMenuItemNotFound: {
+		"es": "Plato no encontrado",
+		"fr": "Article du menu introuvable",
+		"de": "Menüeintrag nicht gefunden",
+	},
+	constants.ErrCodeCategoryNotFound: {
+		"es": "Categoría no encontrada",
+		"fr": "Catégorie introuvable",
+		"de": "Kategorie nicht gefunden",
+	},
+	constants.ErrCodeOrderNotFound: {
+		"es": "Pedido no encontrado",
+		"fr": "Commande introuvable",
+		"de": "Bestellung nicht gefunden",
+	},
+	constants.ErrCodeOrderItemNotFound: {
+		"es": "Artículo del pedido no encontrado",
+		"fr": "Article de commande introuvable",
+		"de": "Bestellposition nicht gefunden",
+	},
+	constants.ErrCodeOrderGroupNotFound: {
+		"es": "Grupo de pedidos no encontrado",
+		"fr": "Groupe de commandes introuvable",
+		"de": "Bestellgruppe nicht gefunden",
+	},
+	constants.ErrCodeUserNotFound: {
+		"es": "Usuario no encontrado",
+		"fr": "Utilisateur introuvable",
+		"de": "Benutzer nicht gefunden",
+	},
+	constants.ErrCodeDuplicateValue: {
+		"es": "Ya existe un registro con los mismos valores",
+		"fr": "Un enregistrement avec les mêmes valeurs existe déjà",
+		"de": "Ein Datensatz mit denselben Werten existiert bereits",
+	},
+	constants.ErrCodeUsernameTaken: {
+		"es": "El nombre de usuario ya está en uso",
+		"fr": "Ce nom d'utilisateur est déjà pris",
+		"de": "Der Benutzername ist bereits vergeben",
+	},
+	constants.ErrCodeEmailTaken: {
+		"es": "El correo electrónico ya está registrado",
+		"fr": "Cette adresse e-mail est déjà enregistrée",
+		"de": "Die E-Mail-Adresse ist bereits registriert",
+	},
+	constants.ErrCodeSKUInUse: {
+		"es": "El SKU ya está en uso",
+		"fr": "Ce SKU est déjà utilisé",
+		"de": "Die SKU wird bereits verwendet",
+	},
+	constants.ErrCodeSettingsExist: {
+		"es": "La configuración de este restaurante ya existe",
+		"fr": "Les paramètres de ce restaurant existent déjà",
+		"de": "Die Einstellungen für dieses Restaurant existieren bereits",
+	},
+	constants.ErrCodeCategoryExists: {
+		"es": "La categoría ya existe",
+		"fr": "La catégorie existe déjà",
+		"de": "Die Kategorie existiert bereits",
+	},
+	constants.ErrCodeVersionConflict: {
+		"es": "El elemento cambió desde que se cargó; vuelva a cargarlo e inténtelo de nuevo",
+		"fr": "L'élément a été modifié depuis son chargement ; rechargez-le et réessayez",
+		"de": "Der Eintrag wurde seit dem Laden geändert; bitte neu laden und erneut versuchen",
+	},
+	constants.ErrCodeMenuItemInUse: {
+		"es": "El plato se usa en pedidos activos",
+		"fr": "L'article est utilisé par des commandes en cours",
+		"de": "Der Menüeintrag wird in offenen Bestellungen verwendet",
+	},
+	constants.ErrCodeLastAdmin: {
+		"es": "No se puede quitar el último administrador",
+		"fr": "Impossible de retirer le dernier administrateur",
+		"de": "Der letzte Administrator kann nicht entfernt werden",
+	},
+	constants.ErrCodeInsufficientStock: {
+		"es": "Existencias insuficientes",
+		"fr": "Stock insuffisant",
+		"de": "Nicht genügend Bestand",
+	},
+	constants.ErrCodeNothingToReorder: {
+		"es": "Ninguno de los platos del pedido anterior está disponible",
+		"fr": "Aucun article de la commande précédente n'est disponible",
+		"de": "Keiner der Artikel der früheren Bestellung ist verfügbar",
+	},
+	constants.ErrCodeBelowMinimumOrder: {
+		"es": "El pedido no alcanza el importe mínimo",
+		"fr": "La commande n'atteint pas le montant minimum",
+		"de": "Die Bestellung erreicht nicht den Mindestbestellwert",
+	},
+	constants.ErrCodeKitchenBusy: {
+		"es": "La cocina está a plena capacidad; inténtalo de nuevo en unos minutos",
+		"fr": "La cuisine est à pleine capacité ; réessayez dans quelques minutes",
+		"de": "Die Küche ist ausgelastet; bitte in ein paar Minuten erneut versuchen",
+	},
+	constants.ErrCodeFeaturedLimitReached: {
+		"es": "Se alcanzó el número máximo de platos destacados",
+		"fr": "Le nombre maximal d'articles mis en avant est atteint",
+		"de": "Die maximale Anzahl hervorgehobener Menüeinträge ist erreicht",
+	},
+	constants.ErrCodeOrderNotOpen: {
+		"es": "El pedido ya no está abierto",
+		"fr": "La commande n'est plus ouverte",
+		"de": "Die Bestellung ist nicht mehr offen",
+	},
+	constants.ErrCodeOrderAlreadyMerged: {
+		"es": "El pedido ya se ha fusionado",
+		"fr": "La commande a déjà été fusionnée",
+		"de": "Die Bestellung wurde bereits zusammengeführt",
+	},
+	constants.ErrCodePaymentExceedsBalance: {
+		"es": "El pago supera el saldo pendiente",
+		"fr": "Le paiement dépasse le solde restant",
+		"de": "Die Zahlung übersteigt den offenen Betrag",
+	},
+	constants.ErrCodeInvalidStatusTransition: {
+		"es": "El pedido no puede pasar a ese estado",
+		"fr": "La commande ne peut pas passer à ce statut",
+		"de": "Die Bestellung kann nicht in diesen Status wechseln",
+	},
+	constants.ErrCodeInternal: {
+		"es": "Error interno del servidor",
+		"fr": "Erreur interne du serveur",
+		"de": "Interner Serverfehler",
+	},
+	constants.ErrCodeRequestTimeout: {
+		"es": "La solicitud ha excedido el tiempo de espera",
+		"fr": "Le délai de la requête a expiré",
+		"de": "Zeitüberschreitung der Anfrage",
+	},
+	constants.ErrCodePayloadTooLarge: {
+		"es": "El cuerpo de la solicitud es demasiado grande",
+		"fr": "Le corps de la requête est trop volumineux",
+		"de": "Der Anfragetext ist zu groß",
+	},
+}
+
+// PreferredLanguage returns the supported language the request's Accept-Language ranks highest,
+// matching regional variants such as es-MX to their language, or DefaultLanguage
+func PreferredLanguage(c *fiber.Ctx) string {
+	if language := c.AcceptsLanguages(supportedLanguages...); language != "" {
+		return language
+	}
+	return DefaultLanguage
+}
+
+// LocalizeError returns the message for an error code in the request's preferred language. In the
+// default language, or when the code has no translation, the handler's own message is kept.
+func LocalizeError(c *fiber.Ctx, code string, message string) string {
+	language := PreferredLanguage(c)
+	if language == DefaultLanguage {
+		return message
+	}
+	if translated, ok := errorTranslations[code][language]; ok {
+		return translated
+	}
+	return message
+}
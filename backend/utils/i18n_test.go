@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"order-system/constants"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendErrorLocalizesMessage(t *testing.T) {
+	app := fiber.New()
+	app.Get("/table", func(c *fiber.Ctx) error {
+		return SendError(c, fiber.StatusNotFound, constants.ErrCodeTableNotFound, "Table not found")
+	})
+	app.Get("/untranslated", func(c *fiber.Ctx) error {
+		return SendError(c, fiber.StatusBadRequest, "SOMETHING_NEW", "Something went wrong")
+	})
+
+	send := func(path, acceptLanguage string) (fiber.Map, string) {
+		req := httptest.NewRequest("GET", path, nil)
+		if acceptLanguage != "" {
+			req.Header.Set(fiber.HeaderAcceptLanguage, acceptLanguage)
+		}
+		resp, err := app.Test(req)
+		assert.NoError(t, err)
+		assert.Equal(t, fiber.HeaderAcceptLanguage, resp.Header.Get(fiber.HeaderVary))
+		var body fiber.Map
+		assert.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+		return body, body["error"].(string)
+	}
+
+	body, message := send("/table", "")
+	assert.Equal(t, "Table not found", message)
+	assert.Equal(t, constants.ErrCodeTableNotFound, body["code"])
+
+	_, message = send("/table", "es-MX,es;q=0.9,en;q=0.8")
+	assert.Equal(t, "Mesa no encontrada", message)
+
+	// The highest-ranked supported language wins
+	_, message = send("/table", "ja, fr;q=0.5, de;q=0.7")
+	assert.Equal(t, "Tisch nicht gefunden", message)
+
+	_, message = send("/table", "ja")
+	assert.Equal(t, "Table not found", message)
+
+	_, message = send("/untranslated", "fr")
+	assert.Equal(t, "Something went wrong", message)
+}
@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/logger"
+)
+
+// redactedValue replaces sensitive values in logs
+const redactedValue = "[REDACTED]"
+
+// sensitiveHeaders are request headers whose values must never be logged
+var sensitiveHeaders = map[string]struct{}{
+	"authorization": {},
+	"cookie":        {},
+}
+
+// sensitiveQueryParams are query parameters that carry credentials or personal data
+var sensitiveQueryParams = map[string]struct{}{
+	"token":         {},
+	"access_token":  {},
+	"refresh_token": {},
+	"code":          {}, // guest order history verification code
+	"phone":         {}, // guest order history phone number, personal data
+}
+
+// RedactToken returns a short, non-reversible fingerprint of a token that is safe to log
+func RedactToken(token string) string {
+	if token == "" {
+		return "<none>"
+	}
+	sum := sha256.Sum256([]byte(token))
+	return "sha256:" + hex.EncodeToString(sum[:4])
+}
+
+// RedactQueryString replaces the values of credential-carrying parameters in a raw query string
+func RedactQueryString(query string) string {
+	if query == "" {
+		return query
+	}
+
+	params := strings.Split(query, "&")
+	for i, param := range params {
+		key, _, hasValue := strings.Cut(param, "=")
+		if _, sensitive := sensitiveQueryParams[strings.ToLower(key)]; sensitive && hasValue {
+			params[i] = key + "=" + redactedValue
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// RedactURL redacts credential-carrying query parameters from a request URI
+func RedactURL(rawURL string) string {
+	path, query, hasQuery := strings.Cut(rawURL, "?")
+	if !hasQuery {
+		return rawURL
+	}
+	return path + "?" + RedactQueryString(query)
+}
+
+// SanitizedLoggerTags overrides the request logger tags that could leak credentials,
+// so cookies, Authorization headers and token query parameters are redacted whatever the log format.
+func SanitizedLoggerTags() map[string]logger.LogFunc {
+	return map[string]logger.LogFunc{
+		logger.TagURL: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+			return output.WriteString(RedactURL(c.OriginalURL()))
+		},
+		logger.TagQueryStringParams: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+			return output.WriteString(RedactQueryString(c.Request().URI().QueryArgs().String()))
+		},
+		logger.TagQuery: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+			if _, sensitive := sensitiveQueryParams[strings.ToLower(extraParam)]; sensitive {
+				return output.WriteString(redactedValue)
+			}
+			return output.WriteString(c.Query(extraParam))
+		},
+		logger.TagReqHeaders: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+			reqHeaders := make([]string, 0)
+			for k, v := range c.GetReqHeaders() {
+				if _, sensitive := sensitiveHeaders[strings.ToLower(k)]; sensitive {
+					reqHeaders = append(reqHeaders, k+"="+redactedValue)
+					continue
+				}
+				reqHeaders = append(reqHeaders, k+"="+strings.Join(v, ","))
+			}
+			return output.WriteString(strings.Join(reqHeaders, "&"))
+		},
+		logger.TagReqHeader: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+			if _, sensitive := sensitiveHeaders[strings.ToLower(extraParam)]; sensitive {
+				return output.WriteString(redactedValue)
+			}
+			return output.WriteString(c.Get(extraParam))
+		},
+		logger.TagCookie: func(output logger.Buffer, c *fiber.Ctx, data *logger.Data, extraParam string) (int, error) {
+			return output.WriteString(redactedValue)
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactToken(t *testing.T) {
+	token := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.secret.signature"
+	redacted := RedactToken(token)
+	if strings.Contains(redacted, "secret") || strings.Contains(redacted, "eyJ") {
+		t.Fatalf("redacted token leaks the original value: %s", redacted)
+	}
+	if redacted != RedactToken(token) {
+		t.Fatal("expected the same token to produce the same fingerprint")
+	}
+	if RedactToken("") != "<none>" {
+		t.Fatalf("expected empty token placeholder, got %s", RedactToken(""))
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"/ws/orders?token=abc.def.ghi", "/ws/orders?token=[REDACTED]"},
+		{"/api/x?page=2&access_token=abc&sort=asc", "/api/x?page=2&access_token=[REDACTED]&sort=asc"},
+		{"/api/x?page=2", "/api/x?page=2"},
+		{"/health", "/health"},
+	}
+
+	for _, tt := range tests {
+		if got := RedactURL(tt.in); got != tt.want {
+			t.Fatalf("RedactURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
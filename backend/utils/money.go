@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// RoundMoney rounds an amount half-up to whole cents
+func RoundMoney(amount float64) float64 {
+	cents := amount * 100
+	// Nudge by a tiny epsilon so values like 1.005 (stored as 1.00499...) round as written
+	return math.Round(cents+math.Copysign(1e-6, cents)) / 100
+}
+
+// Money is a monetary amount stored as integer cents.
+// It marshals to a decimal string in JSON and accepts either a number or a string on input.
+type Money int64
+
+// MoneyFromFloat converts a decimal amount to cents, rounding half-up
+func MoneyFromFloat(amount float64) Money {
+	return Money(math.Round(RoundMoney(amount) * 100))
+}
+
+// Float64 returns the amount as a decimal value
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// String formats the amount as a decimal string with two fraction digits
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON encodes the amount as a decimal string, e.g. "12.50"
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + m.String() + `"`), nil
+}
+
+// UnmarshalJSON decodes a decimal number or string into cents
+func (m *Money) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(strings.TrimSpace(string(data)), `"`)
+	if raw == "" || raw == "null" {
+		*m = 0
+		return nil
+	}
+
+	amount, err := ParseMoney(raw)
+	if err != nil {
+		return err
+	}
+	*m = amount
+	return nil
+}
+
+// ParseMoney parses a decimal amount such as "12.50" into cents
+func ParseMoney(raw string) (Money, error) {
+	amount, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		return 0, fmt.Errorf("invalid money amount %q", raw)
+	}
+	return MoneyFromFloat(amount), nil
+}
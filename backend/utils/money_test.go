@@ -0,0 +1,83 @@
+package utils
+
+import "testing"
+
+func TestRoundMoney(t *testing.T) {
+	tests := []struct {
+		name   string
+		amount float64
+		want   float64
+	}{
+		{"already rounded", 12.5, 12.5},
+		{"float drift on sum", 0.1 + 0.2, 0.3},
+		{"float drift on multiply", 19.99 * 3, 59.97},
+		{"repeated accumulation", 6.66 + 6.66 + 6.67, 19.99},
+		{"half cent rounds up", 1.005, 1.01},
+		{"half cent stored below", 2.675, 2.68},
+		{"below half rounds down", 4.994, 4.99},
+		{"zero", 0, 0},
+		{"negative half cent", -1.005, -1.01},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RoundMoney(tt.amount); got != tt.want {
+				t.Fatalf("RoundMoney(%v) = %v, want %v", tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		cents Money
+		json  string
+	}{
+		{"number", `12.5`, 1250, `"12.50"`},
+		{"string", `"19.99"`, 1999, `"19.99"`},
+		{"float drift", `1.005`, 101, `"1.01"`},
+		{"whole number", `15000`, 1500000, `"15000.00"`},
+		{"negative", `"-0.07"`, -7, `"-0.07"`},
+		{"null", `null`, 0, `"0.00"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var m Money
+			if err := m.UnmarshalJSON([]byte(tt.input)); err != nil {
+				t.Fatalf("UnmarshalJSON(%s) returned error: %v", tt.input, err)
+			}
+			if m != tt.cents {
+				t.Fatalf("UnmarshalJSON(%s) = %d cents, want %d", tt.input, m, tt.cents)
+			}
+
+			out, err := m.MarshalJSON()
+			if err != nil {
+				t.Fatalf("MarshalJSON returned error: %v", err)
+			}
+			if string(out) != tt.json {
+				t.Fatalf("MarshalJSON = %s, want %s", out, tt.json)
+			}
+		})
+	}
+}
+
+func TestMoneyUnmarshalInvalid(t *testing.T) {
+	var m Money
+	if err := m.UnmarshalJSON([]byte(`"abc"`)); err == nil {
+		t.Fatal("expected error for non-numeric amount")
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	if m, err := ParseMoney(" 12.5 "); err != nil || m != 1250 {
+		t.Fatalf("ParseMoney = %d, %v; want 1250", m, err)
+	}
+	for _, raw := range []string{"", "abc", "NaN", "Inf"} {
+		if _, err := ParseMoney(raw); err == nil {
+			t.Fatalf("expected error for %q", raw)
+		}
+	}
+}
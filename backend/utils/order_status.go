@@ -1,6 +1,9 @@
 package utils
 
-import "order-system/constants"
+import (
+	"order-system/constants"
+	"slices"
+)
 
 // MapInternalStatusToFrontend maps internal order statuses to simplified frontend statuses
 func MapInternalStatusToFrontend(internalStatus string) string {
@@ -8,6 +11,8 @@ func MapInternalStatusToFrontend(internalStatus string) string {
 	case constants.OrderStatusPending, constants.OrderStatusConfirmed, 
 	     constants.OrderStatusPreparing, constants.OrderStatusReady:
 		return constants.FrontendOrderStatusActive
+	case constants.OrderStatusOutForDelivery:
+		return constants.FrontendOrderStatusOutForDelivery
 	case constants.OrderStatusDelivered:
 		return constants.FrontendOrderStatusDelivered
 	case constants.OrderStatusCompleted:
@@ -22,6 +27,8 @@ func MapFrontendStatusToInternal(frontendStatus string) string {
 	switch frontendStatus {
 	case constants.FrontendOrderStatusActive:
 		return constants.OrderStatusPending // Default internal status for active orders
+	case constants.FrontendOrderStatusOutForDelivery:
+		return constants.OrderStatusOutForDelivery
 	case constants.FrontendOrderStatusDelivered:
 		return constants.OrderStatusDelivered
 	case constants.FrontendOrderStatusPaid:
@@ -29,4 +36,16 @@ func MapFrontendStatusToInternal(frontendStatus string) string {
 	default:
 		return frontendStatus // Return original if no mapping exists
 	}
-}
\ No newline at end of file
+}
+
+// CanTransitionOrderStatus reports whether an order of orderType may move from one internal status
+// to another along constants.OrderStatusTransitions. Keeping the current status is always allowed.
+func CanTransitionOrderStatus(orderType string, from string, to string) bool {
+	if from == to {
+		return true
+	}
+	if to == constants.OrderStatusOutForDelivery && orderType != constants.OrderTypeDelivery {
+		return false
+	}
+	return slices.Contains(constants.OrderStatusTransitions[from], to)
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"order-system/constants"
+	"testing"
+)
+
+func TestMapInternalStatusToFrontend(t *testing.T) {
+	for internal, frontend := range map[string]string{
+		constants.OrderStatusPreparing:      constants.FrontendOrderStatusActive,
+		constants.OrderStatusOutForDelivery: constants.FrontendOrderStatusOutForDelivery,
+		constants.OrderStatusDelivered:      constants.FrontendOrderStatusDelivered,
+		constants.OrderStatusCompleted:      constants.FrontendOrderStatusPaid,
+		constants.OrderStatusCancelled:      constants.OrderStatusCancelled,
+	} {
+		if got := MapInternalStatusToFrontend(internal); got != frontend {
+			t.Errorf("expected %s to map to %s, got %s", internal, frontend, got)
+		}
+		if internal != constants.OrderStatusPreparing && MapFrontendStatusToInternal(frontend) != internal {
+			t.Errorf("expected %s to map back to %s", frontend, internal)
+		}
+	}
+}
+
+func TestCanTransitionOrderStatus(t *testing.T) {
+	for _, tc := range []struct {
+		orderType string
+		from, to  string
+		allowed   bool
+	}{
+		{constants.OrderTypeDineIn, constants.OrderStatusPending, constants.OrderStatusDelivered, true},
+		{constants.OrderTypeDineIn, constants.OrderStatusDelivered, constants.OrderStatusCompleted, true},
+		{constants.OrderTypeDineIn, constants.OrderStatusReady, constants.OrderStatusReady, true},
+		{constants.OrderTypeDineIn, constants.OrderStatusDelivered, constants.OrderStatusPending, false},
+		{constants.OrderTypeDineIn, constants.OrderStatusCompleted, constants.OrderStatusCancelled, false},
+		{constants.OrderTypeDineIn, constants.OrderStatusCancelled, constants.OrderStatusPending, false},
+		{constants.OrderTypeTakeaway, constants.OrderStatusReady, constants.OrderStatusOutForDelivery, false},
+		{constants.OrderTypeDelivery, constants.OrderStatusReady, constants.OrderStatusOutForDelivery, true},
+		{constants.OrderTypeDelivery, constants.OrderStatusOutForDelivery, constants.OrderStatusDelivered, true},
+		{constants.OrderTypeDelivery, constants.OrderStatusOutForDelivery, constants.OrderStatusReady, false},
+	} {
+		if got := CanTransitionOrderStatus(tc.orderType, tc.from, tc.to); got != tc.allowed {
+			t.Errorf("%s order from %s to %s: expected allowed=%v, got %v", tc.orderType, tc.from, tc.to, tc.allowed, got)
+		}
+	}
+}
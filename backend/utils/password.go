@@ -0,0 +1,135 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// PasswordHasher hashes passwords and verifies them against hashes it produced
+type PasswordHasher interface {
+	Hash(plain string) (string, error)
+	Verify(hash, plain string) bool
+}
+
+// bcryptHasher hashes with bcrypt at the configured BCRYPT_COST
+type bcryptHasher struct{}
+
+// bcryptCost returns the work factor from BCRYPT_COST, falling back to bcrypt.DefaultCost
+// when it is unset or outside bcrypt's supported range
+func bcryptCost() int {
+	value := os.Getenv("BCRYPT_COST")
+	if value == "" {
+		return bcrypt.DefaultCost
+	}
+	cost, err := strconv.Atoi(value)
+	if err != nil || cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		log.Printf("Invalid BCRYPT_COST value %q (must be %d-%d), using default %d", value, bcrypt.MinCost, bcrypt.MaxCost, bcrypt.DefaultCost)
+		return bcrypt.DefaultCost
+	}
+	return cost
+}
+
+func (bcryptHasher) Hash(plain string) (string, error) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte(plain), bcryptCost())
+	if err != nil {
+		return "", err
+	}
+	return string(hashed), nil
+}
+
+func (bcryptHasher) Verify(hash, plain string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(plain)) == nil
+}
+
+// argon2idPrefix identifies hashes in the PHC string format produced by argon2idHasher
+const argon2idPrefix = "$argon2id$"
+
+// argon2idHasher hashes with argon2id, encoding parameters and salt alongside the key
+// so hashes stay verifiable if the defaults change
+type argon2idHasher struct {
+	time    uint32
+	memory  uint32 // KiB
+	threads uint8
+	keyLen  uint32
+	saltLen uint32
+}
+
+// defaultArgon2idHasher uses the RFC 9106 second recommended parameter set (64 MiB, t=3)
+var defaultArgon2idHasher = argon2idHasher{time: 3, memory: 64 * 1024, threads: 4, keyLen: 32, saltLen: 16}
+
+func (h argon2idHasher) Hash(plain string) (string, error) {
+	salt := make([]byte, h.saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(plain), salt, h.time, h.memory, h.threads, h.keyLen)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, h.memory, h.time, h.threads,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func (argon2idHasher) Verify(hash, plain string) bool {
+	// $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil || time == 0 || threads == 0 {
+		return false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return false
+	}
+
+	candidate := argon2.IDKey([]byte(plain), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, candidate) == 1
+}
+
+// passwordHasher returns the hasher for new passwords, selected by PASSWORD_HASH_ALGORITHM
+// ("bcrypt" by default, or "argon2id")
+func passwordHasher() PasswordHasher {
+	switch algorithm := strings.ToLower(os.Getenv("PASSWORD_HASH_ALGORITHM")); algorithm {
+	case "", "bcrypt":
+		return bcryptHasher{}
+	case "argon2id":
+		return defaultArgon2idHasher
+	default:
+		log.Printf("Unknown PASSWORD_HASH_ALGORITHM %q, using bcrypt", algorithm)
+		return bcryptHasher{}
+	}
+}
+
+// HashPassword hashes a plain-text password with the configured algorithm
+func HashPassword(plain string) (string, error) {
+	return passwordHasher().Hash(plain)
+}
+
+// CheckPassword reports whether plain matches the stored password hash. The algorithm is
+// detected from the hash, so existing hashes keep validating after PASSWORD_HASH_ALGORITHM changes.
+func CheckPassword(hash, plain string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		return defaultArgon2idHasher.Verify(hash, plain)
+	}
+	return bcryptHasher{}.Verify(hash, plain)
+}
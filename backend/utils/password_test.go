@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestHashPasswordUsesConfiguredCost(t *testing.T) {
+	t.Setenv("BCRYPT_COST", "5")
+
+	hashed, err := HashPassword("password123")
+	if err != nil {
+		t.Fatalf("HashPassword returned error: %v", err)
+	}
+	cost, err := bcrypt.Cost([]byte(hashed))
+	if err != nil {
+		t.Fatalf("failed to read cost: %v", err)
+	}
+	if cost != 5 {
+		t.Fatalf("expected cost 5, got %d", cost)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(hashed), []byte("password123")) != nil {
+		t.Fatal("expected hash to match the password")
+	}
+}
+
+func TestBcryptCostFallsBackOnInvalidValue(t *testing.T) {
+	for _, value := range []string{"", "3", "32", "high"} {
+		t.Setenv("BCRYPT_COST", value)
+		if cost := bcryptCost(); cost != bcrypt.DefaultCost {
+			t.Fatalf("BCRYPT_COST=%q: expected default cost, got %d", value, cost)
+		}
+	}
+}
+
+func TestCheckPassword(t *testing.T) {
+	t.Setenv("BCRYPT_COST", "4")
+
+	hashed, err := HashPassword("password123")
+	if err != nil {
+		t.Fatalf("HashPassword returned error: %v", err)
+	}
+	if !CheckPassword(hashed, "password123") {
+		t.Fatal("expected the correct password to match")
+	}
+	if CheckPassword(hashed, "wrong-password") {
+		t.Fatal("expected a wrong password to be rejected")
+	}
+	if CheckPassword("not-a-hash", "password123") {
+		t.Fatal("expected a malformed hash to be rejected")
+	}
+}
+
+func TestArgon2idHashAndVerify(t *testing.T) {
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "argon2id")
+
+	hashed, err := HashPassword("password123")
+	if err != nil {
+		t.Fatalf("HashPassword returned error: %v", err)
+	}
+	if !strings.HasPrefix(hashed, "$argon2id$v=19$m=65536,t=3,p=4$") {
+		t.Fatalf("unexpected argon2id hash format: %s", hashed)
+	}
+	if !CheckPassword(hashed, "password123") {
+		t.Fatal("expected the correct password to match")
+	}
+	if CheckPassword(hashed, "wrong-password") {
+		t.Fatal("expected a wrong password to be rejected")
+	}
+}
+
+func TestCheckPasswordAcceptsBothFormatsAfterSwitch(t *testing.T) {
+	t.Setenv("BCRYPT_COST", "4")
+	bcryptHash, err := HashPassword("password123")
+	if err != nil {
+		t.Fatalf("HashPassword returned error: %v", err)
+	}
+
+	// Switching algorithms must not lock out users with existing bcrypt hashes
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "argon2id")
+	argonHash, err := HashPassword("password123")
+	if err != nil {
+		t.Fatalf("HashPassword returned error: %v", err)
+	}
+
+	if !CheckPassword(bcryptHash, "password123") || !CheckPassword(argonHash, "password123") {
+		t.Fatal("expected both bcrypt and argon2id hashes to validate")
+	}
+}
+
+func TestArgon2idVerifyRejectsMalformedHash(t *testing.T) {
+	malformed := []string{
+		"$argon2id$",
+		"$argon2id$v=19$m=65536,t=3,p=4$c2FsdA",
+		"$argon2id$v=18$m=65536,t=3,p=4$c2FsdA$a2V5",
+		"$argon2id$v=19$m=65536,t=0,p=4$c2FsdA$a2V5",
+		"$argon2id$v=19$m=65536,t=3,p=4$!!$a2V5",
+	}
+	for _, hash := range malformed {
+		if CheckPassword(hash, "password123") {
+			t.Fatalf("expected %q to be rejected", hash)
+		}
+	}
+}
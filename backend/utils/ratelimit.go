@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"order-system/constants"
 	"sync"
 	"time"
 
@@ -208,11 +209,7 @@ func RateLimitMiddleware(maxRequests int, window time.Duration) fiber.Handler {
 		ip := c.IP()
 
 		if !rateLimiter.CheckRateLimit(ip, maxRequests, window) {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"success": false,
-				"data":    nil,
-				"error":   fmt.Sprintf("Rate limit exceeded. Please try again later."),
-			})
+			return SendError(c, fiber.StatusTooManyRequests, constants.ErrCodeRateLimited, fmt.Sprintf("Rate limit exceeded. Please try again later."))
 		}
 
 		return c.Next()
@@ -227,11 +224,7 @@ func LoginRateLimitMiddleware() fiber.Handler {
 
 		// Allow max 10 login attempts per minute per IP
 		if !rateLimiter.CheckRateLimit(ip, 10, time.Minute) {
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"success": false,
-				"data":    nil,
-				"error":   "Too many login attempts. Please try again later.",
-			})
+			return SendError(c, fiber.StatusTooManyRequests, constants.ErrCodeRateLimited, "Too many login attempts. Please try again later.")
 		}
 
 		return c.Next()
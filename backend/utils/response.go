@@ -7,8 +7,9 @@ import (
 // APIResponse represents the standard response format
 type APIResponse struct {
 	Success bool        `json:"success"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   interface{} `json:"error,omitempty"`
+	Data    interface{} `json:"data"`
+	Error   interface{} `json:"error"`
+	Code    string      `json:"code,omitempty"` // machine-readable error code, see constants.ErrCode*
 }
 
 // SuccessResponse returns a successful API response
@@ -20,12 +21,19 @@ func SuccessResponse(data interface{}) APIResponse {
 	}
 }
 
-// ErrorResponse returns an error API response
-func ErrorResponse(error interface{}) APIResponse {
+// ErrorResponse returns an error API response with a machine-readable code and a message
+func ErrorResponse(code string, message string) APIResponse {
+	return ErrorResponseWithData(code, message, nil)
+}
+
+// ErrorResponseWithData returns an error API response that also carries details in data,
+// such as the IDs that caused the failure
+func ErrorResponseWithData(code string, message string, data interface{}) APIResponse {
 	return APIResponse{
 		Success: false,
-		Data:    nil,
-		Error:   error,
+		Data:    data,
+		Error:   message,
+		Code:    code,
 	}
 }
 
@@ -39,9 +47,16 @@ func SendSuccessWithStatus(c *fiber.Ctx, data interface{}, statusCode int) error
 	return c.Status(statusCode).JSON(SuccessResponse(data))
 }
 
-// SendError sends an error JSON response with the provided error message
-func SendError(c *fiber.Ctx, statusCode int, error interface{}) error {
-	return c.Status(statusCode).JSON(ErrorResponse(error))
+// SendError sends an error JSON response with the provided error code and message, translating
+// the message for the request's Accept-Language
+func SendError(c *fiber.Ctx, statusCode int, code string, message string) error {
+	return SendErrorWithData(c, statusCode, code, message, nil)
+}
+
+// SendErrorWithData sends an error JSON response like SendError, with details in data
+func SendErrorWithData(c *fiber.Ctx, statusCode int, code string, message string, data interface{}) error {
+	c.Vary(fiber.HeaderAcceptLanguage)
+	return c.Status(statusCode).JSON(ErrorResponseWithData(code, LocalizeError(c, code, message), data))
 }
 
 // SendResponse sends a custom API response
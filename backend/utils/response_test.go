@@ -0,0 +1,24 @@
+package utils
+
+import (
+	"encoding/json"
+	"order-system/constants"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorResponse(t *testing.T) {
+	body, err := json.Marshal(ErrorResponse(constants.ErrCodeTableNotFound, "Table not found"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"success": false, "data": null, "error": "Table not found", "code": "TABLE_NOT_FOUND"}`, string(body))
+
+	body, err = json.Marshal(ErrorResponseWithData(constants.ErrCodeMenuItemInUse, "Items are used by active orders", map[string][]uint{"in_use_ids": {3}}))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"success": false, "data": {"in_use_ids": [3]}, "error": "Items are used by active orders", "code": "MENU_ITEM_IN_USE"}`, string(body))
+
+	// Success responses have no code
+	body, err = json.Marshal(SuccessResponse("ok"))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"success": true, "data": "ok", "error": null}`, string(body))
+}
@@ -67,6 +67,31 @@ func ValidateAccessToken(tokenString string) (jwt.MapClaims, error) {
 	return claims, nil
 }
 
+// ValidateWebSocketToken validates a token issued for the WebSocket handshake.
+// Only short-lived "websocket" tokens are accepted so access tokens never end up in URLs.
+func ValidateWebSocketToken(tokenString string) (jwt.MapClaims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(getSecretKey()), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !token.Valid {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	// Check if token type is websocket
+	if claims["type"] != "websocket" {
+		return nil, jwt.ErrSignatureInvalid
+	}
+
+	return claims, nil
+}
+
 // ValidateRefreshToken validates the refresh token
 func ValidateRefreshToken(tokenString string) (jwt.MapClaims, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
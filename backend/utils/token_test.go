@@ -0,0 +1,25 @@
+package utils
+
+import "testing"
+
+func TestValidateWebSocketTokenAcceptsOnlyWebSocketTokens(t *testing.T) {
+	wsToken, err := GenerateSecureWebSocketToken(1, "tester")
+	if err != nil {
+		t.Fatalf("failed to generate websocket token: %v", err)
+	}
+	claims, err := ValidateWebSocketToken(wsToken)
+	if err != nil {
+		t.Fatalf("expected websocket token to be valid, got %v", err)
+	}
+	if claims["username"] != "tester" {
+		t.Fatalf("expected username tester, got %v", claims["username"])
+	}
+
+	accessToken, err := GenerateSecureAccessToken(1, "tester")
+	if err != nil {
+		t.Fatalf("failed to generate access token: %v", err)
+	}
+	if _, err := ValidateWebSocketToken(accessToken); err == nil {
+		t.Fatal("expected access token to be rejected for the websocket handshake")
+	}
+}
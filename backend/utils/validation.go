@@ -11,6 +11,36 @@ func ValidateEmail(email string) bool {
 	return emailRegex.MatchString(email)
 }
 
+// phoneRegex allows an optional leading + and digits separated by spaces, dots, dashes or parentheses
+var phoneRegex = regexp.MustCompile(`^\+?[0-9 ().-]+$`)
+
+// ValidatePhone validates a phone number as customers type it, e.g. "+1 (555) 010-2030":
+// an optional leading + and 7 to 15 digits, the length limits of E.164, in at most 32 characters
+func ValidatePhone(phone string) bool {
+	if len(phone) > 32 || !phoneRegex.MatchString(phone) {
+		return false
+	}
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	return digits >= 7 && digits <= 15
+}
+
+// PhoneDigits returns the digits of a phone number, so numbers typed with different separators
+// compare equal, e.g. "+1 (555) 010-2030" and "1 555 010 2030"
+func PhoneDigits(phone string) string {
+	digits := make([]rune, 0, len(phone))
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+	return string(digits)
+}
+
 // IsValidOrderStatus checks if a status is valid
 func IsValidOrderStatus(status string) bool {
 	switch status {
@@ -18,6 +48,7 @@ func IsValidOrderStatus(status string) bool {
 	     constants.OrderStatusConfirmed,
 	     constants.OrderStatusPreparing,
 	     constants.OrderStatusReady,
+	     constants.OrderStatusOutForDelivery,
 	     constants.OrderStatusDelivered,
 	     constants.OrderStatusCompleted,
 	     constants.OrderStatusCancelled:
@@ -31,6 +62,7 @@ func IsValidOrderStatus(status string) bool {
 func IsValidFrontendOrderStatus(status string) bool {
 	switch status {
 	case constants.FrontendOrderStatusActive,
+	     constants.FrontendOrderStatusOutForDelivery,
 	     constants.FrontendOrderStatusDelivered,
 	     constants.FrontendOrderStatusPaid:
 		return true
@@ -0,0 +1,16 @@
+package utils
+
+import "testing"
+
+func TestValidatePhone(t *testing.T) {
+	for _, phone := range []string{"+1 (555) 010-2030", "0612345678", "+44 20.7946.0958", "5550102"} {
+		if !ValidatePhone(phone) {
+			t.Errorf("expected %q to be valid", phone)
+		}
+	}
+	for _, phone := range []string{"", "555-01", "+1234567890123456", "555 CALL NOW", "1+555 010 2030", "+1 555 010 2030                    "} {
+		if ValidatePhone(phone) {
+			t.Errorf("expected %q to be invalid", phone)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+// Package version holds the build information reported at /version. The values are set at
+// compile time, e.g.
+//
+//	go build -ldflags "-X order-system/version.Version=1.4.0 \
+//	  -X order-system/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X order-system/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" .
+package version
+
+import "runtime/debug"
+
+// Build information, "dev" unless injected with -ldflags -X
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildTime = "dev"
+)
+
+// Info is the build information of the running binary
+type Info struct {
+	Version   string `json:"version" example:"1.4.0"`
+	Commit    string `json:"commit" example:"3f2c1ab"`
+	BuildTime string `json:"build_time" example:"2024-03-01T12:00:00Z"`
+}
+
+// Get returns the build information. When the commit wasn't injected, the revision Go records
+// for builds inside a git checkout is used instead.
+func Get() Info {
+	info := Info{Version: Version, Commit: Commit, BuildTime: BuildTime}
+	if info.Commit != "dev" {
+		return info
+	}
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				info.Commit = setting.Value
+			}
+		}
+	}
+	return info
+}
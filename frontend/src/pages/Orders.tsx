@@ -16,9 +16,12 @@ interface OrderItem {
 
 interface Order {
   ID: number;
-  TableID: number;
+  OrderRef: string;
+  TableID: number | null;
+  OrderType: string;
   CustomerName: string;
   Status: string;
+  ScheduledFor?: string | null;
   TotalAmount: number;
   OrderItems?: OrderItem[];
   CreatedAt: string;
@@ -28,8 +31,11 @@ interface Order {
 }
 
 interface OrderEvent {
-  type: 'order_created' | 'order_updated';
+  version: number;
+  type: 'order_created' | 'order_updated' | 'order_item_updated' | 'order_deleted';
+  timestamp: string;
   order: OrderResponsePayload;
+  item?: { id: number; status: 'pending' | 'ready' };
 }
 
 const isTransactionStatus = (status?: string) =>
@@ -50,9 +56,12 @@ interface OrderItemResponse {
 
 interface OrderResponsePayload {
   id: number;
-  table_id: number;
+  order_ref?: string;
+  table_id: number | null;
+  order_type?: string;
   customer_name: string;
   status: string;
+  scheduled_for?: string | null;
   total_amount: number;
   order_items?: OrderItemResponse[];
   created_at?: string;
@@ -87,9 +96,12 @@ const normalizeOrderItem = (item: OrderItemResponse): OrderItem => ({
 
 const normalizeOrder = (order: OrderResponsePayload): Order => ({
   ID: order.id,
+  OrderRef: order.order_ref ?? '',
   TableID: order.table_id,
+  OrderType: order.order_type ?? 'dine_in',
   CustomerName: order.customer_name,
   Status: order.status,
+  ScheduledFor: order.scheduled_for ?? null,
   TotalAmount: order.total_amount,
   OrderItems: (order.order_items ?? []).map(normalizeOrderItem),
   CreatedAt: order.created_at ?? '',
@@ -257,6 +269,10 @@ export default function Orders() {
     updateOrderStatus(order.ID, order.restaurant_id, 'delivered');
   };
 
+  const handleSendOutForDelivery = (order: Order) => {
+    updateOrderStatus(order.ID, order.restaurant_id, 'out_for_delivery');
+  };
+
   const handleMarkAsPaid = (order: Order) => {
     updateOrderStatus(order.ID, order.restaurant_id, 'paid');
   };
@@ -314,20 +330,24 @@ export default function Orders() {
               {activeOrders.map((order) => (
                 <div key={order.ID} className="card" style={{ padding: '1rem', textAlign: 'left' }}>
                   <div style={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', marginBottom: '0.5rem' }}>
-                    <h3>Order #{order.ID}</h3>
+                    <h3>Order #{order.OrderRef || order.ID}</h3>
                     <span style={{
                       padding: '0.25rem 0.5rem',
                       borderRadius: '4px',
                       backgroundColor:
                         !order.Status || order.Status === 'active' ? '#d4edda' :
+                        order.Status === 'scheduled' ? '#e2e3e5' :
+                        order.Status === 'out_for_delivery' ? '#fff3cd' :
                         order.Status === 'delivered' ? '#cce5ff' :
                         '#f8d7da',
                       color:
                         !order.Status || order.Status === 'active' ? '#155724' :
+                        order.Status === 'scheduled' ? '#383d41' :
+                        order.Status === 'out_for_delivery' ? '#856404' :
                         order.Status === 'delivered' ? '#004085' :
                         '#721c24'
                     }}>
-                      {order.Status ? order.Status.charAt(0).toUpperCase() + order.Status.slice(1) : 'Unknown'}
+                      {order.Status ? (order.Status.charAt(0).toUpperCase() + order.Status.slice(1)).replace(/_/g, ' ') : 'Unknown'}
                     </span>
                   </div>
 
@@ -335,8 +355,11 @@ export default function Orders() {
                     <p><strong>Restaurant:</strong> {order.restaurant_name}</p>
                   )}
 
-                  <p><strong>Table:</strong> {order.TableID}</p>
+                  <p><strong>Table:</strong> {order.TableID ?? (order.OrderType === 'delivery' ? 'Delivery' : 'Takeaway')}</p>
                   <p><strong>Customer:</strong> {order.CustomerName}</p>
+                  {order.ScheduledFor && (
+                    <p><strong>Wanted at:</strong> {new Date(order.ScheduledFor).toLocaleString()}</p>
+                  )}
                   <p><strong>Total:</strong> {formatCurrency(order.TotalAmount)}</p>
 
                   <div style={{ marginTop: '1rem' }}>
@@ -352,7 +375,15 @@ export default function Orders() {
                   </div>
 
                   <div style={{ display: 'flex', gap: '0.5rem', marginTop: '1rem' }}>
-                    {order.Status && order.Status === 'active' ? (
+                    {order.Status === 'active' && order.OrderType === 'delivery' ? (
+                      <button
+                        className="btn"
+                        style={{ backgroundColor: '#fd7e14', padding: '0.5rem 1rem' }}
+                        onClick={() => handleSendOutForDelivery(order)}
+                      >
+                        Out for Delivery
+                      </button>
+                    ) : order.Status && (order.Status === 'active' || order.Status === 'out_for_delivery') ? (
                       <button
                         className="btn"
                         style={{ backgroundColor: '#28a745', padding: '0.5rem 1rem' }}
@@ -401,7 +432,7 @@ export default function Orders() {
               {paidOrders.map((order) => (
                 <div key={order.ID} className="card" style={{ padding: '1rem', textAlign: 'left' }}>
                   <div style={{ display: 'flex', justifyContent: 'space-between', alignItems: 'center', marginBottom: '0.5rem' }}>
-                    <h3>Order #{order.ID}</h3>
+                    <h3>Order #{order.OrderRef || order.ID}</h3>
                     {(() => {
                       const statusLabel = order.Status ? order.Status.charAt(0).toUpperCase() + order.Status.slice(1) : 'Paid';
                       const isCancelled = order.Status === 'cancelled';
@@ -422,7 +453,7 @@ export default function Orders() {
                     <p><strong>Restaurant:</strong> {order.restaurant_name}</p>
                   )}
 
-                  <p><strong>Table:</strong> {order.TableID}</p>
+                  <p><strong>Table:</strong> {order.TableID ?? (order.OrderType === 'delivery' ? 'Delivery' : 'Takeaway')}</p>
                   <p><strong>Customer:</strong> {order.CustomerName}</p>
                   <p><strong>Total:</strong> {formatCurrency(order.TotalAmount)}</p>
 
@@ -22,21 +22,23 @@ interface CartItem {
 }
 
 interface Order {
-  ID: number
-  TableID: number
-  CustomerName: string
-  Status: string
-  TotalAmount: number
-  OrderItems: OrderItem[]
-  CreatedAt: string
-  UpdatedAt: string
+  id: number
+  order_ref: string
+  table_id: number | null
+  table_number: number
+  customer_name: string
+  status: string
+  total_amount: string
+  order_items: OrderItem[]
+  created_at: string
+  updated_at: string
 }
 
 interface OrderItem {
-  ID: number
-  MenuItemID: number
-  Quantity: number
-  SpecialInstructions: string
+  id: number
+  menu_item_id: number
+  quantity: number
+  special_instructions: string
 }
 
 interface Restaurant {
@@ -58,6 +60,7 @@ export default function QRMenu() {
   const [cart, setCart] = useState<CartItem[]>([])
   const [customerName, setCustomerName] = useState('')
   const [isOrderPlaced, setIsOrderPlaced] = useState(false)
+  const [orderRef, setOrderRef] = useState('')
   const [searchTerm, setSearchTerm] = useState('')
 
   useEffect(() => {
@@ -116,7 +119,8 @@ export default function QRMenu() {
   }, [filteredMenuItems])
 
   const sortedCategories = useMemo(() => {
-    return Object.keys(groupedMenu).sort((a, b) => a.localeCompare(b))
+    // The API returns items in the restaurant's category display order
+    return Object.keys(groupedMenu)
   }, [groupedMenu])
 
   const addToCart = (item: MenuItem) => {
@@ -212,8 +216,9 @@ export default function QRMenu() {
         body: JSON.stringify(orderPayload)
       })
 
-      const response = await handleApiResponse(res)
+      const response = await handleApiResponse<Order>(res)
       if (res.ok && isResponseSuccess(response)) {
+        setOrderRef(response.data?.order_ref ?? '')
         setIsOrderPlaced(true)
         setCart([]) // Clear the cart
         alert('Order placed successfully!')
@@ -369,7 +374,10 @@ export default function QRMenu() {
             margin: '0 auto'
           }}>
             <h2 style={{ color: '#28a745', marginBottom: '1rem' }}>Thank you for your order!</h2>
-            <p style={{ marginBottom: '1.5rem' }}>Your order has been placed successfully. Please wait for your food to be prepared.</p>
+            {orderRef && (
+              <p style={{ fontSize: '2rem', fontWeight: 'bold', marginBottom: '0.5rem' }}>#{orderRef}</p>
+            )}
+            <p style={{ marginBottom: '1.5rem' }}>Your order has been placed successfully. Please wait for your food to be prepared{orderRef ? ' and listen for your order number' : ''}.</p>
             <button
               className="btn"
               onClick={() => setIsOrderPlaced(false)}
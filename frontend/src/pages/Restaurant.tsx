@@ -286,7 +286,11 @@ export default function Restaurant() {
           price: parseFloat(editingMenuForm.price),
           category: editingMenuForm.category,
           image_url: editingMenuForm.image_url,
-          quantity: parseInt(editingMenuForm.quantity)
+          // Only overwrite stock when it was edited, so orders placed meanwhile aren't undone
+          ...(parseInt(editingMenuForm.quantity) !== editingMenuItem.Quantity && {
+            quantity: parseInt(editingMenuForm.quantity),
+          }),
+          version: editingMenuItem.Version,
         }),
       })
       const response = await handleApiResponse(res)
@@ -296,6 +300,9 @@ export default function Restaurant() {
         await fetchMenuItems(showingMenuId)
         cancelEditMenuItem()
         alert('Menu item updated successfully!')
+      } else if (res.status === 409 && response.data?.current) {
+        await fetchMenuItems(showingMenuId)
+        setError('This menu item was changed while you were editing it. Please review the latest values and try again.')
       } else {
         const errorMessage = response.error || 'Failed to update menu item'
         setError(errorMessage)